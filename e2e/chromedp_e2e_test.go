@@ -3,6 +3,7 @@
 package e2e
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -37,8 +38,7 @@ func TestChromeDPLogin(t *testing.T) {
 
 	client, err := browser.NewBrowserClient(
 		cookieManager,
-		cfg.Debug,
-		cfg.TmpDir,
+		browser.ClientOptions{Debug: cfg.Debug, StateDir: cfg.TmpDir},
 	)
 	if err != nil {
 		t.Fatalf("Login failed: %v", err)
@@ -46,7 +46,7 @@ func TestChromeDPLogin(t *testing.T) {
 	defer client.Close()
 
 	// Verify login succeeded by performing a search
-	results, _, err := client.SearchContent("test", nil)
+	results, _, err := client.SearchContent(context.Background(), "test", nil)
 	if err != nil {
 		t.Fatalf("Search after login failed: %v", err)
 	}
@@ -73,8 +73,7 @@ func TestCookieRestoration(t *testing.T) {
 	// First: Create client and login (saves cookies)
 	client1, err := browser.NewBrowserClient(
 		cookieManager,
-		cfg.Debug,
-		cfg.TmpDir,
+		browser.ClientOptions{Debug: cfg.Debug, StateDir: cfg.TmpDir},
 	)
 	if err != nil {
 		t.Fatalf("First login failed: %v", err)
@@ -92,8 +91,7 @@ func TestCookieRestoration(t *testing.T) {
 	cookieManager2 := cookie.NewCookieManager(cookieDir)
 	client2, err := browser.NewBrowserClient(
 		cookieManager2,
-		cfg.Debug,
-		cfg.TmpDir,
+		browser.ClientOptions{Debug: cfg.Debug, StateDir: cfg.TmpDir},
 	)
 	if err != nil {
 		t.Fatalf("Second client creation with restored cookies failed: %v", err)
@@ -101,7 +99,7 @@ func TestCookieRestoration(t *testing.T) {
 	defer client2.Close()
 
 	// Verify authentication works with restored cookies
-	results, _, err := client2.SearchContent("Go", nil)
+	results, _, err := client2.SearchContent(context.Background(), "Go", nil)
 	if err != nil {
 		t.Fatalf("Search with restored cookies failed: %v", err)
 	}
@@ -124,7 +122,7 @@ func TestChromeDP_ReauthenticationFlow(t *testing.T) {
 	}
 
 	// Verify reauthentication succeeded
-	results, _, err := client.SearchContent("Python", nil)
+	results, _, err := client.SearchContent(context.Background(), "Python", nil)
 	if err != nil {
 		t.Fatalf("Search after reauthentication failed: %v", err)
 	}
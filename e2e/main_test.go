@@ -27,8 +27,7 @@ func TestMain(m *testing.M) {
 	// Create shared client (only once for all tests)
 	client, err := browser.NewBrowserClient(
 		cookieManager,
-		cfg.Debug,
-		cfg.TmpDir,
+		browser.ClientOptions{Debug: cfg.Debug, StateDir: cfg.TmpDir},
 	)
 	if err != nil {
 		log.Fatalf("Failed to create shared browser client: %v", err)
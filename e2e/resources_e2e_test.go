@@ -3,6 +3,7 @@
 package e2e
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -15,7 +16,7 @@ func TestMCPResource_BookDetails(t *testing.T) {
 	client := GetSharedClient()
 
 	// Get book details for test book
-	details, err := client.GetBookDetails(TestBookID)
+	details, err := client.GetBookDetails(context.Background(), TestBookID)
 	if err != nil {
 		t.Fatalf("GetBookDetails failed: %v", err)
 	}
@@ -60,7 +61,7 @@ func TestMCPResource_BookTOC(t *testing.T) {
 	client := GetSharedClient()
 
 	// Get table of contents for test book
-	toc, err := client.GetBookTOC(TestBookID)
+	toc, err := client.GetBookTOC(context.Background(), TestBookID)
 	if err != nil {
 		t.Fatalf("GetBookTOC failed: %v", err)
 	}
@@ -106,7 +107,7 @@ func TestMCPResource_BookChapter(t *testing.T) {
 	client := GetSharedClient()
 
 	// Get chapter content for test book
-	chapter, err := client.GetBookChapterContent(TestBookID, TestChapterName)
+	chapter, err := client.GetBookChapterContent(context.Background(), TestBookID, TestChapterName)
 	if err != nil {
 		t.Fatalf("GetBookChapterContent failed: %v", err)
 	}
@@ -3,6 +3,7 @@
 package e2e
 
 import (
+	"context"
 	"testing"
 
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
@@ -28,7 +29,7 @@ func TestServerWithRealAuthentication(t *testing.T) {
 	client := GetSharedClient()
 
 	// Verify authentication by performing a simple search
-	results, _, err := client.SearchContent("Go", nil)
+	results, _, err := client.SearchContent(context.Background(), "Go", nil)
 	if err != nil {
 		t.Fatalf("Search failed after authentication: %v", err)
 	}
@@ -50,8 +51,7 @@ func TestServerBrowserClientLifecycle(t *testing.T) {
 
 	client, err := browser.NewBrowserClient(
 		cookieManager,
-		cfg.Debug,
-		cfg.TmpDir,
+		browser.ClientOptions{Debug: cfg.Debug, StateDir: cfg.TmpDir},
 	)
 	if err != nil {
 		t.Fatalf("Failed to create browser client: %v", err)
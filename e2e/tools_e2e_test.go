@@ -3,9 +3,12 @@
 package e2e
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 	"time"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
 )
 
 // TestMCPTool_SearchContent tests the search_content tool with real API.
@@ -13,7 +16,7 @@ func TestMCPTool_SearchContent(t *testing.T) {
 	client := GetSharedClient()
 
 	// Test search functionality
-	results, _, err := client.SearchContent(TestSearchQuery, nil)
+	results, _, err := client.SearchContent(context.Background(), TestSearchQuery, nil)
 	if err != nil {
 		t.Fatalf("SearchContent failed: %v", err)
 	}
@@ -42,7 +45,7 @@ func TestMCPTool_SearchContent_WithOptions(t *testing.T) {
 		"languages": []string{"en"},
 	}
 
-	results, _, err := client.SearchContent("Docker containers", options)
+	results, _, err := client.SearchContent(context.Background(), "Docker containers", options)
 	if err != nil {
 		t.Fatalf("SearchContent with options failed: %v", err)
 	}
@@ -72,7 +75,7 @@ func TestMCPTool_AskQuestion_FullFlow(t *testing.T) {
 	// Use a reasonable timeout for real answer generation
 	timeout := 60 * time.Second
 
-	answer, err := client.AskQuestion("How to optimize Go performance?", timeout)
+	answer, err := client.AskQuestion(context.Background(), "How to optimize Go performance?", browser.QuestionOptions{}, timeout, nil)
 	if err != nil {
 		t.Fatalf("AskQuestion failed: %v", err)
 	}
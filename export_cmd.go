@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/cookie"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/export"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
+)
+
+// exportArgs は --export サブコマンドの引数を保持します
+type exportArgs struct {
+	productID   string
+	outputDir   string
+	concurrency int
+	force       bool
+}
+
+// parseExportArgs は --export 以降の引数を解析します
+// product_id はフラグの前後どちらにも置けます
+func parseExportArgs(args []string, output io.Writer) (*exportArgs, error) {
+	parsed := &exportArgs{}
+	fs := flag.NewFlagSet("--export", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintln(output, "使い方: orm-discovery-mcp-go --export <product_id> [--output DIR] [--concurrency N] [--force]")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&parsed.outputDir, "output", "", "出力先ディレクトリ (既定: XDG StateHome 配下の exports/<product_id>)")
+	fs.IntVar(&parsed.concurrency, "concurrency", 0, "同時に取得するチャプター数 (既定: ORM_MCP_GO_EXPORT_CONCURRENCY)")
+	fs.BoolVar(&parsed.force, "force", false, "前回エクスポート済みのチャプターも取得し直す")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return nil, errors.New("product_id を 1 つ指定してください")
+	}
+	parsed.productID = positional[0]
+	return parsed, nil
+}
+
+// runExport は書籍を Markdown ディレクトリにエクスポートします
+// 中断しても再実行すると完了済みのチャプターを飛ばして再開します
+func runExport(args []string) error {
+	parsed, err := parseExportArgs(args, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("設定の読み込みに失敗しました: %w", err)
+	}
+	if parsed.outputDir == "" {
+		parsed.outputDir = filepath.Join(cfg.Export.Dir, parsed.productID)
+	}
+	if parsed.concurrency <= 0 {
+		parsed.concurrency = cfg.Export.Concurrency
+	}
+
+	cookieManager := cookie.NewCookieManager(cfg.XDGDirs.CacheHome)
+	bc, err := browser.NewBrowserClient(cookieManager, browser.ClientOptions{
+		Debug:         cfg.Debug.Enabled,
+		StateDir:      cfg.XDGDirs.StateHome,
+		HTTP:          cfg.HTTP,
+		BookCache:     cfg.BookCache,
+		ContentStore:  browser.OpenContentStore(cfg.ContentStore),
+		FulltextIndex: fulltext.Open(cfg.FullText),
+	})
+	if err != nil {
+		return fmt.Errorf("ブラウザクライアントの初期化に失敗しました (--login でCookieを保存してください): %w", err)
+	}
+	defer bc.Close()
+
+	out := os.Stdout
+	result, err := export.Book(ctx, bc, parsed.productID, export.Options{
+		OutputDir:   parsed.outputDir,
+		Concurrency: parsed.concurrency,
+		Force:       parsed.force,
+		OnProgress: func(p export.Progress) {
+			fmt.Fprintf(out, "[%d/%d] %s\n", p.Done, p.Total, p.Chapter)
+		},
+	})
+	if result != nil {
+		printExportSummary(out, result)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return errors.New("エクスポートを中断しました。同じコマンドを再実行すると続きから再開します")
+		}
+		return err
+	}
+	return nil
+}
+
+// printExportSummary はエクスポート結果の概要を出力します
+func printExportSummary(out io.Writer, result *export.Result) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "✓ %s を %s にエクスポートしました\n", result.Title, result.OutputDir)
+	fmt.Fprintf(out, "  チャプター: %d 件取得 / %d 件再開時スキップ / 全 %d 件\n", result.Exported, result.Resumed, result.Chapters)
+	fmt.Fprintf(out, "  画像: %d 件 (失敗 %d 件)\n", result.Images, result.FailedImages)
+	for _, f := range result.Failed {
+		fmt.Fprintf(out, "  ✗ %s (%s): %s\n", f.Title, f.Href, f.Error)
+	}
+	fmt.Fprintf(out, "  索引: %s\n", result.IndexPath)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestParseExportArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    exportArgs
+		wantErr bool
+	}{
+		{name: "product_id only", args: []string{"9781492052586"}, want: exportArgs{productID: "9781492052586"}},
+		{name: "flags after product_id", args: []string{"123", "--output", "/tmp/book", "--force"}, want: exportArgs{productID: "123", outputDir: "/tmp/book", force: true}},
+		{name: "flags before product_id", args: []string{"--concurrency", "2", "123"}, want: exportArgs{productID: "123", concurrency: 2}},
+		{name: "missing product_id", args: []string{"--force"}, wantErr: true},
+		{name: "two product_ids", args: []string{"123", "456"}, wantErr: true},
+		{name: "unknown flag", args: []string{"123", "--bogus"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExportArgs(tt.args, io.Discard)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseExportArgs(%v) error = nil, want error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExportArgs(%v) error = %v", tt.args, err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseExportArgs(%v) = %+v, want %+v", tt.args, *got, tt.want)
+			}
+		})
+	}
+}
@@ -2,18 +2,36 @@ package browser
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/generated/api"
 )
 
-// DefaultFilterQuery Default question request parameters based on the provided JSON specification
+// Filter clauses of the question request. DefaultFilterQuery combines them
+// for a question across all content types.
 const (
-	DefaultFilterQuery = "(type:book OR type:video OR type:article) AND language:(\"en\" OR \"EN\" OR \"en-au\" OR \"en-gb\" OR \"en-GB\" OR \"en-us\" OR \"en-US\") AND ( NOT custom_attributes.required_p_permissions:aia ) AND ( NOT custom_attributes.required_p_permissions:cldsc ) AND ( NOT custom_attributes.required_p_permissions:cprex ) AND ( NOT custom_attributes.required_p_permissions:lvtrg ) AND ( NOT custom_attributes.required_p_permissions:ntbks ) AND ( NOT custom_attributes.required_p_permissions:scnrio )"
+	answerLanguageFilter   = "language:(\"en\" OR \"EN\" OR \"en-au\" OR \"en-gb\" OR \"en-GB\" OR \"en-us\" OR \"en-US\")"
+	answerPermissionFilter = "( NOT custom_attributes.required_p_permissions:aia ) AND ( NOT custom_attributes.required_p_permissions:cldsc ) AND ( NOT custom_attributes.required_p_permissions:cprex ) AND ( NOT custom_attributes.required_p_permissions:lvtrg ) AND ( NOT custom_attributes.required_p_permissions:ntbks ) AND ( NOT custom_attributes.required_p_permissions:scnrio )"
+
+	// DefaultFilterQuery Default question request parameters based on the provided JSON specification
+	DefaultFilterQuery = "(type:book OR type:video OR type:article) AND " + answerLanguageFilter + " AND " + answerPermissionFilter
+)
+
+// Default lengths of the excerpts returned with an answer. Upper limits are
+// left to the Answers API.
+const (
+	DefaultSnippetLength   = 500
+	DefaultHighlightLength = 200
 )
 
+// AnswerContentTypes are the content types O'Reilly Answers draws on.
+var AnswerContentTypes = []string{"book", "video", "article"}
+
 var (
 	DefaultSourceFields = []string{
 		"custom_attributes.ourn",
@@ -38,25 +56,134 @@ var (
 	}
 )
 
-// createQuestionRequest creates a question request with default parameters
-func createQuestionRequest(question string) QuestionRequest {
+// createQuestionRequest creates a question request scoped and tuned by opts
+func createQuestionRequest(question string, opts QuestionOptions) (QuestionRequest, error) {
+	if err := opts.Validate(); err != nil {
+		return QuestionRequest{}, err
+	}
+	snippetLength := DefaultSnippetLength
+	if opts.SnippetLength > 0 {
+		snippetLength = opts.SnippetLength
+	}
+	highlightLength := DefaultHighlightLength
+	if opts.HighlightLength > 0 {
+		highlightLength = opts.HighlightLength
+	}
 	return QuestionRequest{
 		Question:              question,
-		FilterQuery:           DefaultFilterQuery,
+		FilterQuery:           opts.filterQuery(),
 		SourceFields:          DefaultSourceFields,
 		RelatedResourceFields: DefaultRelatedResourceFields,
 		PipelineConfig: PipelineConfig{
-			SnippetLength:   500,
-			HighlightLength: 200,
+			SnippetLength:   snippetLength,
+			HighlightLength: highlightLength,
 		},
+	}, nil
+}
+
+// Validate reports the first invalid option.
+func (o QuestionOptions) Validate() error {
+	bookIDs := false
+	for _, id := range o.ProductIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return errors.New("product_ids must not contain empty values")
+		}
+		bookIDs = bookIDs || !isOURN(id)
+	}
+	hasBook := len(o.ContentTypes) == 0
+	for _, t := range o.ContentTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(AnswerContentTypes, t) {
+			return fmt.Errorf("invalid content type %q: use one of %s", t, strings.Join(AnswerContentTypes, ", "))
+		}
+		hasBook = hasBook || t == "book"
+	}
+	if bookIDs && !hasBook {
+		return errors.New(`product_ids without the "urn:orm:" prefix are books: add "book" to content_types or pass the ourn of the video or article`)
+	}
+	for _, p := range o.Publishers {
+		if strings.TrimSpace(p) == "" {
+			return errors.New("publishers must not contain empty values")
+		}
+	}
+	if o.SnippetLength < 0 {
+		return errors.New("snippet_length must not be negative")
+	}
+	if o.HighlightLength < 0 {
+		return errors.New("highlight_length must not be negative")
+	}
+	return nil
+}
+
+// filterQuery builds the fq of the question request. Without scoping options
+// it is DefaultFilterQuery.
+func (o QuestionOptions) filterQuery() string {
+	types := AnswerContentTypes
+	if len(o.ContentTypes) > 0 {
+		types = make([]string, len(o.ContentTypes))
+		for i, t := range o.ContentTypes {
+			types[i] = strings.ToLower(strings.TrimSpace(t))
+		}
+	}
+	typeClauses := make([]string, len(types))
+	for i, t := range types {
+		typeClauses[i] = "type:" + t
+	}
+	clauses := []string{"(" + strings.Join(typeClauses, " OR ") + ")"}
+
+	if len(o.ProductIDs) > 0 {
+		ourns := make([]string, len(o.ProductIDs))
+		for i, id := range o.ProductIDs {
+			ourns[i] = quoteFilterValue(productOURN(id))
+		}
+		clauses = append(clauses, "custom_attributes.ourn:("+strings.Join(ourns, " OR ")+")")
+	}
+	if len(o.Publishers) > 0 {
+		publishers := make([]string, len(o.Publishers))
+		for i, p := range o.Publishers {
+			publishers[i] = quoteFilterValue(strings.TrimSpace(p))
+		}
+		clauses = append(clauses, "custom_attributes.publishers:("+strings.Join(publishers, " OR ")+")")
 	}
+
+	clauses = append(clauses, answerLanguageFilter, answerPermissionFilter)
+	return strings.Join(clauses, " AND ")
+}
+
+// productOURN returns the OURN of a product ID. Bare IDs are book product IDs,
+// as oreilly_search_content returns them; videos and articles must be given
+// as OURNs such as "urn:orm:video:0636920000000". Validate rejects bare IDs
+// when content_types excludes books.
+func productOURN(id string) string {
+	id = strings.TrimSpace(id)
+	if isOURN(id) {
+		return id
+	}
+	return "urn:orm:book:" + id
+}
+
+// isOURN reports whether id is already an OURN.
+func isOURN(id string) bool {
+	return strings.HasPrefix(id, "urn:orm:")
+}
+
+// quoteFilterValue quotes a value as a phrase of the filter query.
+func quoteFilterValue(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
 }
 
 // SubmitQuestion submits a question to O'Reilly Answers and returns the question ID
 // NOTE: This functionality has not been fully tested in production
-func (bc *BrowserClient) SubmitQuestion(question string) (*QuestionResponse, error) {
+func (bc *BrowserClient) SubmitQuestion(ctx context.Context, question string, opts QuestionOptions) (*QuestionResponse, error) {
 	slog.Info("質問を送信します", "question", question)
 
+	// Create a question request
+	questionReq, err := createQuestionRequest(question, opts)
+	if err != nil {
+		return nil, fmt.Errorf("質問の条件が不正です: %w", err)
+	}
+
 	// Create OpenAPI client with answers-specific referer
 	client := &api.ClientWithResponses{
 		ClientInterface: &api.Client{
@@ -66,10 +193,7 @@ func (bc *BrowserClient) SubmitQuestion(question string) (*QuestionResponse, err
 		},
 	}
 
-	// Create a question request
-	questionReq := createQuestionRequest(question)
-
-	slog.Debug("OpenAPI client経由で質問を送信中", "question", question)
+	slog.Debug("OpenAPI client経由で質問を送信中", "question", question, "filter_query", questionReq.FilterQuery)
 
 	// Convert to API request format
 	apiRequest := api.QuestionRequest{
@@ -84,7 +208,7 @@ func (bc *BrowserClient) SubmitQuestion(question string) (*QuestionResponse, err
 	}
 
 	// Submit question (タイムアウト付き)
-	apiCtx, apiCancel := context.WithTimeout(context.Background(), APIOperationTimeout)
+	apiCtx, apiCancel := bc.apiContext(ctx)
 	defer apiCancel()
 	resp, err := client.SubmitQuestionWithResponse(apiCtx, apiRequest)
 	if err != nil {
@@ -113,7 +237,7 @@ func (bc *BrowserClient) SubmitQuestion(question string) (*QuestionResponse, err
 
 // GetAnswer retrieves the answer for a submitted question
 // NOTE: This functionality has not been fully tested in production
-func (bc *BrowserClient) GetAnswer(questionID string, includeUnfinished bool) (*AnswerResponse, error) {
+func (bc *BrowserClient) GetAnswer(ctx context.Context, questionID string, includeUnfinished bool) (*AnswerResponse, error) {
 	slog.Debug("回答を取得中", "question_id", questionID)
 
 	// Create OpenAPI client with answers-specific referer
@@ -131,7 +255,7 @@ func (bc *BrowserClient) GetAnswer(questionID string, includeUnfinished bool) (*
 	}
 
 	// Get answer (タイムアウト付き)
-	apiCtx, apiCancel := context.WithTimeout(context.Background(), APIOperationTimeout)
+	apiCtx, apiCancel := bc.apiContext(ctx)
 	defer apiCancel()
 	resp, err := client.GetAnswerWithResponse(apiCtx, questionID, params)
 	if err != nil {
@@ -230,18 +354,29 @@ func convertAnswerData(data *api.AnswerData) AnswerData {
 	return result
 }
 
-// AskQuestion asks a question and polls for the answer until completion
+// AskQuestion asks a question and polls for the answer until completion.
+// onProgress (optional) receives each unfinished answer while polling.
 // NOTE: This functionality has not been fully tested in production
-func (bc *BrowserClient) AskQuestion(question string, maxWaitTime time.Duration) (*AnswerResponse, error) {
+func (bc *BrowserClient) AskQuestion(ctx context.Context, question string, opts QuestionOptions, maxWaitTime time.Duration, onProgress AnswerProgressFunc) (*AnswerResponse, error) {
 	slog.Info("質問を開始します", "question", question)
 
 	// Submit question
-	questionResp, err := bc.SubmitQuestion(question)
+	questionResp, err := bc.SubmitQuestion(ctx, question, opts)
 	if err != nil {
 		return nil, fmt.Errorf("質問送信失敗: %w", err)
 	}
 
-	// Poll for answer
+	answer, err := bc.WaitForAnswer(ctx, questionResp.QuestionID, maxWaitTime, onProgress)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("質問への回答が完了しました", "question", question)
+	return answer, nil
+}
+
+// WaitForAnswer polls a submitted question until the answer is finished, maxWaitTime
+// elapses, or ctx is done. onProgress (optional) receives each unfinished answer.
+func (bc *BrowserClient) WaitForAnswer(ctx context.Context, questionID string, maxWaitTime time.Duration, onProgress AnswerProgressFunc) (*AnswerResponse, error) {
 	start := time.Now()
 	pollInterval := 2 * time.Second
 	maxInterval := 10 * time.Second
@@ -253,22 +388,34 @@ func (bc *BrowserClient) AskQuestion(question string, maxWaitTime time.Duration)
 		}
 
 		// Get answer
-		answer, err := bc.GetAnswer(questionResp.QuestionID, true)
+		answer, err := bc.GetAnswer(ctx, questionID, true)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("回答待機が中断されました: %w", ctx.Err())
+			}
 			slog.Warn("回答取得エラー（リトライ中）", "error", err)
-			time.Sleep(pollInterval)
+			if err := sleepContext(ctx, pollInterval); err != nil {
+				return nil, fmt.Errorf("回答待機が中断されました: %w", err)
+			}
 			continue
 		}
 
 		// Check if finished
 		if answer.IsFinished {
-			slog.Info("質問への回答が完了しました", "question", question)
 			return answer, nil
 		}
 
+		// Report partial answer
+		elapsed := time.Since(start)
+		if onProgress != nil {
+			onProgress(answer, elapsed)
+		}
+
 		// Wait before next poll
-		slog.Debug("回答生成中...", "elapsed_time", time.Since(start).Round(time.Second))
-		time.Sleep(pollInterval)
+		slog.Debug("回答生成中...", "elapsed_time", elapsed.Round(time.Second))
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			return nil, fmt.Errorf("回答待機が中断されました: %w", err)
+		}
 
 		// Gradually increase poll interval to reduce load
 		if pollInterval < maxInterval {
@@ -282,9 +429,21 @@ func (bc *BrowserClient) AskQuestion(question string, maxWaitTime time.Duration)
 
 // GetQuestionByID retrieves a previously asked question and its answer
 // NOTE: This functionality has not been fully tested in production
-func (bc *BrowserClient) GetQuestionByID(questionID string) (*AnswerResponse, error) {
+func (bc *BrowserClient) GetQuestionByID(ctx context.Context, questionID string) (*AnswerResponse, error) {
 	slog.Debug("質問IDで回答を取得", "question_id", questionID)
-	return bc.GetAnswer(questionID, true)
+	return bc.GetAnswer(ctx, questionID, true)
+}
+
+// sleepContext waits for the given duration or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // Helper functions for safe type conversion from API pointer types
@@ -1,8 +1,11 @@
 package browser
 
 import (
+	"context"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -96,6 +99,22 @@ func TestGetAnswer_NilMisoResponse(t *testing.T) {
 	assert.NotNil(t, result.Sources)
 }
 
+func TestSleepContext_ReturnsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := sleepContext(ctx, time.Minute)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second, "sleepContext should return immediately when ctx is cancelled")
+}
+
+func TestSleepContext_Elapses(t *testing.T) {
+	err := sleepContext(context.Background(), time.Millisecond)
+	assert.NoError(t, err)
+}
+
 // containsNullValue checks if a JSON string contains a null value for a specific field
 func containsNullValue(jsonStr, fieldName string) bool {
 	var raw map[string]any
@@ -110,3 +129,58 @@ func containsNullValue(jsonStr, fieldName string) bool {
 
 	return value == nil
 }
+
+func TestCreateQuestionRequest_Defaults(t *testing.T) {
+	req, err := createQuestionRequest("What is Raft?", QuestionOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "(type:book OR type:video OR type:article) AND language:(\"en\" OR \"EN\" OR \"en-au\" OR \"en-gb\" OR \"en-GB\" OR \"en-us\" OR \"en-US\") AND ( NOT custom_attributes.required_p_permissions:aia ) AND ( NOT custom_attributes.required_p_permissions:cldsc ) AND ( NOT custom_attributes.required_p_permissions:cprex ) AND ( NOT custom_attributes.required_p_permissions:lvtrg ) AND ( NOT custom_attributes.required_p_permissions:ntbks ) AND ( NOT custom_attributes.required_p_permissions:scnrio )", req.FilterQuery, "an unscoped question keeps the original filter")
+	assert.Equal(t, DefaultFilterQuery, req.FilterQuery)
+	assert.Equal(t, PipelineConfig{SnippetLength: DefaultSnippetLength, HighlightLength: DefaultHighlightLength}, req.PipelineConfig)
+}
+
+func TestCreateQuestionRequest_Scoped(t *testing.T) {
+	req, err := createQuestionRequest("What is Raft?", QuestionOptions{
+		ProductIDs:      []string{"9781449373320", "urn:orm:video:0636920000000"},
+		ContentTypes:    []string{"Book", "video"},
+		Publishers:      []string{`O'Reilly "Media"`},
+		SnippetLength:   1000,
+		HighlightLength: 300,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(req.FilterQuery,
+		`(type:book OR type:video) AND custom_attributes.ourn:("urn:orm:book:9781449373320" OR "urn:orm:video:0636920000000") AND custom_attributes.publishers:("O'Reilly \"Media\"") AND language:(`),
+		req.FilterQuery)
+	assert.True(t, strings.HasSuffix(req.FilterQuery, answerPermissionFilter), "permission exclusions are always applied")
+	assert.Equal(t, PipelineConfig{SnippetLength: 1000, HighlightLength: 300}, req.PipelineConfig)
+}
+
+func TestQuestionOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    QuestionOptions
+		wantErr string
+	}{
+		{"zero value", QuestionOptions{}, ""},
+		{"unknown content type", QuestionOptions{ContentTypes: []string{"podcast"}}, `invalid content type "podcast"`},
+		{"empty product id", QuestionOptions{ProductIDs: []string{" "}}, "product_ids"},
+		{"empty publisher", QuestionOptions{Publishers: []string{""}}, "publishers"},
+		{"book ids with book type", QuestionOptions{ProductIDs: []string{"9781449373320"}, ContentTypes: []string{"Book", "video"}}, ""},
+		{"book ids without book type", QuestionOptions{ProductIDs: []string{"9781449373320"}, ContentTypes: []string{"video"}}, "product_ids without the"},
+		{"ourn without book type", QuestionOptions{ProductIDs: []string{"urn:orm:video:0636920000000"}, ContentTypes: []string{"video"}}, ""},
+		{"negative snippet length", QuestionOptions{SnippetLength: -1}, "snippet_length must not be negative"},
+		{"negative highlight length", QuestionOptions{HighlightLength: -1}, "highlight_length must not be negative"},
+		{"lengths left to the API", QuestionOptions{SnippetLength: 5000, HighlightLength: 1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
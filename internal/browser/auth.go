@@ -7,12 +7,16 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/cookie"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
 )
 
 // visibleLoginTempDir はビジブルログイン用の一時ディレクトリパスを返す。
@@ -27,20 +31,37 @@ var errUnauthenticated = errors.New("認証されていません (401/403)")
 
 const ormHome = "https://learning.oreilly.com/home/"
 
+// ClientOptions は BrowserClient の設定を保持します。
+// ゼロ値はリトライ・キャッシュ・コンテンツ保存・全文検索インデックスをすべて使いません。
+type ClientOptions struct {
+	Debug         bool
+	StateDir      string               // XDG StateHome (Chrome一時データ用)
+	HTTP          config.HTTPOpts      // リトライ・レート制限設定
+	BookCache     config.BookCacheOpts // 書籍詳細・目次のプロセス内キャッシュ設定
+	ContentStore  *ContentStore        // 取得済みコンテンツの保存先 (呼び出し元と共有する。nil で保存しない)
+	FulltextIndex *fulltext.Index      // 取得したチャプターを登録する全文検索インデックス (呼び出し元と共有する。nil で登録しない)
+}
+
 // NewBrowserClient は新しいブラウザクライアントを作成します。
 // Cookie が無効またはない場合は、ビジブルブラウザを起動してユーザーに手動ログインを促します。
-// stateDir: XDG StateHome (Chrome一時データ用)
-func NewBrowserClient(cookieManager cookie.Manager, debug bool, stateDir string) (*BrowserClient, error) {
+func NewBrowserClient(cookieManager cookie.Manager, opts ClientOptions) (*BrowserClient, error) {
+	apiTimeout := RetryBudget(APIOperationTimeout, opts.HTTP)
 	client := &BrowserClient{
 		httpClient: &http.Client{
-			Timeout: APIOperationTimeout,
+			Timeout: apiTimeout,
 			Transport: &GzipTransport{
-				Transport: http.DefaultTransport,
+				Transport: NewRetryTransport(http.DefaultTransport, opts.HTTP),
 			},
 		},
-		userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-		stateDir:  stateDir,
-		debug:     debug,
+		apiTimeout:    apiTimeout,
+		userAgent:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		stateDir:      opts.StateDir,
+		debug:         opts.Debug,
+		detailsCache:  newLRUCache[*BookDetailResponse](opts.BookCache.TTL, opts.BookCache.MaxEntries),
+		tocCache:      newLRUCache[*TableOfContentsResponse](opts.BookCache.TTL, opts.BookCache.MaxEntries),
+		creditsCache:  newLRUCache[*BookCredits](opts.BookCache.TTL, opts.BookCache.MaxEntries),
+		contentStore:  opts.ContentStore,
+		fulltextIndex: opts.FulltextIndex,
 	}
 
 	// Cookieの復元を試行
@@ -53,7 +74,7 @@ func NewBrowserClient(cookieManager cookie.Manager, debug bool, stateDir string)
 			client.cookieManager = cookieManager
 
 			// HTTPリクエストでCookieが有効かどうか検証（chromedp不要）
-			if client.validateAuthenticationViaHTTP() == nil {
+			if client.validateAuthenticationViaHTTP(context.Background()) == nil {
 				slog.Info("Cookieを使用してログインが完了しました")
 				return client, nil
 			}
@@ -63,7 +84,7 @@ func NewBrowserClient(cookieManager cookie.Manager, debug bool, stateDir string)
 
 	// ビジブルブラウザでログインを実行
 	client.cookieManager = cookieManager
-	if err := RunVisibleLogin(visibleLoginTempDir(stateDir), cookieManager); err != nil {
+	if err := RunVisibleLogin(visibleLoginTempDir(opts.StateDir), cookieManager); err != nil {
 		return nil, fmt.Errorf("failed to login: %w", err)
 	}
 
@@ -85,7 +106,7 @@ func (bc *BrowserClient) Reauthenticate() error {
 	}
 
 	// ログイン成功後にCookieの有効性をHTTPで検証する
-	if err := bc.validateAuthenticationViaHTTP(); err != nil {
+	if err := bc.validateAuthenticationViaHTTP(context.Background()); err != nil {
 		return fmt.Errorf("再認証後のCookie検証に失敗しました: %w", err)
 	}
 
@@ -99,8 +120,8 @@ func (bc *BrowserClient) Reauthenticate() error {
 //   - nil: 認証成功 (200)
 //   - errUnauthenticated: 401/403 による認証失敗 (Cookie を削除すべき)
 //   - その他エラー: ネットワーク障害や予期しないレスポンス (Cookie は保持すべき)
-func (bc *BrowserClient) validateAuthenticationViaHTTP() error {
-	ctx, cancel := context.WithTimeout(context.Background(), AuthValidationTimeout)
+func (bc *BrowserClient) validateAuthenticationViaHTTP(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, AuthValidationTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", ormHome, nil)
@@ -153,8 +174,8 @@ func (bc *BrowserClient) validateAuthenticationViaHTTP() error {
 // 認証済みの場合は nil を返します。
 // 401/403 が確定した場合のみ stale Cookie を削除してエラーを返します。
 // ネットワークエラーの場合は Cookie を保持したままエラーを返します。
-func (bc *BrowserClient) CheckAndResetAuth() error {
-	err := bc.validateAuthenticationViaHTTP()
+func (bc *BrowserClient) CheckAndResetAuth(ctx context.Context) error {
+	err := bc.validateAuthenticationViaHTTP(ctx)
 	if err == nil {
 		return nil
 	}
@@ -233,20 +254,42 @@ func (bc *BrowserClient) createRequestEditorInternal(referer string) func(ctx co
 }
 
 // GetContentFromURL retrieves HTML/XHTML content from the specified URL with authentication
-func (bc *BrowserClient) GetContentFromURL(contentURL string) (string, error) {
+func (bc *BrowserClient) GetContentFromURL(ctx context.Context, contentURL string) (string, error) {
+	content, err := bc.fetchContent(ctx, contentURL, "")
+	if err != nil {
+		return "", err
+	}
+	return content.body, nil
+}
+
+// fetchedContent is the result of fetchContent.
+type fetchedContent struct {
+	body        string
+	etag        string
+	contentType string
+	notModified bool // サーバーが 304 を返した (body は空)
+}
+
+// fetchContent retrieves content from contentURL. When etag is non-empty the
+// request is conditional, and a 304 response is reported as notModified.
+func (bc *BrowserClient) fetchContent(ctx context.Context, contentURL, etag string) (*fetchedContent, error) {
 	// Determine content type from URL
 	contentType := "HTML"
 	if strings.HasSuffix(contentURL, ".xhtml") {
 		contentType = "XHTML"
 	} else if strings.Contains(contentURL, "/files/html/") {
 		contentType = "HTML (nested path)"
+	} else if strings.HasPrefix(mime.TypeByExtension(strings.ToLower(path.Ext(contentURL))), "image/") {
+		contentType = "image"
 	}
 
 	slog.Info("コンテンツを取得しています", "type", contentType, "url", contentURL)
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, contentURL, nil)
+	apiCtx, apiCancel := bc.apiContext(ctx)
+	defer apiCancel()
+	req, err := http.NewRequestWithContext(apiCtx, http.MethodGet, contentURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers for HTML response (try different accept headers)
@@ -259,6 +302,9 @@ func (bc *BrowserClient) GetContentFromURL(contentURL string) (string, error) {
 	req.Header.Set("Sec-Fetch-Mode", "navigate")
 	req.Header.Set("Sec-Fetch-Site", "same-origin")
 	req.Header.Set("User-Agent", bc.userAgent)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	// Add authentication cookies manually using cookie.Manager
 	cookies := bc.cookieManager.GetCookiesForURL(req.URL)
@@ -272,7 +318,7 @@ func (bc *BrowserClient) GetContentFromURL(contentURL string) (string, error) {
 
 	resp, err := bc.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("HTTP request failed: %w", err)
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -281,11 +327,15 @@ func (bc *BrowserClient) GetContentFromURL(contentURL string) (string, error) {
 	}()
 
 	if resp.StatusCode == 401 || resp.StatusCode == 403 {
-		return "", fmt.Errorf("authentication error: status %d (cookies may have expired)", resp.StatusCode)
+		return nil, fmt.Errorf("authentication error: status %d (cookies may have expired)", resp.StatusCode)
+	}
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return &fetchedContent{etag: etag, notModified: true}, nil
 	}
 
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("content request failed with status %d", resp.StatusCode)
+		return nil, fmt.Errorf("content request failed with status %d", resp.StatusCode)
 	}
 
 	// Handle gzip compression
@@ -293,7 +343,7 @@ func (bc *BrowserClient) GetContentFromURL(contentURL string) (string, error) {
 	if resp.Header.Get("Content-Encoding") == "gzip" {
 		gzipReader, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return "", fmt.Errorf("failed to create gzip reader: %w", err)
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer func() {
 			if err := gzipReader.Close(); err != nil {
@@ -305,8 +355,8 @@ func (bc *BrowserClient) GetContentFromURL(contentURL string) (string, error) {
 
 	bodyBytes, err := io.ReadAll(reader)
 	if err != nil {
-		return "", fmt.Errorf("failed to read content body: %w", err)
+		return nil, fmt.Errorf("failed to read content body: %w", err)
 	}
 
-	return string(bodyBytes), nil
+	return &fetchedContent{body: string(bodyBytes), etag: resp.Header.Get("ETag"), contentType: resp.Header.Get("Content-Type")}, nil
 }
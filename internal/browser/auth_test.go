@@ -3,6 +3,7 @@ package browser
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
 	"net/http"
 	"strings"
@@ -242,7 +243,7 @@ func TestBrowserClient_GetContentFromURL(t *testing.T) {
 				userAgent:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 			}
 
-			content, err := client.GetContentFromURL(tt.url)
+			content, err := client.GetContentFromURL(context.Background(), tt.url)
 
 			if tt.wantError {
 				require.Error(t, err)
@@ -456,7 +457,7 @@ func TestBrowserClient_ValidateAuthenticationViaHTTP(t *testing.T) {
 				userAgent:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 			}
 
-			err := client.validateAuthenticationViaHTTP()
+			err := client.validateAuthenticationViaHTTP(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
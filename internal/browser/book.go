@@ -4,33 +4,62 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/generated/api"
 )
 
+const (
+	// bookCreditsSearchRows is the number of search results scanned for a book's authors.
+	bookCreditsSearchRows = 10
+	// bookCreditsNotFoundTTL is how long a search that did not find the book is cached.
+	bookCreditsNotFoundTTL = 5 * time.Minute
+)
+
 // GetBookDetails retrieves book details and table of contents from O'Reilly book Product ID
-func (bc *BrowserClient) GetBookDetails(productID string) (*BookDetailResponse, error) {
+func (bc *BrowserClient) GetBookDetails(ctx context.Context, productID string) (*BookDetailResponse, error) {
+	if cached, ok := bc.detailsCache.get(productID); ok {
+		slog.Debug("書籍詳細をキャッシュから返します", "product_id", productID)
+		return cached, nil
+	}
 	slog.Info("プロダクトIDから書籍詳細を取得しています", "product_id", productID)
 
 	// Get book details from API
-	bookDetail, err := bc.getBookDetails(productID)
+	bookDetail, err := bc.getBookDetails(ctx, productID)
 	if err != nil {
 		return nil, fmt.Errorf("書籍詳細取得失敗: %w", err)
 	}
 
+	bc.detailsCache.set(productID, bookDetail)
 	return bookDetail, nil
 }
 
-// GetBookTOC retrieves a table of contents for a specific book
-func (bc *BrowserClient) GetBookTOC(productID string) (*TableOfContentsResponse, error) {
-	return bc.getBookTOC(productID)
+// GetBookTOC retrieves a table of contents for a specific book.
+// Results are cached per product ID, so repeated chapter reads share one TOC download.
+func (bc *BrowserClient) GetBookTOC(ctx context.Context, productID string) (*TableOfContentsResponse, error) {
+	if cached, ok := bc.tocCache.get(productID); ok {
+		slog.Debug("目次をキャッシュから返します", "product_id", productID)
+		return cached, nil
+	}
+
+	toc, err := bc.getBookTOC(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	bc.tocCache.set(productID, toc)
+	return toc, nil
 }
 
 // Helper functions
 
 // getBookDetails retrieves book metadata from O'Reilly v2 epubs API using OpenAPI client
-func (bc *BrowserClient) getBookDetails(productID string) (*BookDetailResponse, error) {
+func (bc *BrowserClient) getBookDetails(ctx context.Context, productID string) (*BookDetailResponse, error) {
 	slog.Debug("書籍詳細APIを呼び出しています (v2)", "product_id", productID)
 
 	client, err := api.NewClientWithResponses(APIEndpointBase,
@@ -40,7 +69,7 @@ func (bc *BrowserClient) getBookDetails(productID string) (*BookDetailResponse,
 		return nil, fmt.Errorf("failed to create OpenAPI client: %v", err)
 	}
 
-	apiCtx, apiCancel := context.WithTimeout(context.Background(), APIOperationTimeout)
+	apiCtx, apiCancel := bc.apiContext(ctx)
 	defer apiCancel()
 	resp, err := client.GetBookDetailsWithResponse(apiCtx, productID)
 	if err != nil {
@@ -56,10 +85,81 @@ func (bc *BrowserClient) getBookDetails(productID string) (*BookDetailResponse,
 	}
 
 	bookDetail := convertAPIBookDetailToLocal(resp.JSON200)
+	bc.contentStore.putJSON(detailsStoreKey(productID), bookDetail, responseURL(resp.HTTPResponse), resp.HTTPResponse.Header.Get("ETag"))
 	slog.Info("書籍詳細取得に成功しました", "title", bookDetail.Title, "product_id", productID)
 	return bookDetail, nil
 }
 
+// GetBookCredits returns the authors and publisher of a book, which the v2
+// epubs API does not return, from the search API. It searches for productID,
+// then for title when that finds nothing. A book the search does not find has
+// empty credits. Lookups are cached apart from book details; a book the search
+// did not find for bookCreditsNotFoundTTL only, and failed searches not at all.
+func (bc *BrowserClient) GetBookCredits(ctx context.Context, productID, title string) (*BookCredits, error) {
+	if cached, ok := bc.creditsCache.get(productID); ok {
+		return cached, nil
+	}
+
+	credits := &BookCredits{}
+	for _, query := range []string{productID, title} {
+		if query == "" {
+			continue
+		}
+		results, _, err := bc.SearchContent(ctx, query, map[string]any{"rows": bookCreditsSearchRows})
+		if err != nil {
+			return nil, fmt.Errorf("書籍の著者情報の検索に失敗しました: %w", err) // not cached: the next call retries
+		}
+		if found, ok := matchBookCredits(results, productID); ok {
+			bc.creditsCache.set(productID, found)
+			return found, nil
+		}
+	}
+	slog.Debug("検索結果に書籍が見つからないため著者情報を補完できませんでした", "product_id", productID)
+	bc.creditsCache.setFor(productID, credits, bookCreditsNotFoundTTL)
+	return credits, nil
+}
+
+// matchBookCredits returns the credits of the search result for productID.
+func matchBookCredits(results []map[string]any, productID string) (*BookCredits, bool) {
+	for _, result := range results {
+		if id, _ := result["product_id"].(string); id != productID {
+			continue
+		}
+		credits := &BookCredits{}
+		if authors, ok := result["authors"].([]Author); ok {
+			for _, a := range authors {
+				if a.Name != "" && !slices.Contains(credits.Authors, a.Name) {
+					credits.Authors = append(credits.Authors, a.Name)
+				}
+			}
+		}
+		credits.Publisher, _ = result["publisher"].(string)
+		return credits, true
+	}
+	return nil, false
+}
+
+// editionPattern matches the edition statement in a title, e.g. "2nd Edition".
+var editionPattern = regexp.MustCompile(`(?i)\b(\d+)(?:st|nd|rd|th)\s+edition\b`)
+
+// editionFromTitle returns the edition number stated in a title, or 0.
+func editionFromTitle(title string) int {
+	m := editionPattern.FindStringSubmatch(title)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// responseURL returns the request URL of resp, or empty string if unknown.
+func responseURL(resp *http.Response) string {
+	if resp == nil || resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+	return resp.Request.URL.String()
+}
+
 // derefString returns the value of a string pointer, or empty string if nil.
 func derefString(p *string) string {
 	if p != nil {
@@ -80,6 +180,7 @@ func convertAPIBookDetailToLocal(apiBook *api.BookDetailResponse) *BookDetailRes
 		PublicationDate: derefString(apiBook.PublicationDate),
 		Language:        derefString(apiBook.Language),
 	}
+	bookDetail.Edition = editionFromTitle(bookDetail.Title)
 
 	if apiBook.VirtualPages != nil {
 		bookDetail.VirtualPages = *apiBook.VirtualPages
@@ -107,7 +208,7 @@ func convertAPIBookDetailToLocal(apiBook *api.BookDetailResponse) *BookDetailRes
 }
 
 // getBookTOC retrieves table of contents from O'Reilly v2 API
-func (bc *BrowserClient) getBookTOC(productID string) (*TableOfContentsResponse, error) {
+func (bc *BrowserClient) getBookTOC(ctx context.Context, productID string) (*TableOfContentsResponse, error) {
 	slog.Debug("目次APIを呼び出しています (v2)", "product_id", productID)
 
 	client, err := api.NewClientWithResponses(APIEndpointBase,
@@ -117,7 +218,7 @@ func (bc *BrowserClient) getBookTOC(productID string) (*TableOfContentsResponse,
 		return nil, fmt.Errorf("failed to create OpenAPI client: %v", err)
 	}
 
-	apiCtx, apiCancel := context.WithTimeout(context.Background(), APIOperationTimeout)
+	apiCtx, apiCancel := bc.apiContext(ctx)
 	defer apiCancel()
 	resp, err := client.GetBookTOCWithResponse(apiCtx, productID)
 	if err != nil {
@@ -133,6 +234,7 @@ func (bc *BrowserClient) getBookTOC(productID string) (*TableOfContentsResponse,
 	}
 
 	tocResponse := convertV2TOCToLocal(productID, *resp.JSON200)
+	bc.contentStore.putJSON(tocStoreKey(productID), tocResponse, responseURL(resp.HTTPResponse), resp.HTTPResponse.Header.Get("ETag"))
 	slog.Info("目次取得に成功しました", "book_id", productID, "chapter_count", tocResponse.TotalChapters)
 	return tocResponse, nil
 }
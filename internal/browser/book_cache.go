@@ -0,0 +1,180 @@
+package browser
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CacheStats reports hit/miss counters for an in-process cache.
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+}
+
+// BookCacheStats reports statistics for the book details and TOC caches.
+type BookCacheStats struct {
+	Details CacheStats `json:"details"`
+	TOC     CacheStats `json:"toc"`
+}
+
+// lruCache is a size-bounded LRU cache whose entries expire after a TTL.
+// A nil *lruCache is a valid, always-missing cache.
+type lruCache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	ll         *list.List // front = most recently used
+	items      map[string]*list.Element
+	stats      CacheStats
+	now        func() time.Time
+}
+
+type lruEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// newLRUCache returns a cache, or nil when ttl or maxEntries disables caching.
+func newLRUCache[V any](ttl time.Duration, maxEntries int) *lruCache[V] {
+	if ttl <= 0 || maxEntries <= 0 {
+		return nil
+	}
+	return &lruCache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// get returns the cached value for key if present and not expired.
+func (c *lruCache[V]) get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return zero, false
+	}
+	entry := el.Value.(*lruEntry[V])
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(el)
+		c.stats.Misses++
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	c.stats.Hits++
+	return entry.value, true
+}
+
+// peek reports whether a fresh entry exists without updating recency or statistics.
+func (c *lruCache[V]) peek(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	entry := el.Value.(*lruEntry[V])
+	if c.now().After(entry.expiresAt) {
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// set stores value for key, evicting the least recently used entry when full.
+func (c *lruCache[V]) set(key string, value V) {
+	if c == nil {
+		return
+	}
+	c.setFor(key, value, c.ttl)
+}
+
+// setFor is set with an entry lifetime of ttl, capped at the cache's TTL.
+func (c *lruCache[V]) setFor(key string, value V, ttl time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(min(ttl, c.ttl))
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry[V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.ll.Len() > c.maxEntries {
+		c.removeElement(c.ll.Back())
+		c.stats.Evictions++
+	}
+}
+
+// remove deletes key from the cache. An empty key clears every entry.
+func (c *lruCache[V]) remove(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key == "" {
+		c.ll.Init()
+		clear(c.items)
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// snapshot returns the current statistics.
+func (c *lruCache[V]) snapshot() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.ll.Len()
+	return stats
+}
+
+func (c *lruCache[V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*lruEntry[V]).key)
+}
+
+// BookCacheStats returns hit/miss statistics for the book details and TOC caches.
+func (bc *BrowserClient) BookCacheStats() BookCacheStats {
+	return BookCacheStats{
+		Details: bc.detailsCache.snapshot(),
+		TOC:     bc.tocCache.snapshot(),
+	}
+}
+
+// InvalidateBookCache drops cached book details, TOC and credits for productID.
+// An empty productID clears the caches entirely.
+func (bc *BrowserClient) InvalidateBookCache(productID string) {
+	bc.detailsCache.remove(productID)
+	bc.tocCache.remove(productID)
+	bc.creditsCache.remove(productID)
+}
@@ -0,0 +1,286 @@
+package browser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache_HitMissAndEviction(t *testing.T) {
+	c := newLRUCache[string](time.Hour, 2)
+
+	_, ok := c.get("a")
+	assert.False(t, ok)
+
+	c.set("a", "A")
+	c.set("b", "B")
+	v, ok := c.get("a") // a becomes most recently used
+	assert.True(t, ok)
+	assert.Equal(t, "A", v)
+
+	c.set("c", "C") // evicts b
+	_, ok = c.get("b")
+	assert.False(t, ok)
+	_, ok = c.get("a")
+	assert.True(t, ok)
+
+	stats := c.snapshot()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, int64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+func TestLRUCache_TTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newLRUCache[string](time.Minute, 10)
+	c.now = func() time.Time { return now }
+
+	c.set("a", "A")
+	_, ok := c.peek("a")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.peek("a")
+	assert.False(t, ok)
+	_, ok = c.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.snapshot().Entries, "expired entry is dropped on get")
+}
+
+func TestLRUCache_Remove(t *testing.T) {
+	c := newLRUCache[string](time.Hour, 10)
+	c.set("a", "A")
+	c.set("b", "B")
+
+	c.remove("a")
+	_, ok := c.get("a")
+	assert.False(t, ok)
+	_, ok = c.get("b")
+	assert.True(t, ok)
+
+	c.remove("")
+	assert.Equal(t, 0, c.snapshot().Entries)
+}
+
+func TestLRUCache_Disabled(t *testing.T) {
+	c := newLRUCache[string](0, 10)
+	assert.Nil(t, c)
+
+	// nil cache is safe to use and never hits
+	c.set("a", "A")
+	_, ok := c.get("a")
+	assert.False(t, ok)
+	c.remove("a")
+	assert.Equal(t, CacheStats{}, c.snapshot())
+}
+
+// routingDoer serves canned responses by URL substring and counts requests per route.
+type routingDoer struct {
+	routes map[string]func() string
+	calls  map[string]*atomic.Int32
+}
+
+func newRoutingDoer(routes map[string]func() string) *routingDoer {
+	d := &routingDoer{routes: routes, calls: map[string]*atomic.Int32{}}
+	for k := range routes {
+		d.calls[k] = &atomic.Int32{}
+	}
+	return d
+}
+
+func (d *routingDoer) Do(req *http.Request) (*http.Response, error) {
+	for k, body := range d.routes {
+		if strings.Contains(req.URL.Path, k) {
+			d.calls[k].Add(1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(body())),
+				Request:    req,
+			}, nil
+		}
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func newCachingTestClient(doer HTTPDoer) *BrowserClient {
+	return &BrowserClient{
+		httpClient:    doer,
+		cookieManager: NewMockCookieManager(),
+		detailsCache:  newLRUCache[*BookDetailResponse](time.Hour, 10),
+		tocCache:      newLRUCache[*TableOfContentsResponse](time.Hour, 10),
+		creditsCache:  newLRUCache[*BookCredits](time.Hour, 10),
+	}
+}
+
+func TestGetBookChapterContent_FetchesTOCOnce(t *testing.T) {
+	doer := newRoutingDoer(map[string]func() string{
+		"/table-of-contents/": func() string {
+			return `[{"title":"Chapter 1","reference_id":"123-/ch01.html"},{"title":"Chapter 2","reference_id":"123-/ch02.html"}]`
+		},
+		"/files/": func() string { return "<html><body><h1>Title</h1><p>Body</p></body></html>" },
+	})
+	bc := newCachingTestClient(doer)
+
+	for _, ch := range []string{"ch01", "ch02", "ch01"} {
+		_, err := bc.GetBookChapterContent(context.Background(), "123", ch)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), doer.calls["/table-of-contents/"].Load(), "TOC should be downloaded once")
+	assert.Equal(t, int32(3), doer.calls["/files/"].Load())
+
+	stats := bc.BookCacheStats()
+	assert.Equal(t, int64(1), stats.TOC.Misses)
+	assert.Equal(t, int64(2), stats.TOC.Hits)
+}
+
+func TestFindTOCItem_RefetchesStaleCachedTOC(t *testing.T) {
+	var tocVersion atomic.Int32
+	doer := newRoutingDoer(map[string]func() string{
+		"/table-of-contents/": func() string {
+			if tocVersion.Load() == 0 {
+				return `[{"title":"Chapter 1","reference_id":"123-/ch01.html"}]`
+			}
+			return `[{"title":"Chapter 1","reference_id":"123-/ch01.html"},{"title":"Chapter 2","reference_id":"123-/ch02.html"}]`
+		},
+	})
+	bc := newCachingTestClient(doer)
+
+	_, err := bc.findTOCItem(context.Background(), "123", "ch01")
+	require.NoError(t, err)
+
+	// The book gained a chapter after the TOC was cached
+	tocVersion.Store(1)
+	item, err := bc.findTOCItem(context.Background(), "123", "ch02")
+	require.NoError(t, err)
+	assert.Equal(t, "Chapter 2", item.Title)
+	assert.Equal(t, int32(2), doer.calls["/table-of-contents/"].Load())
+
+	// A chapter that does not exist at all is still an error
+	_, err = bc.findTOCItem(context.Background(), "123", "ch99")
+	assert.Error(t, err)
+}
+
+func TestInvalidateBookCache(t *testing.T) {
+	doer := newRoutingDoer(map[string]func() string{
+		"/table-of-contents/": func() string { return `[]` },
+	})
+	bc := newCachingTestClient(doer)
+	ctx := context.Background()
+
+	_, err := bc.GetBookTOC(ctx, "123")
+	require.NoError(t, err)
+	_, err = bc.GetBookTOC(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), doer.calls["/table-of-contents/"].Load())
+
+	bc.InvalidateBookCache("123")
+	_, err = bc.GetBookTOC(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), doer.calls["/table-of-contents/"].Load())
+}
+
+func TestGetBookDetails_DoesNotSearchForCredits(t *testing.T) {
+	doer := newRoutingDoer(map[string]func() string{
+		"/epubs/urn:orm:book:9781492077206/": func() string {
+			return `{"identifier":"9781492077206","title":"Learning Go, 2nd Edition","isbn":"9781492077206","publication_date":"2024-01-09"}`
+		},
+		"/search/": func() string { return `{"data":{"products":[]}}` },
+	})
+	bc := newCachingTestClient(doer)
+
+	detail, err := bc.GetBookDetails(context.Background(), "9781492077206")
+	require.NoError(t, err)
+	assert.Equal(t, 2, detail.Edition)
+	assert.Equal(t, int32(0), doer.calls["/search/"].Load(), "credits are looked up only when citing")
+}
+
+func TestGetBookCredits(t *testing.T) {
+	doer := newRoutingDoer(map[string]func() string{
+		"/search/": func() string {
+			return `{"data":{"products":[
+				{"product_id":"0000000000000","title":"Other","authors":["Someone Else"]},
+				{"product_id":"9781492077206","title":"Learning Go","authors":["Jon Bodner"],"publishers":["O'Reilly Media, Inc."]}
+			]}}`
+		},
+	})
+	bc := newCachingTestClient(doer)
+	ctx := context.Background()
+
+	credits, err := bc.GetBookCredits(ctx, "9781492077206", "Learning Go, 2nd Edition")
+	require.NoError(t, err)
+	assert.Equal(t, &BookCredits{Authors: []string{"Jon Bodner"}, Publisher: "O'Reilly Media, Inc."}, credits)
+
+	_, err = bc.GetBookCredits(ctx, "9781492077206", "Learning Go, 2nd Edition")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), doer.calls["/search/"].Load(), "credits are cached")
+}
+
+func TestGetBookCredits_NotFound(t *testing.T) {
+	doer := newRoutingDoer(map[string]func() string{
+		"/search/": func() string { return `{"data":{"products":[{"product_id":"0000000000000","title":"Other"}]}}` },
+	})
+	bc := newCachingTestClient(doer)
+	ctx := context.Background()
+
+	credits, err := bc.GetBookCredits(ctx, "123", "Some Book")
+	require.NoError(t, err)
+	assert.Equal(t, &BookCredits{}, credits)
+	assert.Equal(t, int32(2), doer.calls["/search/"].Load(), "the title is searched when the product ID finds nothing")
+
+	_, err = bc.GetBookCredits(ctx, "123", "Some Book")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), doer.calls["/search/"].Load(), "a miss is cached too")
+}
+
+func TestGetBookCredits_SearchFails(t *testing.T) {
+	doer := newRoutingDoer(map[string]func() string{})
+	bc := newCachingTestClient(doer)
+	ctx := context.Background()
+
+	_, err := bc.GetBookCredits(ctx, "123", "Some Book")
+	assert.Error(t, err)
+
+	// The search recovers: the failure was not cached
+	doer.routes["/search/"] = func() string {
+		return `{"data":{"products":[{"product_id":"123","title":"Some Book","authors":["Ann Author"]}]}}`
+	}
+	doer.calls["/search/"] = &atomic.Int32{}
+	credits, err := bc.GetBookCredits(ctx, "123", "Some Book")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ann Author"}, credits.Authors)
+}
+
+func TestLRUCache_SetForCapsTTL(t *testing.T) {
+	c := newLRUCache[string](time.Minute, 10)
+	c.setFor("short", "v", time.Nanosecond)
+	c.setFor("long", "v", time.Hour)
+	time.Sleep(time.Millisecond)
+
+	_, ok := c.get("short")
+	assert.False(t, ok, "a shorter TTL expires first")
+	_, ok = c.get("long")
+	assert.True(t, ok)
+}
+
+func TestEditionFromTitle(t *testing.T) {
+	tests := map[string]int{
+		"Learning Go, 2nd Edition":                     2,
+		"Designing Data-Intensive Applications":        0,
+		"Fluent Python, 1st edition":                   1,
+		"Programming Rust: Fast, Safe Systems, 3rd Ed": 0,
+		"The Go Programming Language 21st Edition":     21,
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, editionFromTitle(title), title)
+	}
+}
@@ -0,0 +1,156 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrNotImage is returned when a requested book file is not an image.
+var ErrNotImage = errors.New("book file is not an image")
+
+// BookImage is an image file of a book, such as a figure or diagram.
+type BookImage struct {
+	BookID    string
+	Path      string // book-relative path, e.g. "assets/ddia_0101.png"
+	MIMEType  string
+	Data      []byte
+	SourceURL string
+	Metadata  map[string]any
+}
+
+// bookFilesPrefix is the URL path under which the files of a book are served.
+func bookFilesPrefix(productID string) string {
+	return "/api/v2/epubs/urn:orm:book:" + productID + "/files/"
+}
+
+// bookFileURL returns the API URL of a book-relative file path.
+func bookFileURL(productID, filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return APIEndpointBase + bookFilesPrefix(productID) + strings.Join(segments, "/")
+}
+
+// ResolveBookFilePath resolves ref (e.g. an image src) against the URL of the
+// chapter that references it and returns the book-relative file path.
+// It returns "" when ref points outside the book's files.
+func ResolveBookFilePath(productID, baseURL, ref string) string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	r, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || ref == "" {
+		return ""
+	}
+	abs := base.ResolveReference(r)
+	endpoint, _ := url.Parse(APIEndpointBase)
+	prefix := bookFilesPrefix(productID)
+	if abs.Host != endpoint.Host || !strings.HasPrefix(abs.Path, prefix) {
+		return ""
+	}
+	return cleanBookFilePath(strings.TrimPrefix(abs.Path, prefix))
+}
+
+// cleanBookFilePath normalizes a book-relative path, returning "" for paths
+// that are empty or escape the book's files.
+func cleanBookFilePath(p string) string {
+	p = path.Clean("/" + p)[1:]
+	if p == "" || p == "." {
+		return ""
+	}
+	return p
+}
+
+// imageMIMEType determines the MIME type of image data from the response
+// Content-Type, the file extension or the content itself, in that order.
+// It returns "" when the data is not an image.
+func imageMIMEType(imagePath, contentType string, data []byte) string {
+	candidates := []string{contentType, mime.TypeByExtension(strings.ToLower(path.Ext(imagePath))), http.DetectContentType(data)}
+	for _, c := range candidates {
+		if mediaType, _, err := mime.ParseMediaType(c); err == nil && strings.HasPrefix(mediaType, "image/") {
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// GetBookImage downloads an image from the book's files with the authenticated
+// cookies. Images do not change once published, so a copy in the content store
+// is returned without contacting the server.
+func (bc *BrowserClient) GetBookImage(ctx context.Context, productID, imagePath string) (*BookImage, error) {
+	imagePath = cleanBookFilePath(imagePath)
+	if imagePath == "" {
+		return nil, fmt.Errorf("invalid image path")
+	}
+	storeKey := imageStoreKey(productID, imagePath)
+	if image, _, err := bc.contentStore.bookImage(productID, imagePath); err == nil {
+		slog.Debug("保存済みの画像を使用します", "product_id", productID, "path", imagePath)
+		return image, nil
+	}
+
+	imageURL := bookFileURL(productID, imagePath)
+	content, err := bc.fetchContent(ctx, imageURL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image from %s: %w", imageURL, err)
+	}
+	data := []byte(content.body)
+	mimeType := imageMIMEType(imagePath, content.contentType, data)
+	if mimeType == "" {
+		return nil, fmt.Errorf("%s: %w", imagePath, ErrNotImage)
+	}
+
+	if err := bc.contentStore.Put(storeKey, data, imageURL, content.etag); err != nil {
+		slog.Warn("コンテンツストアへの保存に失敗しました", "key", storeKey, "error", err)
+	}
+
+	slog.Debug("画像取得に成功しました", "url", imageURL, "mime_type", mimeType, "size", len(data))
+	return &BookImage{
+		BookID:    productID,
+		Path:      imagePath,
+		MIMEType:  mimeType,
+		Data:      data,
+		SourceURL: imageURL,
+	}, nil
+}
+
+// bookImage reads an image and its store entry from the store.
+func (s *ContentStore) bookImage(productID, imagePath string) (*BookImage, *StoredEntry, error) {
+	data, entry, err := s.Get(imageStoreKey(productID, imagePath))
+	if err != nil {
+		return nil, nil, err
+	}
+	mimeType := imageMIMEType(imagePath, "", data)
+	if mimeType == "" {
+		return nil, nil, fmt.Errorf("%s: %w", imagePath, ErrNotImage)
+	}
+	return &BookImage{
+		BookID:    productID,
+		Path:      imagePath,
+		MIMEType:  mimeType,
+		Data:      data,
+		SourceURL: entry.URL,
+	}, entry, nil
+}
+
+// StaleBookImage returns an image from the store, marked as stale.
+func (s *ContentStore) StaleBookImage(productID, imagePath string) (*BookImage, error) {
+	imagePath = cleanBookFilePath(imagePath)
+	if imagePath == "" {
+		return nil, ErrNotStored
+	}
+	image, entry, err := s.bookImage(productID, imagePath)
+	if err != nil {
+		return nil, err
+	}
+	image.Metadata = staleMetadata(nil, entry)
+	return image, nil
+}
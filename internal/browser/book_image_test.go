@@ -0,0 +1,97 @@
+package browser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestResolveBookFilePath(t *testing.T) {
+	chapterURL := "https://learning.oreilly.com/api/v2/epubs/urn:orm:book:123/files/OEBPS/ch01.html"
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"assets/fig1.png", "OEBPS/assets/fig1.png"},
+		{"../images/fig 2.png", "images/fig 2.png"},
+		{"/api/v2/epubs/urn:orm:book:123/files/cover.jpg", "cover.jpg"},
+		{"https://learning.oreilly.com/api/v2/epubs/urn:orm:book:123/files/a/b.svg", "a/b.svg"},
+		{"https://cdn.example.com/fig.png", ""},
+		{"/api/v2/epubs/urn:orm:book:999/files/fig.png", ""},
+		{"../../../../fig.png", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ResolveBookFilePath("123", chapterURL, tt.ref), tt.ref)
+	}
+}
+
+func TestImageMIMEType(t *testing.T) {
+	assert.Equal(t, "image/png", imageMIMEType("fig.bin", "image/png; charset=binary", nil))
+	assert.Equal(t, "image/svg+xml", imageMIMEType("fig.svg", "application/octet-stream", []byte("<svg/>")))
+	assert.Equal(t, "image/png", imageMIMEType("fig", "", pngHeader))
+	assert.Equal(t, "", imageMIMEType("ch01.html", "text/html", []byte("<html></html>")))
+}
+
+// imageDoer serves files/assets/fig1.png and files/ch01.html.
+type imageDoer struct {
+	calls atomic.Int32
+}
+
+func (d *imageDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls.Add(1)
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/files/assets/fig1.png"):
+		resp.Header.Set("Content-Type", "image/png")
+		resp.Body = io.NopCloser(strings.NewReader(string(pngHeader)))
+	case strings.HasSuffix(req.URL.Path, "/files/ch01.html"):
+		resp.Header.Set("Content-Type", "text/html")
+		resp.Body = io.NopCloser(strings.NewReader("<html></html>"))
+	default:
+		resp.StatusCode = http.StatusNotFound
+		resp.Body = io.NopCloser(strings.NewReader(""))
+	}
+	return resp, nil
+}
+
+func TestGetBookImage(t *testing.T) {
+	doer := &imageDoer{}
+	store := NewContentStore(t.TempDir())
+	bc := &BrowserClient{
+		httpClient:    doer,
+		cookieManager: NewMockCookieManager(),
+		contentStore:  store,
+	}
+	ctx := context.Background()
+
+	image, err := bc.GetBookImage(ctx, "123", "assets/fig1.png")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", image.MIMEType)
+	assert.Equal(t, pngHeader, image.Data)
+	assert.Equal(t, "https://learning.oreilly.com/api/v2/epubs/urn:orm:book:123/files/assets/fig1.png", image.SourceURL)
+
+	// Served from the content store the second time
+	_, err = bc.GetBookImage(ctx, "123", "assets/fig1.png")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), doer.calls.Load())
+
+	stale, err := store.StaleBookImage("123", "assets/fig1.png")
+	require.NoError(t, err)
+	assert.Equal(t, true, stale.Metadata["stale"])
+
+	_, err = bc.GetBookImage(ctx, "123", "ch01.html")
+	assert.ErrorIs(t, err, ErrNotImage)
+
+	_, err = bc.GetBookImage(ctx, "123", "../")
+	assert.Error(t, err)
+}
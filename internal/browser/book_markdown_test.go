@@ -0,0 +1,61 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+func TestRenderMarkdown_Elements(t *testing.T) {
+	r := mustParse(t, `<html><head><title>Chapter 1. Basics</title></head><body>
+		<h1>Chapter 1. Basics</h1><p>Intro   paragraph</p>
+		<h2>Code</h2><pre><code class="language-go">fmt.Println("hi")</code></pre>
+		<ul><li>one</li><li>two</li></ul>
+		<ol><li>first</li><li>second</li></ol>
+		<img src="fig1.png" alt="Figure 1">
+		<a href="https://example.com">Example</a>
+	</body></html>`)
+
+	md := htmlparse.RenderMarkdown(r)
+
+	wants := []string{
+		"# Chapter 1. Basics\n\nIntro   paragraph\n\n",
+		"## Code\n\n```go\nfmt.Println(\"hi\")\n```\n\n",
+		"- one\n- two\n\n",
+		"1. first\n2. second\n\n",
+		"![Figure 1](fig1.png)\n\n",
+		"[Example](https://example.com)\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n---\n%s", want, md)
+		}
+	}
+	if strings.Count(md, "# Chapter 1. Basics") != 1 {
+		t.Errorf("title should not be duplicated when it matches the first heading\n---\n%s", md)
+	}
+}
+
+func TestRenderMarkdown_TitleWithoutHeading(t *testing.T) {
+	md := htmlparse.RenderMarkdown(&htmlparse.ParsedChapterContent{
+		Title: "Preface",
+		Sections: []htmlparse.ContentSection{{
+			Content: []any{htmlparse.ParagraphElement{Type: "paragraph", Text: "Hello"}},
+		}},
+	})
+	if md != "# Preface\n\nHello\n" {
+		t.Errorf("unexpected markdown: %q", md)
+	}
+}
+
+func TestRenderMarkdown_CodeFenceEscapesBackticks(t *testing.T) {
+	md := htmlparse.RenderMarkdown(&htmlparse.ParsedChapterContent{
+		Sections: []htmlparse.ContentSection{{
+			Content: []any{htmlparse.CodeBlockElement{Type: "code_block", Language: "md", Code: "```\nnested\n```"}},
+		}},
+	})
+	if !strings.HasPrefix(md, "````md\n") || !strings.HasSuffix(md, "\n````\n") {
+		t.Errorf("expected a four-backtick fence, got %q", md)
+	}
+}
@@ -0,0 +1,91 @@
+package browser
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// assertGolden compares got with testdata/name, rewriting the file when -update is set.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0600); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch (run with -update to accept)\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestParseHTMLContent_OReillyChapterGolden(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "oreilly_chapter.xhtml"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	parsed := mustParse(t, string(src))
+
+	gotJSON, err := json.MarshalIndent(parsed, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal parsed content: %v", err)
+	}
+	assertGolden(t, "oreilly_chapter.golden.json", append(gotJSON, '\n'))
+	assertGolden(t, "oreilly_chapter.golden.md", []byte(htmlparse.RenderMarkdown(parsed)))
+}
+
+func TestParseHTMLContent_NewElementTypes(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "oreilly_chapter.xhtml"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	parsed := mustParse(t, string(src))
+
+	counts := map[string]int{}
+	var nested *htmlparse.ListItem
+	for _, section := range parsed.Sections {
+		for _, item := range section.Content {
+			switch e := item.(type) {
+			case htmlparse.TableElement:
+				counts["table"]++
+				if len(e.Headers) != 3 || len(e.Rows) != 2 {
+					t.Errorf("unexpected table shape: headers=%v rows=%v", e.Headers, e.Rows)
+				}
+			case htmlparse.AdmonitionElement:
+				counts[e.Kind]++
+			case htmlparse.FootnoteElement:
+				counts["footnote"]++
+				if e.Label != "1" || e.Text != "Even stateless services keep state somewhere else." {
+					t.Errorf("unexpected footnote: %+v", e)
+				}
+			case htmlparse.ListElement:
+				if len(e.Items) > 0 && len(e.Items[0].Children) > 0 {
+					nested = &e.Items[0]
+				}
+			}
+		}
+	}
+
+	for _, kind := range []string{"table", "note", "warning", "tip", "sidebar", "footnote"} {
+		if counts[kind] != 1 {
+			t.Errorf("expected 1 %s, got %d", kind, counts[kind])
+		}
+	}
+	if nested == nil {
+		t.Fatal("expected a list item with a nested list")
+	}
+	if nested.Text != "Access patterns" || len(nested.Children[0].Items) != 2 {
+		t.Errorf("unexpected nested list item: %+v", nested)
+	}
+}
@@ -1,31 +1,85 @@
 package browser
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
 )
 
 // GetBookChapterContent retrieves and parses chapter content from O'Reilly book
-func (bc *BrowserClient) GetBookChapterContent(productID, chapterName string) (*ChapterContentResponse, error) {
+func (bc *BrowserClient) GetBookChapterContent(ctx context.Context, productID, chapterName string) (*ChapterContentResponse, error) {
 	slog.Info("チャプター本文を取得しています", "product_id", productID, "chapter_name", chapterName)
 
-	// Step 1: Get chapter title from TOC
-	chapterTitle, err := bc.getChapterTitleFromTOC(productID, chapterName)
+	// Step 1: Look up the chapter in the TOC once for both title and href
+	item, err := bc.findTOCItem(ctx, productID, chapterName)
 	if err != nil {
-		slog.Warn("TOCからタイトル取得に失敗、チャプター名を使用", "error", err, "chapter_name", chapterName)
-		chapterTitle = chapterName
+		return nil, fmt.Errorf("チャプターHTML取得失敗: failed to get chapter href from TOC: %w", err)
 	}
 
-	// Step 2: Get raw HTML content from API via flat-toc
-	htmlContent, contentURL, err := bc.GetChapterHTMLContent(productID, chapterName)
+	// Step 2: Get raw HTML content from the chapter href
+	htmlContent, contentURL, err := bc.getChapterHTML(ctx, productID, item)
 	if err != nil {
 		return nil, fmt.Errorf("チャプターHTML取得失敗: %w", err)
 	}
 
+	response, err := buildChapterContent(productID, chapterName, item, htmlContent, contentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("チャプター本文取得に成功しました",
+		"title", response.ChapterTitle,
+		"section_count", len(response.Content.Sections))
+
+	bc.indexChapter(productID, item, response)
+	return response, nil
+}
+
+// indexChapter adds the sections of a parsed chapter to the full-text index,
+// keyed by the chapter's file so that any name resolving to it replaces the same entry.
+func (bc *BrowserClient) indexChapter(productID string, item *TableOfContentsItem, chapter *ChapterContentResponse) {
+	if bc.fulltextIndex == nil {
+		return
+	}
+	chapterName := hrefFile(item.Href)
+	if chapterName == "" {
+		chapterName = item.ID
+	}
+	bookTitle := bc.knownBookTitle(productID)
+
+	docs := make([]fulltext.Document, 0, len(chapter.Content.Sections))
+	for i, section := range chapter.Content.Sections {
+		text := htmlparse.SectionText(section)
+		if text == "" && section.Heading.Text == "" {
+			continue
+		}
+		docs = append(docs, fulltext.Document{
+			BookID:       productID,
+			BookTitle:    bookTitle,
+			ChapterName:  chapterName,
+			ChapterTitle: chapter.ChapterTitle,
+			SectionIndex: i,
+			Heading:      section.Heading.Text,
+			Text:         text,
+		})
+	}
+	if err := bc.fulltextIndex.IndexChapter(productID, chapterName, docs); err != nil {
+		slog.Warn("全文検索インデックスへの登録に失敗しました", "product_id", productID, "chapter", chapterName, "error", err)
+	}
+}
+
+// buildChapterContent parses chapter HTML into a ChapterContentResponse.
+func buildChapterContent(productID, chapterName string, item *TableOfContentsItem, htmlContent, contentURL string) (*ChapterContentResponse, error) {
+	chapterTitle := item.Title
+	if chapterTitle == "" {
+		chapterTitle = chapterName
+	}
+
 	// Parse HTML content into structured format
 	parsedContent, err := htmlparse.ParseHTMLContent(htmlContent)
 	if err != nil {
@@ -39,7 +93,7 @@ func (bc *BrowserClient) GetBookChapterContent(productID, chapterName string) (*
 		chapterTitle = parsedContent.Sections[0].Heading.Text
 	}
 
-	response := &ChapterContentResponse{
+	return &ChapterContentResponse{
 		BookID:       productID,
 		ChapterName:  chapterName,
 		ChapterTitle: chapterTitle,
@@ -50,59 +104,95 @@ func (bc *BrowserClient) GetBookChapterContent(productID, chapterName string) (*
 			"processed_at":      time.Now().UTC().Format(time.RFC3339),
 			"word_count":        htmlparse.CountWordsFromSections(parsedContent.Sections),
 		},
-	}
-
-	slog.Info("チャプター本文取得に成功しました",
-		"title", chapterTitle,
-		"section_count", len(parsedContent.Sections))
-
-	return response, nil
+	}, nil
 }
 
 // GetChapterHTMLContent retrieves actual HTML content from O'Reilly API via flat-toc lookup
-func (bc *BrowserClient) GetChapterHTMLContent(productID, chapterName string) (string, string, error) {
+func (bc *BrowserClient) GetChapterHTMLContent(ctx context.Context, productID, chapterName string) (string, string, error) {
 	// Step 1: Get chapter href from flat-toc
-	chapterHref, err := bc.getChapterHrefFromTOC(productID, chapterName)
+	item, err := bc.findTOCItem(ctx, productID, chapterName)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get chapter href from TOC: %w", err)
 	}
 
 	// Step 2: Get actual HTML content from the href URL
-	htmlContent, err := bc.GetContentFromURL(chapterHref)
+	return bc.getChapterHTML(ctx, productID, item)
+}
+
+// getChapterHTML downloads the HTML of a TOC item and returns it with the resolved URL.
+// A copy is kept in the content store; when it carries an ETag the download is
+// revalidated with If-None-Match and the stored HTML is reused on 304.
+func (bc *BrowserClient) getChapterHTML(ctx context.Context, productID string, item *TableOfContentsItem) (string, string, error) {
+	chapterHref := resolveChapterHref(item.Href, productID)
+	storeKey := chapterStoreKey(productID, item.Href)
+
+	var etag string
+	if entry, err := bc.contentStore.Lookup(storeKey); err == nil {
+		etag = entry.ETag
+	}
+
+	content, err := bc.fetchContent(ctx, chapterHref, etag)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get HTML content from %s: %w", chapterHref, err)
 	}
 
+	htmlContent := content.body
+	if content.notModified {
+		stored, _, err := bc.contentStore.Get(storeKey)
+		if err != nil {
+			// 保存済みHTMLが読めない場合は条件なしで再取得する
+			if content, err = bc.fetchContent(ctx, chapterHref, ""); err != nil {
+				return "", "", fmt.Errorf("failed to get HTML content from %s: %w", chapterHref, err)
+			}
+			stored = []byte(content.body)
+		} else {
+			slog.Debug("チャプターHTMLは未更新のため保存済みのものを使用します", "href", chapterHref)
+		}
+		htmlContent = string(stored)
+	}
+
+	if err := bc.contentStore.Put(storeKey, []byte(htmlContent), chapterHref, content.etag); err != nil {
+		slog.Warn("コンテンツストアへの保存に失敗しました", "key", storeKey, "error", err)
+	}
+
 	slog.Debug("チャプターHTML取得に成功しました", "href", chapterHref, "content_size", len(htmlContent))
 	return htmlContent, chapterHref, nil
 }
 
-// findTOCItem searches the book's TOC for a matching chapter by exact or partial match.
-func (bc *BrowserClient) findTOCItem(productID, chapterName string) (*TableOfContentsItem, error) {
-	toc, err := bc.getBookTOC(productID)
+// findTOCItem resolves chapterName against the book's TOC (see resolveTOCItem).
+// When the chapter does not resolve in a cached TOC, the cache entry may be stale,
+// so it is invalidated and the lookup is retried once against a fresh TOC.
+func (bc *BrowserClient) findTOCItem(ctx context.Context, productID, chapterName string) (*TableOfContentsItem, error) {
+	_, cached := bc.tocCache.peek(productID)
+
+	toc, err := bc.GetBookTOC(ctx, productID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get book TOC: %w", err)
 	}
-
-	// Exact match: ID or href containing the chapter name
-	for _, item := range toc.TableOfContents {
-		if item.ID == chapterName ||
-			strings.Contains(item.Href, chapterName) ||
-			strings.HasSuffix(item.Href, chapterName+".html") ||
-			strings.HasSuffix(item.Href, chapterName+".xhtml") {
-			return &item, nil
-		}
+	item, err := resolveTOCItem(toc, productID, chapterName)
+	if err == nil || !cached {
+		return item, err
 	}
 
-	// Partial match: case-insensitive search in href or ID
-	for _, item := range toc.TableOfContents {
-		if strings.Contains(strings.ToLower(item.Href), strings.ToLower(chapterName)) ||
-			strings.Contains(strings.ToLower(item.ID), strings.ToLower(chapterName)) {
-			return &item, nil
-		}
+	slog.Debug("キャッシュ済み目次でチャプターを特定できないため再取得します", "product_id", productID, "chapter_name", chapterName)
+	bc.tocCache.remove(productID)
+	if toc, err = bc.GetBookTOC(ctx, productID); err != nil {
+		return nil, fmt.Errorf("failed to get book TOC: %w", err)
 	}
+	return resolveTOCItem(toc, productID, chapterName)
+}
 
-	return nil, fmt.Errorf("chapter '%s' not found in TOC for book %s", chapterName, productID)
+// knownBookTitle returns the book title from cached or stored book details
+// without contacting the server, or "" when the details were never fetched.
+func (bc *BrowserClient) knownBookTitle(productID string) string {
+	if details, ok := bc.detailsCache.peek(productID); ok {
+		return details.Title
+	}
+	var details BookDetailResponse
+	if _, err := bc.contentStore.getJSON(detailsStoreKey(productID), &details); err == nil {
+		return details.Title
+	}
+	return ""
 }
 
 // resolveChapterHref converts a TOC item's href to a full URL.
@@ -116,25 +206,12 @@ func resolveChapterHref(href, productID string) string {
 	return href
 }
 
-// getChapterHrefFromTOC retrieves chapter href URL from flat-toc
-func (bc *BrowserClient) getChapterHrefFromTOC(productID, chapterName string) (string, error) {
-	slog.Debug("flat-tocからチャプターhrefを取得しています", "product_id", productID, "chapter_name", chapterName)
-
-	item, err := bc.findTOCItem(productID, chapterName)
-	if err != nil {
-		return "", err
-	}
-
-	href := resolveChapterHref(item.Href, productID)
-	slog.Debug("チャプターhref取得に成功しました", "chapter_name", chapterName, "href", href)
-	return href, nil
-}
-
-// getChapterTitleFromTOC retrieves chapter title from flat-toc
-func (bc *BrowserClient) getChapterTitleFromTOC(productID, chapterName string) (string, error) {
-	item, err := bc.findTOCItem(productID, chapterName)
-	if err != nil {
-		return "", err
+// Markdown renders the chapter body as Markdown, using ChapterTitle when the
+// parsed content has no title of its own.
+func (c *ChapterContentResponse) Markdown() string {
+	content := c.Content
+	if content.Title == "" {
+		content.Title = c.ChapterTitle
 	}
-	return item.Title, nil
+	return htmlparse.RenderMarkdown(&content)
 }
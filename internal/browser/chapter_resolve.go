@@ -0,0 +1,292 @@
+package browser
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scores of the ways a chapter name can match a TOC entry. A name resolves
+// when the best entry scores at least minChapterScore and no entry from a
+// different file comes within chapterScoreMargin of it.
+const (
+	scoreExactRef   = 1.0  // ID, href or file name
+	scoreNumber     = 0.9  // chapter number, ordinal word or appendix letter
+	scoreTitle      = 0.85 // whole title, with or without its number label
+	scorePhrase     = 0.7  // title contains the name as a phrase
+	scoreTitleWords = 0.6  // scaled by the share of the name's words found in the title
+	scoreSubstring  = 0.4  // name is a substring of the href or ID; suggestion only
+
+	minChapterScore      = 0.6
+	chapterScoreMargin   = 0.1
+	maxChapterCandidates = 5
+)
+
+var (
+	// reChapterNumber matches numeric labels such as "Chapter 3.", "3. Title" or "3.2 Title".
+	reChapterNumber = regexp.MustCompile(`^(?:Chapter\s+)?(\d+(?:\.\d+)*)\.?(?:\s|$)`)
+	// reAppendixLetter matches appendix labels such as "Appendix A."
+	reAppendixLetter = regexp.MustCompile(`^Appendix\s+([A-Z])\b`)
+
+	// reNumberQuery matches chapter names such as "3", "ch3", "ch03" or "chapter 3".
+	reNumberQuery = regexp.MustCompile(`^(?:ch(?:apter)?[\s._-]*)?0*(\d+)$`)
+	// reAppendixQuery matches appendix names such as "appendix a" or "app-b".
+	reAppendixQuery = regexp.MustCompile(`^app(?:endix)?[\s._-]*([a-z])$`)
+	// reChapterStem matches file stems such as "ch03" or "chapter-3".
+	reChapterStem = regexp.MustCompile(`^ch(?:apter)?[_-]?0*(\d+)$`)
+	// reAppendixStem matches file stems such as "app01", "appa" or "appendix-b".
+	reAppendixStem = regexp.MustCompile(`^app(?:endix)?[_-]?(?:0*(\d+)|([a-z]))$`)
+)
+
+var chapterNumberWords = func() map[string]int {
+	cardinals := []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen", "twenty"}
+	ordinals := []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth",
+		"eleventh", "twelfth", "thirteenth", "fourteenth", "fifteenth", "sixteenth", "seventeenth", "eighteenth", "nineteenth", "twentieth"}
+	words := make(map[string]int, len(cardinals)+len(ordinals))
+	for i := range cardinals {
+		words[cardinals[i]] = i + 1
+		words[ordinals[i]] = i + 1
+	}
+	return words
+}()
+
+// ChapterNumberFromTitle extracts the chapter number label from a TOC title,
+// e.g. "3" from "Chapter 3. Replication", "3.2" from "3.2 Partitioning" or
+// "A" from "Appendix A. Glossary". It returns "" for unnumbered titles.
+func ChapterNumberFromTitle(title string) string {
+	number, _ := splitChapterLabel(title)
+	return number
+}
+
+// splitChapterLabel splits a TOC title into its number label and the rest of the title.
+func splitChapterLabel(title string) (number, rest string) {
+	title = strings.TrimSpace(title)
+	if m := reChapterNumber.FindStringSubmatch(title); m != nil {
+		return m[1], strings.TrimSpace(title[len(m[0]):])
+	}
+	if m := reAppendixLetter.FindStringSubmatch(title); m != nil {
+		return m[1], strings.TrimLeft(strings.TrimSpace(title[len(m[0]):]), ".: ")
+	}
+	return "", title
+}
+
+// ChapterCandidate is a TOC entry considered when resolving a chapter name.
+type ChapterCandidate struct {
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Href  string  `json:"href"`
+	Score float64 `json:"score"`
+}
+
+// ChapterNotFoundError is returned when a chapter name does not resolve to a
+// single TOC entry. Candidates holds the closest entries, best first.
+type ChapterNotFoundError struct {
+	ProductID   string
+	ChapterName string
+	Ambiguous   bool
+	Candidates  []ChapterCandidate
+}
+
+func (e *ChapterNotFoundError) Error() string {
+	var b strings.Builder
+	if e.Ambiguous {
+		fmt.Fprintf(&b, "chapter '%s' is ambiguous in TOC for book %s", e.ChapterName, e.ProductID)
+	} else {
+		fmt.Fprintf(&b, "chapter '%s' not found in TOC for book %s", e.ChapterName, e.ProductID)
+	}
+	for i, c := range e.Candidates {
+		if i == 0 {
+			b.WriteString("; candidates: ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q (%s)", c.Title, c.Href)
+	}
+	return b.String()
+}
+
+// chapterQuery is a chapter name normalized for matching.
+type chapterQuery struct {
+	raw    string
+	lower  string
+	number string // chapter number or upper-case appendix letter, "" if the name is not one
+	words  []string
+}
+
+func newChapterQuery(name string) chapterQuery {
+	q := chapterQuery{raw: name, lower: strings.ToLower(strings.TrimSpace(name))}
+	q.words = titleWords(q.lower)
+
+	if m := reNumberQuery.FindStringSubmatch(q.lower); m != nil {
+		q.number = m[1]
+		return q
+	}
+	if m := reAppendixQuery.FindStringSubmatch(q.lower); m != nil {
+		q.number = strings.ToUpper(m[1])
+		return q
+	}
+	// "third", "chapter three", "the third chapter"
+	var rest []string
+	for _, w := range q.words {
+		if w != "chapter" && w != "ch" && w != "the" {
+			rest = append(rest, w)
+		}
+	}
+	if len(rest) == 1 {
+		if n, ok := chapterNumberWords[rest[0]]; ok {
+			q.number = strconv.Itoa(n)
+		}
+	}
+	return q
+}
+
+// titleWords lower-cases s and splits it into letter/digit runs.
+func titleWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stemChapterNumber derives a chapter number or appendix letter from a file
+// stem; appendix files numbered from 1 map to letters from A.
+func stemChapterNumber(stem string) string {
+	if m := reChapterStem.FindStringSubmatch(stem); m != nil {
+		return m[1]
+	}
+	if m := reAppendixStem.FindStringSubmatch(stem); m != nil {
+		if m[2] != "" {
+			return strings.ToUpper(m[2])
+		}
+		if n, err := strconv.Atoi(m[1]); err == nil && n >= 1 && n <= 26 {
+			return string(rune('A' + n - 1))
+		}
+	}
+	return ""
+}
+
+// hrefFile strips the fragment from a TOC href.
+func hrefFile(href string) string {
+	file, _, _ := strings.Cut(href, "#")
+	return file
+}
+
+// scoreTOCItem scores how well item matches q; 0 means no match.
+func scoreTOCItem(item *TableOfContentsItem, q chapterQuery) float64 {
+	if q.lower == "" {
+		return 0
+	}
+	file := strings.ToLower(hrefFile(item.Href))
+	base := path.Base(file)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	if item.ID == q.raw || item.Href == q.raw || (file != "" &&
+		(q.lower == strings.ToLower(item.Href) || q.lower == file || q.lower == base || q.lower == stem ||
+			q.lower == strings.TrimSuffix(file, path.Ext(file)))) {
+		return scoreExactRef
+	}
+
+	number, rest := splitChapterLabel(item.Title)
+	if q.number != "" {
+		if number == q.number {
+			return scoreNumber
+		}
+		if stemChapterNumber(stem) == q.number {
+			return scoreNumber
+		}
+	}
+
+	var score float64
+	// Numbers alone say nothing about titles; only name-like queries are matched against them
+	if q.number == "" && len(q.words) > 0 {
+		title := strings.Join(titleWords(item.Title), " ")
+		phrase := strings.Join(q.words, " ")
+		switch {
+		case title == phrase || strings.Join(titleWords(rest), " ") == phrase:
+			return scoreTitle
+		case strings.Contains(" "+title+" ", " "+phrase+" "):
+			return scorePhrase
+		}
+		found := 0
+		tw := titleWords(item.Title)
+		for _, w := range q.words {
+			if slices.Contains(tw, w) {
+				found++
+			}
+		}
+		score = scoreTitleWords * float64(found) / float64(len(q.words))
+	}
+	if score < scoreSubstring && (strings.Contains(strings.ToLower(item.Href), q.lower) || strings.Contains(strings.ToLower(item.ID), q.lower)) {
+		score = scoreSubstring
+	}
+	return score
+}
+
+// resolveTOCItem resolves chapterName to a TOC item. It accepts the item's ID
+// or href, a chapter number ("3", "ch03", "third"), an appendix letter or a
+// title phrase. Entries sharing a file (a chapter and its sections) are
+// scored as one; the chapter itself is preferred over its fragments. When the
+// name is missing or ambiguous a *ChapterNotFoundError lists the closest entries.
+func resolveTOCItem(toc *TableOfContentsResponse, productID, chapterName string) (*TableOfContentsItem, error) {
+	type group struct {
+		item  *TableOfContentsItem
+		score float64
+	}
+	q := newChapterQuery(chapterName)
+	var groups []*group
+	byFile := map[string]*group{}
+
+	for i := range toc.TableOfContents {
+		item := &toc.TableOfContents[i]
+		score := scoreTOCItem(item, q)
+		if score == 0 {
+			continue
+		}
+		file := hrefFile(item.Href)
+		if file == "" {
+			file = item.ID
+		}
+		g, ok := byFile[file]
+		if !ok {
+			g = &group{item: item, score: score}
+			byFile[file] = g
+			groups = append(groups, g)
+			continue
+		}
+		if score > g.score || (score == g.score && strings.Contains(g.item.Href, "#") && !strings.Contains(item.Href, "#")) {
+			g.item, g.score = item, score
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b *group) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		}
+		return 0
+	})
+
+	if len(groups) > 0 && groups[0].score >= minChapterScore &&
+		(len(groups) == 1 || groups[1].score <= groups[0].score-chapterScoreMargin) {
+		return groups[0].item, nil
+	}
+
+	notFound := &ChapterNotFoundError{
+		ProductID:   productID,
+		ChapterName: chapterName,
+		Ambiguous:   len(groups) > 1 && groups[0].score >= minChapterScore,
+	}
+	for _, g := range groups[:min(len(groups), maxChapterCandidates)] {
+		notFound.Candidates = append(notFound.Candidates, ChapterCandidate{
+			ID:    g.item.ID,
+			Title: g.item.Title,
+			Href:  g.item.Href,
+			Score: g.score,
+		})
+	}
+	return nil, notFound
+}
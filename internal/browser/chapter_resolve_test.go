@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResolveTOC() *TableOfContentsResponse {
+	items := []TableOfContentsItem{
+		{ID: "123-/preface01.html", Title: "Preface", Href: "preface01.html"},
+		{ID: "123-/ch01.html", Title: "1. Reliable, Scalable, and Maintainable Applications", Href: "ch01.html"},
+		{ID: "123-/ch01.html#sec-reliability", Title: "Reliability", Href: "ch01.html#sec-reliability", Parent: "123-/ch01.html"},
+		{ID: "123-/ch02.html", Title: "2. Data Models and Query Languages", Href: "ch02.html"},
+		{ID: "123-/ch03.html", Title: "Chapter 3. Storage and Retrieval", Href: "ch03.html"},
+		{ID: "123-/ch09.html", Title: "9. Consistency and Consensus", Href: "ch09.html"},
+		{ID: "123-/ch09.html#sec-consensus", Title: "Distributed Transactions and Consensus", Href: "ch09.html#sec-consensus", Parent: "123-/ch09.html"},
+		{ID: "123-/ch10.html", Title: "10. Batch Processing", Href: "ch10.html"},
+		{ID: "123-/ch11.html", Title: "11. Stream Processing", Href: "ch11.html"},
+		{ID: "123-/app01.html", Title: "Appendix A. Glossary", Href: "app01.html"},
+	}
+	return &TableOfContentsResponse{BookID: "123", TableOfContents: items}
+}
+
+func TestResolveTOCItem(t *testing.T) {
+	toc := newResolveTOC()
+	tests := []struct {
+		name     string
+		wantHref string
+	}{
+		{"123-/ch02.html", "ch02.html"},
+		{"ch02.html", "ch02.html"},
+		{"ch02", "ch02.html"},
+		{"CH02", "ch02.html"},
+		{"ch1", "ch01.html"}, // not ch10 or ch11
+		{"1", "ch01.html"},
+		{"3", "ch03.html"},
+		{"chapter 10", "ch10.html"},
+		{"third", "ch03.html"},
+		{"chapter eleven", "ch11.html"},
+		{"Appendix A", "app01.html"},
+		{"Glossary", "app01.html"},
+		{"storage and retrieval", "ch03.html"},
+		{"Consensus", "ch09.html"}, // chapter preferred over its own section
+		{"stream processing", "ch11.html"},
+		{"ch01.html#sec-reliability", "ch01.html#sec-reliability"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := resolveTOCItem(toc, "123", tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHref, item.Href)
+		})
+	}
+}
+
+func TestResolveTOCItem_Ambiguous(t *testing.T) {
+	_, err := resolveTOCItem(newResolveTOC(), "123", "processing")
+
+	var notFound *ChapterNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.True(t, notFound.Ambiguous)
+	require.Len(t, notFound.Candidates, 2)
+	assert.ElementsMatch(t, []string{"ch10.html", "ch11.html"},
+		[]string{notFound.Candidates[0].Href, notFound.Candidates[1].Href})
+	assert.Contains(t, err.Error(), "is ambiguous")
+}
+
+func TestResolveTOCItem_NotFoundSuggestsCandidates(t *testing.T) {
+	toc := newResolveTOC()
+
+	_, err := resolveTOCItem(toc, "123", "ch99")
+	var notFound *ChapterNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.False(t, notFound.Ambiguous)
+	assert.Empty(t, notFound.Candidates)
+	assert.Equal(t, "chapter 'ch99' not found in TOC for book 123", err.Error())
+
+	// Partial word overlap is only a suggestion
+	_, err = resolveTOCItem(toc, "123", "stream joins")
+	require.ErrorAs(t, err, &notFound)
+	require.NotEmpty(t, notFound.Candidates)
+	assert.Equal(t, "ch11.html", notFound.Candidates[0].Href)
+	assert.Less(t, notFound.Candidates[0].Score, minChapterScore)
+}
+
+func TestChapterNumberFromTitle(t *testing.T) {
+	tests := map[string]string{
+		"1. Introduction":          "1",
+		"Chapter 12. Streams":      "12",
+		"3.2 Partitioning":         "3.2",
+		"Part II. Derived Data":    "",
+		"Part 2. Derived Data":     "",
+		"Appendix B. Bibliography": "B",
+		"A Tour of Go":             "",
+		"Preface":                  "",
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, ChapterNumberFromTitle(title), title)
+	}
+}
+
+func TestStaleBookChapterContent_AmbiguousChapter(t *testing.T) {
+	store := NewContentStore(t.TempDir())
+	store.putJSON(tocStoreKey("123"), newResolveTOC(), "", "")
+
+	_, err := store.StaleBookChapterContent("123", "processing")
+	var notFound *ChapterNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.True(t, notFound.Ambiguous)
+	assert.ErrorIs(t, err, ErrNotStored)
+}
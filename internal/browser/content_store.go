@@ -0,0 +1,356 @@
+package browser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fsutil"
+)
+
+// ErrNotStored is returned when the content store has no entry for a key.
+var ErrNotStored = errors.New("content not found in local store")
+
+// StoredEntry describes a piece of content saved in the ContentStore.
+type StoredEntry struct {
+	Key       string    `json:"key"`
+	Hash      string    `json:"hash"` // SHA-256 of the blob
+	URL       string    `json:"url,omitempty"`
+	ETag      string    `json:"etag,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Size      int       `json:"size"`
+}
+
+// ContentStore is a content-addressed on-disk store for fetched book content.
+//
+// Blobs are written to objects/<hh>/<sha256> and each logical key (book details,
+// TOC, chapter HTML) points at a blob through a small ref file under refs/.
+// Writes go through a temp file and rename, so concurrent readers never observe
+// partial content. Prune bounds its age and size. A nil *ContentStore is valid
+// and stores nothing.
+type ContentStore struct {
+	dir string
+	now func() time.Time
+	mu  sync.RWMutex // Put holds it shared, Prune exclusively
+}
+
+// NewContentStore returns a store rooted at dir.
+func NewContentStore(dir string) *ContentStore {
+	return &ContentStore{dir: dir, now: time.Now}
+}
+
+// OpenContentStore returns the store described by opts, or nil when it is disabled.
+func OpenContentStore(opts config.ContentStoreOpts) *ContentStore {
+	if !opts.Enabled || opts.Dir == "" {
+		return nil
+	}
+	return NewContentStore(opts.Dir)
+}
+
+// Content store keys for each kind of book content.
+func detailsStoreKey(productID string) string { return "book-details/" + productID }
+func tocStoreKey(productID string) string     { return "book-toc/" + productID }
+func chapterStoreKey(productID, href string) string {
+	return "book-chapter/" + productID + "/" + href
+}
+func imageStoreKey(productID, imagePath string) string {
+	return "book-image/" + productID + "/" + imagePath
+}
+
+// Put stores data under key together with its source URL and ETag.
+func (s *ContentStore) Put(key string, data []byte, url, etag string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	blobPath := s.blobPath(hash)
+	if _, err := os.Stat(blobPath); errors.Is(err, os.ErrNotExist) {
+		if err := fsutil.WriteFileAtomic(blobPath, data, 0700); err != nil {
+			return fmt.Errorf("failed to write blob: %w", err)
+		}
+	} else {
+		// Mark the shared blob as in use so that a concurrent Prune keeps it
+		now := s.now()
+		_ = os.Chtimes(blobPath, now, now)
+	}
+
+	entry := StoredEntry{
+		Key:       key,
+		Hash:      hash,
+		URL:       url,
+		ETag:      etag,
+		FetchedAt: s.now().UTC(),
+		Size:      len(data),
+	}
+	refBytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ref: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(s.refPath(key), refBytes, 0700); err != nil {
+		return fmt.Errorf("failed to write ref: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the metadata stored for key without reading the blob.
+func (s *ContentStore) Lookup(key string) (*StoredEntry, error) {
+	if s == nil {
+		return nil, ErrNotStored
+	}
+	refBytes, err := os.ReadFile(s.refPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotStored
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ref: %w", err)
+	}
+	var entry StoredEntry
+	if err := json.Unmarshal(refBytes, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse ref: %w", err)
+	}
+	return &entry, nil
+}
+
+// Get returns the blob and metadata stored for key.
+func (s *ContentStore) Get(key string) ([]byte, *StoredEntry, error) {
+	entry, err := s.Lookup(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(s.blobPath(entry.Hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrNotStored
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, entry, nil
+}
+
+// putJSON stores v as JSON under key, logging instead of failing on error.
+func (s *ContentStore) putJSON(key string, v any, url, etag string) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("コンテンツストアへの保存に失敗しました", "key", key, "error", err)
+		return
+	}
+	if err := s.Put(key, data, url, etag); err != nil {
+		slog.Warn("コンテンツストアへの保存に失敗しました", "key", key, "error", err)
+	}
+}
+
+// getJSON decodes the JSON stored under key into v.
+func (s *ContentStore) getJSON(key string, v any) (*StoredEntry, error) {
+	data, entry, err := s.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("failed to decode stored %s: %w", key, err)
+	}
+	return entry, nil
+}
+
+// pruneGracePeriod protects blobs and temp files written this recently from
+// Prune, as a Put from another process sharing the directory (such as the
+// --export command) may not have written the ref to them yet.
+const pruneGracePeriod = time.Hour
+
+// PruneResult reports what Prune removed.
+type PruneResult struct {
+	Refs  int   // entries removed for their age or the size limit
+	Blobs int   // blobs and leftover temp files removed
+	Bytes int64 // size of the removed blobs
+}
+
+// storedRef is a ref file and the entry it holds.
+type storedRef struct {
+	path  string
+	entry StoredEntry
+}
+
+// Prune removes entries fetched more than maxAge ago, then the oldest entries
+// until the content still referenced totals at most maxBytes, and finally the
+// blobs no entry refers to. A zero maxAge or maxBytes disables that limit.
+func (s *ContentStore) Prune(maxAge time.Duration, maxBytes int64) (PruneResult, error) {
+	var result PruneResult
+	if s == nil {
+		return result, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refs, err := s.loadRefs()
+	if err != nil {
+		return result, err
+	}
+	slices.SortFunc(refs, func(a, b storedRef) int { return a.entry.FetchedAt.Compare(b.entry.FetchedAt) })
+
+	refCount := map[string]int{}
+	var total int64
+	for _, r := range refs {
+		if refCount[r.entry.Hash] == 0 {
+			total += int64(r.entry.Size)
+		}
+		refCount[r.entry.Hash]++
+	}
+
+	now := s.now()
+	for _, r := range refs { // oldest first
+		expired := maxAge > 0 && now.Sub(r.entry.FetchedAt) > maxAge
+		overSize := maxBytes > 0 && total > maxBytes
+		if !expired && !overSize {
+			break
+		}
+		if err := os.Remove(r.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return result, fmt.Errorf("failed to remove ref: %w", err)
+		}
+		result.Refs++
+		if refCount[r.entry.Hash]--; refCount[r.entry.Hash] == 0 {
+			total -= int64(r.entry.Size)
+		}
+	}
+
+	err = s.removeUnreferenced(filepath.Join(s.dir, "objects"), func(name string) bool { return refCount[name] > 0 }, &result)
+	if err != nil {
+		return result, err
+	}
+	err = s.removeUnreferenced(filepath.Join(s.dir, "refs"), func(name string) bool { return !strings.HasPrefix(name, ".tmp-") }, &result)
+	return result, err
+}
+
+// loadRefs reads every ref in the store. Unreadable refs are skipped.
+func (s *ContentStore) loadRefs() ([]storedRef, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "refs", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]storedRef, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry StoredEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			slog.Debug("コンテンツストアの参照を読み取れませんでした", "path", path, "error", err)
+			continue
+		}
+		refs = append(refs, storedRef{path: path, entry: entry})
+	}
+	return refs, nil
+}
+
+// removeUnreferenced removes the files under dir that keep does not report as
+// in use and that are older than pruneGracePeriod.
+func (s *ContentStore) removeUnreferenced(dir string, keep func(name string) bool, result *PruneResult) error {
+	cutoff := s.now().Add(-pruneGracePeriod)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || keep(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		result.Blobs++
+		result.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune %s: %w", filepath.Base(dir), err)
+	}
+	return nil
+}
+
+func (s *ContentStore) blobPath(hash string) string {
+	return filepath.Join(s.dir, "objects", hash[:2], hash)
+}
+
+func (s *ContentStore) refPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, "refs", hex.EncodeToString(sum[:])+".json")
+}
+
+// staleMetadata marks content served from the store instead of the live API.
+func staleMetadata(metadata map[string]any, entry *StoredEntry) map[string]any {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["stale"] = true
+	metadata["fetched_at"] = entry.FetchedAt.Format(time.RFC3339)
+	metadata["source"] = "local_content_store"
+	return metadata
+}
+
+// StaleBookDetails returns book details from the store, marked as stale.
+func (s *ContentStore) StaleBookDetails(productID string) (*BookDetailResponse, error) {
+	var details BookDetailResponse
+	entry, err := s.getJSON(detailsStoreKey(productID), &details)
+	if err != nil {
+		return nil, err
+	}
+	details.Metadata = staleMetadata(details.Metadata, entry)
+	return &details, nil
+}
+
+// StaleBookTOC returns a table of contents from the store, marked as stale.
+func (s *ContentStore) StaleBookTOC(productID string) (*TableOfContentsResponse, error) {
+	var toc TableOfContentsResponse
+	entry, err := s.getJSON(tocStoreKey(productID), &toc)
+	if err != nil {
+		return nil, err
+	}
+	toc.Metadata = staleMetadata(toc.Metadata, entry)
+	return &toc, nil
+}
+
+// StaleBookChapterContent parses chapter HTML from the store, resolving the
+// chapter through the stored TOC. The result is marked as stale.
+func (s *ContentStore) StaleBookChapterContent(productID, chapterName string) (*ChapterContentResponse, error) {
+	var toc TableOfContentsResponse
+	if _, err := s.getJSON(tocStoreKey(productID), &toc); err != nil {
+		return nil, err
+	}
+	item, err := resolveTOCItem(&toc, productID, chapterName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, ErrNotStored)
+	}
+
+	htmlBytes, entry, err := s.Get(chapterStoreKey(productID, item.Href))
+	if err != nil {
+		return nil, err
+	}
+	response, err := buildChapterContent(productID, chapterName, item, string(htmlBytes), entry.URL)
+	if err != nil {
+		return nil, err
+	}
+	response.Metadata = staleMetadata(response.Metadata, entry)
+	return response, nil
+}
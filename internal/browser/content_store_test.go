@@ -0,0 +1,256 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fsutil"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
+)
+
+func TestContentStore_PutGet(t *testing.T) {
+	s := NewContentStore(t.TempDir())
+	fetchedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return fetchedAt }
+
+	require.NoError(t, s.Put("book-toc/123", []byte("hello"), "https://example.com/toc", `"v1"`))
+
+	data, entry, err := s.Get("book-toc/123")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, `"v1"`, entry.ETag)
+	assert.Equal(t, "https://example.com/toc", entry.URL)
+	assert.Equal(t, fetchedAt, entry.FetchedAt)
+	assert.Equal(t, 5, entry.Size)
+
+	_, _, err = s.Get("book-toc/999")
+	assert.ErrorIs(t, err, ErrNotStored)
+}
+
+func TestContentStore_ContentAddressed(t *testing.T) {
+	dir := t.TempDir()
+	s := NewContentStore(dir)
+
+	require.NoError(t, s.Put("a", []byte("same"), "", ""))
+	require.NoError(t, s.Put("b", []byte("same"), "", ""))
+
+	a, err := s.Lookup("a")
+	require.NoError(t, err)
+	b, err := s.Lookup("b")
+	require.NoError(t, err)
+	assert.Equal(t, a.Hash, b.Hash)
+
+	blobs, err := filepath.Glob(filepath.Join(dir, "objects", "*", "*"))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 1, "identical content is stored once")
+}
+
+func TestContentStore_PruneByAge(t *testing.T) {
+	s := NewContentStore(t.TempDir())
+	now := time.Now()
+	s.now = func() time.Time { return now.Add(-100 * 24 * time.Hour) }
+	require.NoError(t, s.Put("book-toc/old", []byte("old"), "", ""))
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.Put("book-toc/new", []byte("new"), "", ""))
+
+	s.now = func() time.Time { return now.Add(2 * pruneGracePeriod) }
+	result, err := s.Prune(90*24*time.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Refs: 1, Blobs: 1, Bytes: 3}, result)
+
+	_, _, err = s.Get("book-toc/old")
+	assert.ErrorIs(t, err, ErrNotStored)
+	data, _, err := s.Get("book-toc/new")
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
+
+func TestContentStore_PruneBySize(t *testing.T) {
+	dir := t.TempDir()
+	s := NewContentStore(dir)
+	now := time.Now()
+	for i, e := range []struct{ key, data string }{
+		{"a", "0123456789"},
+		{"b", "abcdefghij"},
+		{"c", "abcdefghij"}, // shares the blob of b
+	} {
+		s.now = func() time.Time { return now.Add(time.Duration(i) * time.Minute) }
+		require.NoError(t, s.Put(e.key, []byte(e.data), "", ""))
+	}
+
+	s.now = func() time.Time { return now.Add(2 * pruneGracePeriod) }
+	result, err := s.Prune(0, 15)
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Refs: 1, Blobs: 1, Bytes: 10}, result, "the oldest entry goes first")
+
+	for _, key := range []string{"b", "c"} {
+		_, _, err := s.Get(key)
+		assert.NoError(t, err, key)
+	}
+	blobs, err := filepath.Glob(filepath.Join(dir, "objects", "*", "*"))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 1)
+}
+
+func TestContentStore_PruneKeepsRecentBlobs(t *testing.T) {
+	dir := t.TempDir()
+	s := NewContentStore(dir)
+	// A blob whose ref a concurrent Put has not written yet
+	require.NoError(t, fsutil.WriteFileAtomic(s.blobPath(strings.Repeat("ab", 32)), []byte("pending"), 0700))
+	leftover := filepath.Join(dir, "refs", ".tmp-123")
+	require.NoError(t, os.MkdirAll(filepath.Dir(leftover), 0700))
+	require.NoError(t, os.WriteFile(leftover, []byte("{"), 0600))
+	old := time.Now().Add(-2 * pruneGracePeriod)
+	require.NoError(t, os.Chtimes(leftover, old, old))
+
+	result, err := s.Prune(time.Hour, 1)
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Blobs: 1, Bytes: 1}, result, "only the leftover temp file is removed")
+	assert.FileExists(t, s.blobPath(strings.Repeat("ab", 32)))
+	assert.NoFileExists(t, leftover)
+}
+
+func TestContentStore_Nil(t *testing.T) {
+	var s *ContentStore
+	assert.NoError(t, s.Put("a", []byte("x"), "", ""))
+	_, err := s.Lookup("a")
+	assert.ErrorIs(t, err, ErrNotStored)
+	_, err = s.StaleBookChapterContent("123", "ch01")
+	assert.ErrorIs(t, err, ErrNotStored)
+	_, err = s.Prune(time.Hour, 1)
+	assert.NoError(t, err)
+}
+
+// etagDoer serves a TOC and a chapter that honours If-None-Match.
+type etagDoer struct {
+	etag         string
+	chapterCalls atomic.Int32
+	notModified  atomic.Int32
+	offline      atomic.Bool
+}
+
+func (d *etagDoer) Do(req *http.Request) (*http.Response, error) {
+	if d.offline.Load() {
+		return nil, errors.New("network is unreachable")
+	}
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+	switch {
+	case strings.Contains(req.URL.Path, "/table-of-contents/"):
+		resp.Header.Set("Content-Type", "application/json")
+		resp.Body = io.NopCloser(strings.NewReader(`[{"title":"Chapter 1","reference_id":"123-/ch01.html"}]`))
+	case strings.Contains(req.URL.Path, "/files/"):
+		d.chapterCalls.Add(1)
+		resp.Header.Set("ETag", d.etag)
+		if d.etag != "" && req.Header.Get("If-None-Match") == d.etag {
+			d.notModified.Add(1)
+			resp.StatusCode = http.StatusNotModified
+			resp.Body = io.NopCloser(strings.NewReader(""))
+			return resp, nil
+		}
+		resp.Body = io.NopCloser(strings.NewReader("<html><body><h1>Intro</h1><p>Body text</p></body></html>"))
+	default:
+		resp.StatusCode = http.StatusNotFound
+		resp.Body = io.NopCloser(strings.NewReader(""))
+	}
+	return resp, nil
+}
+
+func TestGetBookChapterContent_RevalidatesWithETag(t *testing.T) {
+	doer := &etagDoer{etag: `"abc"`}
+	bc := &BrowserClient{
+		httpClient:    doer,
+		cookieManager: NewMockCookieManager(),
+		contentStore:  NewContentStore(t.TempDir()),
+	}
+	ctx := context.Background()
+
+	first, err := bc.GetBookChapterContent(ctx, "123", "ch01")
+	require.NoError(t, err)
+	second, err := bc.GetBookChapterContent(ctx, "123", "ch01")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), doer.chapterCalls.Load())
+	assert.Equal(t, int32(1), doer.notModified.Load(), "second fetch is answered with 304")
+	assert.Equal(t, first.Content.Sections, second.Content.Sections)
+
+	entry, err := bc.contentStore.Lookup(chapterStoreKey("123", "ch01.html"))
+	require.NoError(t, err)
+	assert.Equal(t, `"abc"`, entry.ETag)
+}
+
+func TestContentStore_StaleContentAfterOnlineFetch(t *testing.T) {
+	doer := &etagDoer{}
+	store := NewContentStore(t.TempDir())
+	bc := &BrowserClient{
+		httpClient:    doer,
+		cookieManager: NewMockCookieManager(),
+		contentStore:  store,
+	}
+	ctx := context.Background()
+
+	_, err := bc.GetBookChapterContent(ctx, "123", "ch01")
+	require.NoError(t, err)
+
+	// Network goes away: the live client fails, the store still answers
+	doer.offline.Store(true)
+	_, err = bc.GetBookChapterContent(ctx, "123", "ch01")
+	require.Error(t, err)
+
+	chapter, err := store.StaleBookChapterContent("123", "ch01")
+	require.NoError(t, err)
+	assert.Equal(t, "Intro", chapter.ChapterTitle)
+	assert.Equal(t, true, chapter.Metadata["stale"])
+	assert.NotEmpty(t, chapter.Metadata["fetched_at"])
+
+	toc, err := store.StaleBookTOC("123")
+	require.NoError(t, err)
+	assert.Len(t, toc.TableOfContents, 1)
+	assert.Equal(t, true, toc.Metadata["stale"])
+
+	_, err = store.StaleBookChapterContent("123", "ch99")
+	assert.ErrorIs(t, err, ErrNotStored)
+}
+
+func TestOpenContentStore_Disabled(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "content")
+	assert.Nil(t, OpenContentStore(config.ContentStoreOpts{Enabled: false, Dir: dir}))
+	assert.NotNil(t, OpenContentStore(config.ContentStoreOpts{Enabled: true, Dir: dir}))
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "store directory is created lazily")
+}
+
+func TestGetBookChapterContent_IndexesSections(t *testing.T) {
+	store := NewContentStore(t.TempDir())
+	store.putJSON(detailsStoreKey("123"), &BookDetailResponse{Title: "Stored Title"}, "", "")
+	bc := &BrowserClient{
+		httpClient:    &etagDoer{},
+		cookieManager: NewMockCookieManager(),
+		contentStore:  store,
+		fulltextIndex: fulltext.New(t.TempDir()),
+	}
+
+	// Different names for the same chapter replace one index entry
+	for _, name := range []string{"ch01", "1"} {
+		_, err := bc.GetBookChapterContent(context.Background(), "123", name)
+		require.NoError(t, err)
+	}
+
+	hits, total := bc.fulltextIndex.Search("body", fulltext.SearchOptions{})
+	require.Equal(t, 1, total)
+	assert.Equal(t, "123", hits[0].BookID)
+	assert.Equal(t, "Stored Title", hits[0].BookTitle, "the title comes from stored details without a request")
+	assert.Equal(t, "ch01.html", hits[0].ChapterName)
+	assert.Equal(t, "Intro", hits[0].Heading)
+	assert.Equal(t, "Body text", hits[0].Snippet)
+}
@@ -20,6 +20,20 @@ const (
 	CookieAuthScopes = "CookieAuth.Scopes"
 )
 
+// Defines values for SearchContentV2ParamsSort.
+const (
+	Popularity      SearchContentV2ParamsSort = "popularity"
+	PublicationDate SearchContentV2ParamsSort = "publication_date"
+	Relevance       SearchContentV2ParamsSort = "relevance"
+	Title           SearchContentV2ParamsSort = "title"
+)
+
+// Defines values for SearchContentV2ParamsOrder.
+const (
+	Asc  SearchContentV2ParamsOrder = "asc"
+	Desc SearchContentV2ParamsOrder = "desc"
+)
+
 // AffiliationProduct An O'Reilly product related to the answer
 type AffiliationProduct struct {
 	// Authors Product authors
@@ -260,6 +274,9 @@ type RawSearchResult struct {
 	// Isbn ISBN
 	Isbn *string `json:"isbn,omitempty"`
 
+	// Language Content language code (e.g. en, ja)
+	Language *string `json:"language,omitempty"`
+
 	// LearningUrl Learning platform URL
 	LearningUrl *string `json:"learning_url,omitempty"`
 
@@ -411,8 +428,29 @@ type SearchContentV2Params struct {
 
 	// IsTopics Search only in topics
 	IsTopics *bool `form:"isTopics,omitempty" json:"isTopics,omitempty"`
+
+	// Languages Restrict results to these languages (e.g. en, ja)
+	Languages *[]string `form:"languages,omitempty" json:"languages,omitempty"`
+
+	// Formats Restrict results to these content types (e.g. book, video)
+	Formats *[]string `form:"formats,omitempty" json:"formats,omitempty"`
+
+	// Publishers Restrict results to these publishers
+	Publishers *[]string `form:"publishers,omitempty" json:"publishers,omitempty"`
+
+	// Sort Sort field (omit for relevance)
+	Sort *SearchContentV2ParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Sort direction
+	Order *SearchContentV2ParamsOrder `form:"order,omitempty" json:"order,omitempty"`
 }
 
+// SearchContentV2ParamsSort defines parameters for SearchContentV2.
+type SearchContentV2ParamsSort string
+
+// SearchContentV2ParamsOrder defines parameters for SearchContentV2.
+type SearchContentV2ParamsOrder string
+
 // SubmitQuestionJSONRequestBody defines body for SubmitQuestion for application/json ContentType.
 type SubmitQuestionJSONRequestBody = QuestionRequest
 
@@ -945,6 +983,86 @@ func NewSearchContentV2Request(server string, params *SearchContentV2Params) (*h
 
 		}
 
+		if params.Languages != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "languages", runtime.ParamLocationQuery, *params.Languages); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Formats != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "formats", runtime.ParamLocationQuery, *params.Formats); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Publishers != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "publishers", runtime.ParamLocationQuery, *params.Publishers); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Sort != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "sort", runtime.ParamLocationQuery, *params.Sort); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Order != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "order", runtime.ParamLocationQuery, *params.Order); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
 		queryURL.RawQuery = queryValues.Encode()
 	}
 
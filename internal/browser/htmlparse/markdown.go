@@ -0,0 +1,177 @@
+package htmlparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderMarkdown renders parsed chapter content as Markdown.
+// The chapter title becomes a level-1 heading unless the first section already
+// starts with the same heading.
+func RenderMarkdown(content *ParsedChapterContent) string {
+	var b strings.Builder
+
+	if content.Title != "" && !firstHeadingIs(content, content.Title) {
+		fmt.Fprintf(&b, "# %s\n\n", oneLine(content.Title))
+	}
+
+	for _, section := range content.Sections {
+		if section.Heading.Text != "" {
+			level := min(max(section.Heading.Level, 1), 6)
+			fmt.Fprintf(&b, "%s %s\n\n", strings.Repeat("#", level), oneLine(section.Heading.Text))
+		}
+		for _, elem := range section.Content {
+			writeMarkdownElement(&b, elem)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// firstHeadingIs reports whether the first section heading equals title.
+func firstHeadingIs(content *ParsedChapterContent, title string) bool {
+	if len(content.Sections) == 0 {
+		return false
+	}
+	return oneLine(content.Sections[0].Heading.Text) == oneLine(title)
+}
+
+// writeMarkdownElement writes a single content element followed by a blank line.
+func writeMarkdownElement(b *strings.Builder, elem any) {
+	switch e := elem.(type) {
+	case ParagraphElement:
+		text := strings.TrimSpace(e.Text)
+		if text == "" {
+			return
+		}
+		b.WriteString(text)
+	case CodeBlockElement:
+		fence := codeFence(e.Code)
+		fmt.Fprintf(b, "%s%s\n%s\n%s", fence, e.Language, e.Code, fence)
+		if e.Caption != "" {
+			fmt.Fprintf(b, "\n\n*%s*", oneLine(e.Caption))
+		}
+	case ImageElement:
+		src := e.Src
+		if e.URI != "" {
+			src = e.URI
+		}
+		fmt.Fprintf(b, "![%s](%s)", oneLine(e.Alt), src)
+		if e.Caption != "" {
+			fmt.Fprintf(b, "\n\n*%s*", oneLine(e.Caption))
+		}
+	case ListElement:
+		writeMarkdownList(b, e, "")
+	case TableElement:
+		writeMarkdownTable(b, e)
+	case AdmonitionElement:
+		writeMarkdownAdmonition(b, e)
+	case FootnoteElement:
+		fmt.Fprintf(b, "[^%s]: %s", e.Label, oneLine(e.Text))
+	case LinkElement:
+		fmt.Fprintf(b, "[%s](%s)", oneLine(e.Text), e.Href)
+	default:
+		return
+	}
+	b.WriteString("\n\n")
+}
+
+// writeMarkdownList writes list items, indenting nested lists under their parent item.
+func writeMarkdownList(b *strings.Builder, list ListElement, indent string) {
+	for i, item := range list.Items {
+		if i > 0 || indent != "" {
+			b.WriteString("\n")
+		}
+		marker := "-"
+		if list.Ordered {
+			marker = fmt.Sprintf("%d.", i+1)
+		}
+		fmt.Fprintf(b, "%s%s %s", indent, marker, oneLine(item.Text))
+		for _, child := range item.Children {
+			writeMarkdownList(b, child, indent+strings.Repeat(" ", len(marker)+1))
+		}
+	}
+}
+
+// writeMarkdownTable writes a GitHub-flavored Markdown table.
+func writeMarkdownTable(b *strings.Builder, table TableElement) {
+	if table.Caption != "" {
+		fmt.Fprintf(b, "*%s*\n\n", oneLine(table.Caption))
+	}
+
+	cols := len(table.Headers)
+	for _, row := range table.Rows {
+		cols = max(cols, len(row))
+	}
+	if cols == 0 {
+		return
+	}
+
+	// GFM requires a header row; use empty headers when the table has none
+	writeMarkdownTableRow(b, table.Headers, cols)
+	b.WriteString("\n|")
+	b.WriteString(strings.Repeat(" --- |", cols))
+	for _, row := range table.Rows {
+		b.WriteString("\n")
+		writeMarkdownTableRow(b, row, cols)
+	}
+}
+
+func writeMarkdownTableRow(b *strings.Builder, cells []string, cols int) {
+	b.WriteString("|")
+	for i := range cols {
+		cell := ""
+		if i < len(cells) {
+			cell = strings.ReplaceAll(oneLine(cells[i]), "|", "\\|")
+		}
+		fmt.Fprintf(b, " %s |", cell)
+	}
+}
+
+// writeMarkdownAdmonition writes a callout as a blockquote led by its kind and title.
+func writeMarkdownAdmonition(b *strings.Builder, adm AdmonitionElement) {
+	var inner strings.Builder
+	kind := adm.Kind
+	if kind == "" {
+		kind = "note"
+	}
+	label := strings.ToUpper(kind[:1]) + kind[1:]
+	if adm.Title != "" && !strings.EqualFold(adm.Title, kind) {
+		label += ": " + oneLine(adm.Title)
+	}
+	fmt.Fprintf(&inner, "**%s**\n\n", label)
+	for _, elem := range adm.Content {
+		writeMarkdownElement(&inner, elem)
+	}
+
+	lines := strings.Split(strings.TrimRight(inner.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if line == "" {
+			b.WriteString(">")
+		} else {
+			b.WriteString("> " + line)
+		}
+	}
+}
+
+// codeFence returns a backtick fence longer than any backtick run in code.
+func codeFence(code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// oneLine collapses whitespace so that text fits on a single Markdown line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -11,6 +11,16 @@ import (
 // reCodeLang matches language identifiers in code block class attributes.
 var reCodeLang = regexp.MustCompile(`(?:language-|highlight-)(\w+)`)
 
+// admonitionKinds lists the O'Reilly data-type values rendered as AdmonitionElement.
+var admonitionKinds = map[string]bool{
+	"note":      true,
+	"warning":   true,
+	"tip":       true,
+	"caution":   true,
+	"important": true,
+	"sidebar":   true,
+}
+
 // ParseHTMLContent parses HTML content into structured format.
 func ParseHTMLContent(htmlContent string) (*ParsedChapterContent, error) {
 	doc, err := html.Parse(strings.NewReader(htmlContent))
@@ -28,19 +38,30 @@ func ParseHTMLContent(htmlContent string) (*ParsedChapterContent, error) {
 	return content, nil
 }
 
-// CountWordsFromSections counts words across all paragraph elements in sections.
+// CountWordsFromSections counts words across all paragraph elements in sections,
+// including paragraphs inside admonitions.
 func CountWordsFromSections(sections []ContentSection) int {
 	totalWords := 0
 	for _, section := range sections {
-		for _, item := range section.Content {
-			if p, ok := item.(ParagraphElement); ok {
-				totalWords += len(strings.Fields(p.Text))
-			}
-		}
+		totalWords += countWords(section.Content)
 	}
 	return totalWords
 }
 
+// countWords counts paragraph words in content elements.
+func countWords(items []any) int {
+	words := 0
+	for _, item := range items {
+		switch e := item.(type) {
+		case ParagraphElement:
+			words += len(strings.Fields(e.Text))
+		case AdmonitionElement:
+			words += countWords(e.Content)
+		}
+	}
+	return words
+}
+
 // sectionBuilder tracks the current section while walking the DOM tree.
 type sectionBuilder struct {
 	sections []ContentSection
@@ -106,6 +127,19 @@ func walkDOM(n *html.Node, sb *sectionBuilder) {
 // handleElement processes a single HTML element node.
 // Returns true if the element was handled (no further recursion needed).
 func handleElement(n *html.Node, sb *sectionBuilder) bool {
+	// O'Reilly marks callouts and footnotes with data-type rather than tag names
+	switch dataType := getAttr(n, "data-type"); {
+	case admonitionKinds[dataType]:
+		sb.appendContent(parseAdmonition(n, dataType))
+		return true
+	case dataType == "footnote":
+		fn := parseFootnote(n)
+		if fn.Text != "" {
+			sb.appendContent(fn)
+		}
+		return true
+	}
+
 	switch strings.ToLower(n.Data) {
 	case "h1", "h2", "h3", "h4", "h5", "h6":
 		heading := parseHeading(n)
@@ -137,6 +171,12 @@ func handleElement(n *html.Node, sb *sectionBuilder) bool {
 			sb.appendContent(le)
 		}
 		return true
+	case "table":
+		table := parseTable(n)
+		if len(table.Headers) > 0 || len(table.Rows) > 0 {
+			sb.appendContent(table)
+		}
+		return true
 	case "a":
 		link := parseLinkElement(n)
 		if link.Href != "" && link.Text != "" {
@@ -246,15 +286,15 @@ func parseImage(n *html.Node) ImageElement {
 	}
 }
 
-// parseList parses ul/ol elements into ListElement.
+// parseList parses ul/ol elements into ListElement, keeping nested lists under their item.
 func parseList(n *html.Node) ListElement {
 	ordered := strings.ToLower(n.Data) == "ol"
-	var items []string
+	items := []ListItem{}
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
 		if c.Type == html.ElementNode && strings.ToLower(c.Data) == "li" {
-			text := strings.TrimSpace(extractTextContent(c))
-			if text != "" {
-				items = append(items, text)
+			item := parseListItem(c)
+			if item.Text != "" || len(item.Children) > 0 {
+				items = append(items, item)
 			}
 		}
 	}
@@ -265,6 +305,169 @@ func parseList(n *html.Node) ListElement {
 	}
 }
 
+// parseListItem parses an li element. Text of nested lists is excluded from the
+// item text and returned as Children instead.
+func parseListItem(li *html.Node) ListItem {
+	var item ListItem
+	var text strings.Builder
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				if tag := strings.ToLower(c.Data); tag == "ul" || tag == "ol" {
+					if sub := parseList(c); len(sub.Items) > 0 {
+						item.Children = append(item.Children, sub)
+					}
+					continue
+				}
+			}
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+				continue
+			}
+			if isNoteRef(c) {
+				text.WriteString(extractTextContent(c))
+				continue
+			}
+			walk(c)
+			if c.Type == html.ElementNode && isBlockTag(c.Data) {
+				text.WriteString(" ")
+			}
+		}
+	}
+	walk(li)
+
+	item.Text = normalizeSpace(text.String())
+	return item
+}
+
+// isBlockTag reports whether tag is a block element whose text should be separated by a space.
+func isBlockTag(tag string) bool {
+	switch strings.ToLower(tag) {
+	case "p", "div", "pre", "br":
+		return true
+	}
+	return false
+}
+
+// parseTable parses a table element into TableElement.
+// Header cells come from thead, or from a leading row made only of th cells.
+func parseTable(n *html.Node) TableElement {
+	table := TableElement{Type: "table", Rows: [][]string{}}
+
+	var walk func(*html.Node, bool)
+	walk = func(node *html.Node, inHead bool) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch strings.ToLower(c.Data) {
+			case "caption":
+				table.Caption = normalizeSpace(extractTextContent(c))
+			case "thead":
+				walk(c, true)
+			case "tbody", "tfoot":
+				walk(c, false)
+			case "tr":
+				cells, allTH := parseTableRow(c)
+				if len(cells) == 0 {
+					continue
+				}
+				if table.Headers == nil && (inHead || (allTH && len(table.Rows) == 0)) {
+					table.Headers = cells
+				} else {
+					table.Rows = append(table.Rows, cells)
+				}
+			}
+		}
+	}
+	walk(n, false)
+
+	return table
+}
+
+// parseTableRow returns the cell texts of a tr and whether every cell is a th.
+func parseTableRow(tr *html.Node) ([]string, bool) {
+	var cells []string
+	allTH := true
+	for c := tr.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch strings.ToLower(c.Data) {
+		case "th":
+			cells = append(cells, normalizeSpace(extractTextContent(c)))
+		case "td":
+			allTH = false
+			cells = append(cells, normalizeSpace(extractTextContent(c)))
+		}
+	}
+	return cells, allTH
+}
+
+// parseAdmonition parses a data-type callout into AdmonitionElement.
+// The first heading inside the callout becomes its title.
+func parseAdmonition(n *html.Node, kind string) AdmonitionElement {
+	inner := &sectionBuilder{}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkDOM(c, inner)
+	}
+
+	adm := AdmonitionElement{Type: "admonition", Kind: kind, Content: []any{}}
+	for _, section := range inner.build() {
+		if heading := normalizeSpace(section.Heading.Text); heading != "" {
+			if adm.Title == "" {
+				adm.Title = heading
+			} else {
+				adm.Content = append(adm.Content, ParagraphElement{Type: "paragraph", Text: heading})
+			}
+		}
+		adm.Content = append(adm.Content, section.Content...)
+	}
+	return adm
+}
+
+// parseFootnote parses a data-type="footnote" element into FootnoteElement.
+// The leading back-reference ("<sup><a href="#...-marker">1</a></sup>") becomes the label.
+func parseFootnote(n *html.Node) FootnoteElement {
+	fn := FootnoteElement{Type: "footnote", ID: getAttr(n, "id")}
+
+	var text strings.Builder
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if fn.Label == "" && strings.TrimSpace(text.String()) == "" && c.Type == html.ElementNode &&
+				(strings.ToLower(c.Data) == "a" || strings.ToLower(c.Data) == "sup") {
+				fn.Label = normalizeSpace(extractTextContent(c))
+				continue
+			}
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+
+	fn.Text = normalizeSpace(text.String())
+	if fn.Label == "" {
+		fn.Label = fn.ID
+	}
+	return fn
+}
+
+// isNoteRef reports whether n is an inline footnote reference (<a data-type="noteref">).
+func isNoteRef(n *html.Node) bool {
+	return n.Type == html.ElementNode && strings.ToLower(n.Data) == "a" && getAttr(n, "data-type") == "noteref"
+}
+
+// normalizeSpace collapses runs of whitespace into single spaces.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
 // parseLinkElement parses standalone link elements into LinkElement.
 func parseLinkElement(n *html.Node) LinkElement {
 	href := getAttr(n, "href")
@@ -285,11 +488,19 @@ func parseLinkElement(n *html.Node) LinkElement {
 	}
 }
 
-// extractTextContent extracts all text content from a node and its children
+// extractTextContent extracts all text content from a node and its children.
+// Footnote references are rendered as "[^label]".
 func extractTextContent(n *html.Node) string {
 	if n.Type == html.TextNode {
 		return n.Data
 	}
+	if isNoteRef(n) {
+		var label strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			label.WriteString(extractTextContent(c))
+		}
+		return "[^" + strings.TrimSpace(label.String()) + "]"
+	}
 
 	var text strings.Builder
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
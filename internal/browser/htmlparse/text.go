@@ -0,0 +1,59 @@
+package htmlparse
+
+import "strings"
+
+// SectionText returns the readable text of a section without its heading:
+// paragraphs, list items, table cells, code, captions and callouts, one block per line.
+func SectionText(section ContentSection) string {
+	var b strings.Builder
+	writeText(&b, section.Content)
+	return strings.TrimSpace(b.String())
+}
+
+func writeText(b *strings.Builder, items []any) {
+	line := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			b.WriteString(s)
+			b.WriteByte('\n')
+		}
+	}
+	for _, item := range items {
+		switch e := item.(type) {
+		case ParagraphElement:
+			line(e.Text)
+		case CodeBlockElement:
+			line(e.Code)
+			line(e.Caption)
+		case ImageElement:
+			line(e.Alt)
+			line(e.Caption)
+		case ListElement:
+			writeListText(b, e)
+		case TableElement:
+			line(e.Caption)
+			line(strings.Join(e.Headers, " "))
+			for _, row := range e.Rows {
+				line(strings.Join(row, " "))
+			}
+		case AdmonitionElement:
+			line(e.Title)
+			writeText(b, e.Content)
+		case FootnoteElement:
+			line(e.Text)
+		case LinkElement:
+			line(e.Text)
+		}
+	}
+}
+
+func writeListText(b *strings.Builder, list ListElement) {
+	for _, item := range list.Items {
+		if text := strings.TrimSpace(item.Text); text != "" {
+			b.WriteString(text)
+			b.WriteByte('\n')
+		}
+		for _, child := range item.Children {
+			writeListText(b, child)
+		}
+	}
+}
@@ -39,13 +39,44 @@ type ImageElement struct {
 	Src     string `json:"src"`
 	Alt     string `json:"alt,omitempty"`
 	Caption string `json:"caption,omitempty"`
+	URI     string `json:"uri,omitempty"` // resource URI serving the image bytes, set by the server
 }
 
 // ListElement represents a list in section content
 type ListElement struct {
-	Type    string   `json:"type"` // "list"
-	Ordered bool     `json:"ordered"`
-	Items   []string `json:"items"`
+	Type    string     `json:"type"` // "list"
+	Ordered bool       `json:"ordered"`
+	Items   []ListItem `json:"items"`
+}
+
+// ListItem represents a single list item, optionally containing nested lists
+type ListItem struct {
+	Text     string        `json:"text"`
+	Children []ListElement `json:"children,omitempty"`
+}
+
+// TableElement represents a table in section content
+type TableElement struct {
+	Type    string     `json:"type"` // "table"
+	Caption string     `json:"caption,omitempty"`
+	Headers []string   `json:"headers,omitempty"`
+	Rows    [][]string `json:"rows"`
+}
+
+// AdmonitionElement represents a note/warning/tip/caution/important callout or sidebar
+type AdmonitionElement struct {
+	Type    string `json:"type"` // "admonition"
+	Kind    string `json:"kind"` // "note" / "warning" / "tip" / "caution" / "important" / "sidebar"
+	Title   string `json:"title,omitempty"`
+	Content []any  `json:"content"`
+}
+
+// FootnoteElement represents a footnote body; references in text appear as "[^label]"
+type FootnoteElement struct {
+	Type  string `json:"type"` // "footnote"
+	ID    string `json:"id,omitempty"`
+	Label string `json:"label"`
+	Text  string `json:"text"`
 }
 
 // LinkElement represents a standalone link in section content
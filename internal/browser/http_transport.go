@@ -1,11 +1,20 @@
 package browser
 
 import (
+	"cmp"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
 )
 
 // GzipTransport is a custom transport that automatically handles gzip decompression
@@ -61,3 +70,266 @@ func (grc *gzipReadCloser) Close() error {
 
 	return nil
 }
+
+// RetryTransport retries idempotent requests on transient failures and applies
+// a client-side rate limit to every request.
+// 429 and 5xx gateway errors are retried with jittered exponential backoff,
+// honoring Retry-After when the server sends it. Each attempt has its own
+// timeout, so a slow attempt does not use up the budget of the retries; the
+// caller's deadline bounds them all (see RetryBudget).
+type RetryTransport struct {
+	Transport http.RoundTripper
+
+	maxRetries     int
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+	attemptTimeout time.Duration // 0 means no per-attempt timeout
+	limiter        *tokenBucket  // nil means unlimited
+
+	// jitter returns a value in [0, 1); replaced in tests for deterministic delays.
+	jitter func() float64
+}
+
+// NewRetryTransport wraps transport with retry and rate limiting configured by opts.
+func NewRetryTransport(transport http.RoundTripper, opts config.HTTPOpts) *RetryTransport {
+	rt := &RetryTransport{
+		Transport:      transport,
+		maxRetries:     opts.MaxRetries,
+		baseDelay:      opts.RetryBaseDelay,
+		maxDelay:       opts.RetryMaxDelay,
+		attemptTimeout: APIOperationTimeout,
+		jitter:         rand.Float64,
+	}
+	if opts.RateLimitRPS > 0 {
+		rt.limiter = newTokenBucket(float64(opts.RateLimitRPS), max(opts.RateLimitBurst, 1))
+	}
+	return rt
+}
+
+// RetryBudget returns the time a request may take through a RetryTransport
+// configured by opts: every attempt up to attemptTimeout, and the longest
+// wait before each retry. Callers use it as their deadline, so that retries
+// are not cut short by a deadline meant for a single attempt.
+func RetryBudget(attemptTimeout time.Duration, opts config.HTTPOpts) time.Duration {
+	retries := time.Duration(max(opts.MaxRetries, 0))
+	return attemptTimeout*(retries+1) + opts.RetryMaxDelay*retries
+}
+
+// apiContext bounds one API call, its retries included.
+func (bc *BrowserClient) apiContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, cmp.Or(bc.apiTimeout, APIOperationTimeout))
+}
+
+// RoundTrip implements the http.RoundTripper interface with retry and rate limiting.
+func (rt *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := isIdempotent(req)
+
+	for attempt := 0; ; attempt++ {
+		if rt.limiter != nil {
+			if err := rt.limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := rt.roundTripAttempt(req, attempt)
+		if !retryable || attempt >= rt.maxRetries || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := rt.backoff(attempt)
+		if resp != nil {
+			if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if d > rt.maxDelay {
+					// 待機上限を超える Retry-After はリトライせずそのまま返す
+					return resp, nil
+				}
+				delay = d
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// 待機すると呼び出し元の期限を過ぎるため、最後の応答をそのまま返す
+			return resp, err
+		}
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+
+		slog.Warn("HTTPリクエストをリトライします",
+			"method", req.Method, "url", req.URL.Redacted(), "attempt", attempt+1,
+			"status", statusCode(resp), "error", err, "delay", delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTripAttempt sends one attempt of req under the per-attempt timeout. The
+// timeout also covers reading the response body and ends when it is closed.
+func (rt *RetryTransport) roundTripAttempt(req *http.Request, attempt int) (*http.Response, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if rt.attemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), rt.attemptTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+	attemptReq, err := requestForAttempt(req.WithContext(ctx), attempt)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp, err := rt.Transport.RoundTrip(attemptReq)
+	if err != nil || resp == nil || resp.Body == nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of an attempt when its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// backoff returns the full-jitter exponential backoff delay for the given attempt.
+func (rt *RetryTransport) backoff(attempt int) time.Duration {
+	ceiling := rt.maxDelay
+	if attempt < 32 {
+		if d := rt.baseDelay << attempt; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	return time.Duration(rt.jitter() * float64(ceiling))
+}
+
+// requestForAttempt returns the request to send on the given attempt. A retry
+// of a request with a body is sent as a clone with the body rewound by
+// GetBody, since the previous attempt consumed it.
+func requestForAttempt(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("リトライ用のリクエストボディを再取得できませんでした: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// isIdempotent reports whether the request can be safely retried.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a response or transport error is transient.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given as delay-seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// drainAndClose discards the rest of the body so the connection can be reused.
+func drainAndClose(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	_ = body.Close()
+}
+
+// tokenBucket is a simple token-bucket rate limiter.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // tokens per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:     rate,
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		delay := b.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0,
+// otherwise returns how long to wait until the next token.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.capacity, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
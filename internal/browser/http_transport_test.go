@@ -0,0 +1,331 @@
+package browser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+)
+
+// newTestRetryTransport returns a RetryTransport with short delays and no jitter randomness.
+func newTestRetryTransport(opts config.HTTPOpts) *RetryTransport {
+	if opts.RetryBaseDelay == 0 {
+		opts.RetryBaseDelay = time.Millisecond
+	}
+	if opts.RetryMaxDelay == 0 {
+		opts.RetryMaxDelay = 50 * time.Millisecond
+	}
+	rt := NewRetryTransport(http.DefaultTransport, opts)
+	rt.jitter = func() float64 { return 1 }
+	return rt
+}
+
+func TestRetryTransport_RetriesTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{MaxRetries: 3})}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{MaxRetries: 2})}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load(), "1 attempt + 2 retries")
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestRetryTransport_RewindsBodyOnRetry(t *testing.T) {
+	var bodies []string
+	rt := newTestRetryTransport(config.HTTPOpts{MaxRetries: 3})
+	rt.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		data, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(data))
+		status := http.StatusOK
+		if len(bodies) < 2 {
+			status = http.StatusServiceUnavailable
+		}
+		return &http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/search", strings.NewReader(`{"query":"go"}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{`{"query":"go"}`, `{"query":"go"}`}, bodies, "the retry resends the whole body")
+}
+
+func TestRetryTransport_DoesNotRetryNonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{MaxRetries: 3})}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryTransport_DoesNotRetryClientError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{MaxRetries: 3})}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryTransport_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var first time.Time
+	var gap time.Duration
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		gap = time.Since(first)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{MaxRetries: 1, RetryMaxDelay: 5 * time.Second})}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, gap, 900*time.Millisecond, "should wait for Retry-After")
+}
+
+func TestRetryTransport_RetryAfterBeyondMaxDelay(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{MaxRetries: 3})}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load(), "should not wait longer than RetryMaxDelay")
+}
+
+func TestRetryTransport_StopsOnContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	rt := newTestRetryTransport(config.HTTPOpts{MaxRetries: 5, RetryBaseDelay: time.Second, RetryMaxDelay: time.Second})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = (&http.Client{Transport: rt}).Do(req)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryTransport_ReturnsLastResponseBeforeDeadline(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	rt := newTestRetryTransport(config.HTTPOpts{MaxRetries: 5, RetryBaseDelay: time.Second, RetryMaxDelay: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	require.NoError(t, err, "a wait past the deadline is not started")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryTransport_RetriesTimedOutAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done() // hangs until the attempt times out
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	rt := newTestRetryTransport(config.HTTPOpts{MaxRetries: 1})
+	rt.attemptTimeout = 100 * time.Millisecond
+	resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryTransport_SlowAttemptAndRetryAfterWithinBudget(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(800 * time.Millisecond)
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	// Scaled down from the defaults: a 1s attempt timeout and up to 2s between attempts
+	opts := config.HTTPOpts{MaxRetries: 1, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 2 * time.Second}
+	rt := NewRetryTransport(http.DefaultTransport, opts)
+	rt.attemptTimeout = time.Second
+	bc := &BrowserClient{httpClient: &http.Client{Transport: rt}, apiTimeout: RetryBudget(rt.attemptTimeout, opts)}
+
+	ctx, cancel := bc.apiContext(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := bc.httpClient.Do(req)
+	require.NoError(t, err, "1.8s of attempt and Retry-After exceed one attempt's timeout but not the budget")
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryBudget(t *testing.T) {
+	opts := config.HTTPOpts{MaxRetries: 3, RetryMaxDelay: 30 * time.Second}
+	assert.Equal(t, 4*30*time.Second+3*30*time.Second, RetryBudget(30*time.Second, opts))
+	assert.Equal(t, 30*time.Second, RetryBudget(30*time.Second, config.HTTPOpts{}), "no retries")
+}
+
+func TestRetryTransport_RateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// 20 req/s with burst 1: 4 requests need at least 3 refills (~150ms)
+	client := &http.Client{Transport: newTestRetryTransport(config.HTTPOpts{RateLimitRPS: 20, RateLimitBurst: 1})}
+	start := time.Now()
+	for range 4 {
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	rt := newTestRetryTransport(config.HTTPOpts{RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second})
+
+	assert.Equal(t, 100*time.Millisecond, rt.backoff(0))
+	assert.Equal(t, 400*time.Millisecond, rt.backoff(2))
+	assert.Equal(t, time.Second, rt.backoff(10), "capped at RetryMaxDelay")
+	assert.Equal(t, time.Second, rt.backoff(100), "no overflow")
+
+	rt.jitter = func() float64 { return 0.5 }
+	assert.Equal(t, 200*time.Millisecond, rt.backoff(2))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("5", now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	for _, v := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(v, now)
+		assert.False(t, ok, v)
+	}
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	b := newTokenBucket(10, 2)
+	now := b.last
+
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now), "empty bucket waits one refill")
+	assert.Equal(t, time.Duration(0), b.reserve(now.Add(100*time.Millisecond)))
+}
@@ -107,13 +107,14 @@ func normalizeSearchResult(raw api.RawSearchResult, index int) map[string]any {
 		"ourn":           firstString(raw.Ourn),
 		"publisher":      publisher,
 		"published_date": firstString(raw.PublishedDate, raw.PublicationDate, raw.DatePublished, raw.PubDate),
+		"language":       firstString(raw.Language),
 		"source":         "api_search_oreilly",
 	}
 }
 
 // makeHTTPSearchRequest performs the O'Reilly search API call using generated OpenAPI client.
 // Returns the API response and total count of matching results.
-func (bc *BrowserClient) makeHTTPSearchRequest(query string, rows, offset, tzOffset int, aiaOnly bool, featureFlags string, report, isTopics bool) (*api.SearchAPIResponse, int, error) {
+func (bc *BrowserClient) makeHTTPSearchRequest(ctx context.Context, query string, opts searchOptions) (*api.SearchAPIResponse, int, error) {
 	// Create OpenAPI client
 	client := &api.ClientWithResponses{
 		ClientInterface: &api.Client{
@@ -126,19 +127,30 @@ func (bc *BrowserClient) makeHTTPSearchRequest(query string, rows, offset, tzOff
 	// Create search parameters
 	params := &api.SearchContentV2Params{
 		Query:        query,
-		Rows:         &rows,
-		Offset:       &offset,
-		TzOffset:     &tzOffset,
-		AiaOnly:      &aiaOnly,
-		FeatureFlags: &featureFlags,
-		Report:       &report,
-		IsTopics:     &isTopics,
+		Rows:         &opts.rows,
+		Offset:       &opts.offset,
+		TzOffset:     &opts.tzOffset,
+		AiaOnly:      &opts.aiaOnly,
+		FeatureFlags: &opts.featureFlags,
+		Report:       &opts.report,
+		IsTopics:     &opts.isTopics,
 	}
+	if len(opts.languages) > 0 {
+		params.Languages = &opts.languages
+	}
+	if len(opts.formats) > 0 {
+		params.Formats = &opts.formats
+	}
+	if len(opts.publishers) > 0 {
+		params.Publishers = &opts.publishers
+	}
+	params.Sort, params.Order = apiSortParams(opts.sort)
 
 	// OpenAPI検索リクエスト (タイムアウト付き)
-	apiCtx, apiCancel := context.WithTimeout(context.Background(), APIOperationTimeout)
+	apiCtx, apiCancel := bc.apiContext(ctx)
 	defer apiCancel()
-	slog.Debug("OpenAPI検索リクエスト開始", "query", query, "rows", rows, "offset", offset)
+	slog.Debug("OpenAPI検索リクエスト開始", "query", query, "rows", opts.rows, "offset", opts.offset,
+		"languages", opts.languages, "formats", opts.formats, "publishers", opts.publishers, "sort", opts.sort)
 
 	// Make the API call
 	resp, err := client.SearchContentV2WithResponse(apiCtx, params)
@@ -175,6 +187,10 @@ type searchOptions struct {
 	featureFlags string
 	report       bool
 	isTopics     bool
+	languages    []string
+	formats      []string
+	publishers   []string
+	sort         string
 }
 
 // parseSearchOptions extracts search parameters from the options map, applying defaults.
@@ -208,19 +224,31 @@ func parseSearchOptions(options map[string]any) searchOptions {
 	if topics, ok := options["isTopics"].(bool); ok {
 		opts.isTopics = topics
 	}
+	if langs, ok := options["languages"].([]string); ok {
+		opts.languages = langs
+	}
+	if formats, ok := options["content_types"].([]string); ok {
+		opts.formats = formats
+	}
+	if publishers, ok := options["publishers"].([]string); ok {
+		opts.publishers = publishers
+	}
+	if order, ok := options["sort"].(string); ok && IsValidSortOrder(order) {
+		opts.sort = order
+	}
 
 	return opts
 }
 
 // SearchContent は O'Reilly Learning Platform の内部 API を使用して検索を実行します。
 // Returns normalized results and total count of matching results.
-func (bc *BrowserClient) SearchContent(query string, options map[string]any) ([]map[string]any, int, error) {
+func (bc *BrowserClient) SearchContent(ctx context.Context, query string, options map[string]any) ([]map[string]any, int, error) {
 	slog.Info("API検索を開始します", "query", query)
 
 	opts := parseSearchOptions(options)
 
 	// Use OpenAPI generated client for search
-	apiResponse, totalCount, err := bc.makeHTTPSearchRequest(query, opts.rows, opts.offset, opts.tzOffset, opts.aiaOnly, opts.featureFlags, opts.report, opts.isTopics)
+	apiResponse, totalCount, err := bc.makeHTTPSearchRequest(ctx, query, opts)
 	if err != nil {
 		slog.Error("API検索に失敗しました", "error", err, "query", query)
 		return nil, 0, fmt.Errorf("API search failed: %w", err)
@@ -250,6 +278,9 @@ func (bc *BrowserClient) SearchContent(query string, options map[string]any) ([]
 		results = append(results, normalized)
 	}
 
+	// API がソート指定を無視した場合に備え、ページ内で決定的に並べ替える (ページをまたいだ並べ替えはしない)
+	SortSearchResults(results, opts.sort)
+
 	slog.Info("API検索が完了しました", "query", query, "result_count", len(results), "total_count", totalCount)
 	return results, totalCount, nil
 }
@@ -0,0 +1,158 @@
+package browser
+
+import (
+	"strings"
+	"time"
+)
+
+// SearchFilter holds filters applied to normalized search results on the client side.
+// The search API honors languages, formats and publishers itself, but not authors
+// or publication dates, so those are only enforced here. The API-side filters are
+// re-checked as well because results that lack the field are returned regardless.
+type SearchFilter struct {
+	Languages       []string
+	ContentTypes    []string
+	Publishers      []string
+	Authors         []string
+	PublishedAfter  time.Time // inclusive; zero means unbounded
+	PublishedBefore time.Time // inclusive; zero means unbounded
+}
+
+// IsZero reports whether the filter has no conditions.
+func (f SearchFilter) IsZero() bool {
+	return len(f.Languages) == 0 && len(f.ContentTypes) == 0 && len(f.Publishers) == 0 &&
+		len(f.Authors) == 0 && f.PublishedAfter.IsZero() && f.PublishedBefore.IsZero()
+}
+
+// Match reports whether a normalized search result satisfies every condition.
+// Language and content type are skipped when the result does not carry them,
+// while a date range excludes results without a parseable published_date.
+func (f SearchFilter) Match(result map[string]any) bool {
+	if len(f.Languages) > 0 {
+		if lang, _ := result["language"].(string); lang != "" && !matchLanguage(lang, f.Languages) {
+			return false
+		}
+	}
+	if len(f.ContentTypes) > 0 {
+		if ct, _ := result["content_type"].(string); ct != "" && ct != ContentTypeUnknown && !containsFold(f.ContentTypes, ct) {
+			return false
+		}
+	}
+	if len(f.Publishers) > 0 {
+		publisher, _ := result["publisher"].(string)
+		if !matchAnySubstring(f.Publishers, publisher) {
+			return false
+		}
+	}
+	if len(f.Authors) > 0 && !matchAuthors(f.Authors, result["authors"]) {
+		return false
+	}
+	if !f.PublishedAfter.IsZero() || !f.PublishedBefore.IsZero() {
+		raw, _ := result["published_date"].(string)
+		published, ok := ParsePublishedDate(raw)
+		if !ok {
+			return false
+		}
+		if !f.PublishedAfter.IsZero() && published.Before(f.PublishedAfter) {
+			return false
+		}
+		if !f.PublishedBefore.IsZero() && published.After(f.PublishedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterSearchResults returns the results that satisfy the filter.
+func FilterSearchResults(results []map[string]any, f SearchFilter) []map[string]any {
+	if f.IsZero() {
+		return results
+	}
+	filtered := make([]map[string]any, 0, len(results))
+	for _, result := range results {
+		if f.Match(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// publishedDateLayouts are the date formats seen in search results and accepted as filter bounds.
+var publishedDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// ParsePublishedDate parses a publication date such as "2023-05-16", "2023-05" or "2023".
+func ParsePublishedDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range publishedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// matchLanguage compares primary language subtags, so "en" matches "en-US".
+func matchLanguage(lang string, wanted []string) bool {
+	primary := func(s string) string {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if i := strings.IndexAny(s, "-_"); i >= 0 {
+			s = s[:i]
+		}
+		return s
+	}
+	p := primary(lang)
+	for _, w := range wanted {
+		if primary(w) == p {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnySubstring reports whether s contains any of the needles, ignoring case.
+func matchAnySubstring(needles []string, s string) bool {
+	s = strings.ToLower(s)
+	for _, n := range needles {
+		if n != "" && strings.Contains(s, strings.ToLower(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAuthors reports whether any author name contains one of the wanted names.
+func matchAuthors(wanted []string, authors any) bool {
+	switch v := authors.(type) {
+	case []Author:
+		for _, a := range v {
+			if matchAnySubstring(wanted, a.Name) {
+				return true
+			}
+		}
+	case []string:
+		for _, name := range v {
+			if matchAnySubstring(wanted, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
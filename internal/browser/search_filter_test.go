@@ -0,0 +1,97 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePublishedDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		ok   bool
+	}{
+		{"2023-05-16", time.Date(2023, 5, 16, 0, 0, 0, 0, time.UTC), true},
+		{"2023-05-16T10:00:00Z", time.Date(2023, 5, 16, 10, 0, 0, 0, time.UTC), true},
+		{"2023-05", time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{" 2023 ", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"", time.Time{}, false},
+		{"May 2023", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePublishedDate(tt.in)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.True(t, tt.want.Equal(got), "%q: got %v, want %v", tt.in, got, tt.want)
+	}
+}
+
+func TestSearchFilter_Match(t *testing.T) {
+	result := map[string]any{
+		"content_type":   ContentTypeBook,
+		"publisher":      "O'Reilly Media, Inc.",
+		"authors":        []Author{{Name: "Jon Bodner"}},
+		"published_date": "2024-01-09",
+		"language":       "en-US",
+	}
+
+	tests := []struct {
+		name   string
+		filter SearchFilter
+		want   bool
+	}{
+		{"empty filter", SearchFilter{}, true},
+		{"language primary subtag", SearchFilter{Languages: []string{"en"}}, true},
+		{"language mismatch", SearchFilter{Languages: []string{"ja"}}, false},
+		{"content type", SearchFilter{ContentTypes: []string{"BOOK"}}, true},
+		{"content type mismatch", SearchFilter{ContentTypes: []string{"video"}}, false},
+		{"publisher substring", SearchFilter{Publishers: []string{"o'reilly"}}, true},
+		{"publisher mismatch", SearchFilter{Publishers: []string{"Packt"}}, false},
+		{"author substring", SearchFilter{Authors: []string{"bodner"}}, true},
+		{"author mismatch", SearchFilter{Authors: []string{"Kernighan"}}, false},
+		{"within range", SearchFilter{PublishedAfter: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), PublishedBefore: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)}, true},
+		{"before range", SearchFilter{PublishedAfter: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"after range", SearchFilter{PublishedBefore: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(result))
+		})
+	}
+}
+
+func TestSearchFilter_Match_MissingFields(t *testing.T) {
+	// 言語・種別が不明な結果は API 側フィルタを信頼して残すが、日付範囲指定時は除外する
+	result := map[string]any{"content_type": ContentTypeUnknown}
+
+	assert.True(t, SearchFilter{Languages: []string{"en"}, ContentTypes: []string{"book"}}.Match(result))
+	assert.False(t, SearchFilter{PublishedAfter: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}.Match(result))
+	assert.False(t, SearchFilter{Publishers: []string{"O'Reilly"}}.Match(result))
+}
+
+func TestFilterSearchResults(t *testing.T) {
+	results := []map[string]any{
+		{"id": "1", "published_date": "2020-01-01"},
+		{"id": "2", "published_date": "2025-03-01"},
+	}
+
+	assert.Len(t, FilterSearchResults(results, SearchFilter{}), 2)
+
+	filtered := FilterSearchResults(results, SearchFilter{PublishedAfter: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "2", filtered[0]["id"])
+}
+
+func TestParseSearchOptions_Filters(t *testing.T) {
+	opts := parseSearchOptions(map[string]any{
+		"languages":     []string{"en"},
+		"content_types": []string{"book"},
+		"publishers":    []string{"O'Reilly Media, Inc."},
+	})
+
+	assert.Equal(t, []string{"en"}, opts.languages)
+	assert.Equal(t, []string{"book"}, opts.formats)
+	assert.Equal(t, []string{"O'Reilly Media, Inc."}, opts.publishers)
+}
@@ -0,0 +1,109 @@
+package browser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/generated/api"
+)
+
+// Sort order values accepted by SearchContent via the "sort" option.
+const (
+	SortRelevance  = "relevance"
+	SortNewest     = "newest"
+	SortOldest     = "oldest"
+	SortPopularity = "popularity"
+	SortTitle      = "title"
+)
+
+// SortOrders lists the supported sort order values.
+var SortOrders = []string{SortRelevance, SortNewest, SortOldest, SortPopularity, SortTitle}
+
+// IsValidSortOrder reports whether s is a supported sort order. The empty string means relevance.
+func IsValidSortOrder(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, o := range SortOrders {
+		if s == o {
+			return true
+		}
+	}
+	return false
+}
+
+// apiSortParams maps a sort order to the search API's sort and order parameters.
+// Relevance is the API default, so no parameters are sent for it.
+func apiSortParams(order string) (*api.SearchContentV2ParamsSort, *api.SearchContentV2ParamsOrder) {
+	ptr := func(s api.SearchContentV2ParamsSort, o api.SearchContentV2ParamsOrder) (*api.SearchContentV2ParamsSort, *api.SearchContentV2ParamsOrder) {
+		return &s, &o
+	}
+	switch order {
+	case SortNewest:
+		return ptr(api.PublicationDate, api.Desc)
+	case SortOldest:
+		return ptr(api.PublicationDate, api.Asc)
+	case SortPopularity:
+		return ptr(api.Popularity, api.Desc)
+	case SortTitle:
+		return ptr(api.Title, api.Asc)
+	default:
+		return nil, nil
+	}
+}
+
+// SortSearchResults sorts normalized search results in place.
+// newest/oldest sort on published_date (undated results last) and title sorts
+// case-insensitively, with title and id as tie-breakers so the order is deterministic.
+// relevance and popularity keep the API order because results carry no score to sort on.
+// Only the given page is reordered: when the API ignores the sort, a later
+// page may hold results that would rank before this one.
+func SortSearchResults(results []map[string]any, order string) {
+	var desc bool
+	switch order {
+	case SortNewest:
+		desc = true
+	case SortOldest, SortTitle:
+	default:
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if order != SortTitle {
+			if c := compareDates(a, b, desc); c != 0 {
+				return c < 0
+			}
+		}
+		if c := strings.Compare(sortKey(a, "title"), sortKey(b, "title")); c != 0 {
+			return c < 0
+		}
+		return sortKey(a, "id") < sortKey(b, "id")
+	})
+}
+
+// compareDates orders results by published_date. Results without a parseable
+// date always sort after dated ones, regardless of direction.
+func compareDates(a, b map[string]any, desc bool) int {
+	rawA, _ := a["published_date"].(string)
+	rawB, _ := b["published_date"].(string)
+	da, okA := ParsePublishedDate(rawA)
+	db, okB := ParsePublishedDate(rawB)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return 1
+	case !okB:
+		return -1
+	case desc:
+		return db.Compare(da)
+	default:
+		return da.Compare(db)
+	}
+}
+
+func sortKey(result map[string]any, key string) string {
+	s, _ := result[key].(string)
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,119 @@
+package browser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/generated/api"
+)
+
+func sortTestResults() []map[string]any {
+	return []map[string]any{
+		{"id": "1", "title": "beta", "published_date": "2021-05-01"},
+		{"id": "2", "title": "Alpha", "published_date": "2024-01-15"},
+		{"id": "3", "title": "gamma"},
+		{"id": "4", "title": "Alpha", "published_date": "2021-05-01"},
+	}
+}
+
+func ids(results []map[string]any) []string {
+	out := make([]string, 0, len(results))
+	for _, r := range results {
+		out = append(out, r["id"].(string))
+	}
+	return out
+}
+
+func TestSortSearchResults(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{SortNewest, []string{"2", "4", "1", "3"}},
+		{SortOldest, []string{"4", "1", "2", "3"}},
+		{SortTitle, []string{"2", "4", "1", "3"}},
+		{SortRelevance, []string{"1", "2", "3", "4"}},
+		{SortPopularity, []string{"1", "2", "3", "4"}},
+		{"", []string{"1", "2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			results := sortTestResults()
+			SortSearchResults(results, tt.order)
+			assert.Equal(t, tt.want, ids(results))
+		})
+	}
+}
+
+func TestIsValidSortOrder(t *testing.T) {
+	for _, o := range SortOrders {
+		assert.True(t, IsValidSortOrder(o), o)
+	}
+	assert.True(t, IsValidSortOrder(""))
+	assert.False(t, IsValidSortOrder("rating"))
+}
+
+func TestAPISortParams(t *testing.T) {
+	s, o := apiSortParams(SortNewest)
+	assert.Equal(t, api.PublicationDate, *s)
+	assert.Equal(t, api.Desc, *o)
+
+	s, o = apiSortParams(SortTitle)
+	assert.Equal(t, api.Title, *s)
+	assert.Equal(t, api.Asc, *o)
+
+	s, o = apiSortParams(SortRelevance)
+	assert.Nil(t, s)
+	assert.Nil(t, o)
+}
+
+func TestParseSearchOptions_Sort(t *testing.T) {
+	assert.Equal(t, SortOldest, parseSearchOptions(map[string]any{"sort": SortOldest}).sort)
+	assert.Equal(t, "", parseSearchOptions(map[string]any{"sort": "bogus"}).sort)
+}
+
+// unsortedSearchDoer serves two fixed search pages chosen by offset and
+// ignores the sort parameters, like an API that does not support them.
+type unsortedSearchDoer struct{}
+
+func (unsortedSearchDoer) Do(req *http.Request) (*http.Response, error) {
+	body := `{"data":{"products":[
+		{"product_id":"1","title":"A","published_date":"2020-01-01"},
+		{"product_id":"2","title":"B","published_date":"2021-01-01"}]}}`
+	if req.URL.Query().Get("offset") != "0" {
+		body = `{"data":{"products":[
+			{"product_id":"3","title":"C","published_date":"2024-01-01"},
+			{"product_id":"4","title":"D","published_date":"2019-01-01"}]}}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestSearchContent_SortIsPageLocal(t *testing.T) {
+	bc := newCachingTestClient(unsortedSearchDoer{})
+	ctx := context.Background()
+
+	page := func(offset int, order string) []string {
+		results, _, err := bc.SearchContent(ctx, "go", map[string]any{"rows": 2, "offset": offset, "sort": order})
+		require.NoError(t, err)
+		return ids(results)
+	}
+
+	// Each page is sorted on its own: page 2 holds the newest result overall
+	// but is never merged with page 1
+	assert.Equal(t, []string{"2", "1"}, page(0, SortNewest))
+	assert.Equal(t, []string{"3", "4"}, page(2, SortNewest))
+
+	// popularity cannot be applied client-side, so the API order is kept
+	assert.Equal(t, []string{"1", "2"}, page(0, SortPopularity))
+	assert.Equal(t, []string{"3", "4"}, page(2, SortPopularity))
+}
@@ -1,11 +1,13 @@
 package browser
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/cookie"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
 )
 
 // Operation timeouts
@@ -30,15 +32,22 @@ type HTTPDoer interface {
 
 // Client は server.go が BrowserClient に期待するメソッドを定義するインターフェース。
 // テスト時に mock に差し替えることで、全 O'Reilly ハンドラーの単体テストを可能にする。
+// O'Reilly API を呼び出すメソッドは呼び出し元の context を受け取り、
+// キャンセル・デッドライン・シャットダウンを HTTP リクエストとポーリングに伝播する。
 type Client interface {
-	SearchContent(query string, options map[string]any) ([]map[string]any, int, error)
-	AskQuestion(question string, maxWaitTime time.Duration) (*AnswerResponse, error)
-	GetBookDetails(productID string) (*BookDetailResponse, error)
-	GetBookTOC(productID string) (*TableOfContentsResponse, error)
-	GetBookChapterContent(productID, chapterName string) (*ChapterContentResponse, error)
-	GetQuestionByID(questionID string) (*AnswerResponse, error)
+	SearchContent(ctx context.Context, query string, options map[string]any) ([]map[string]any, int, error)
+	AskQuestion(ctx context.Context, question string, opts QuestionOptions, maxWaitTime time.Duration, onProgress AnswerProgressFunc) (*AnswerResponse, error)
+	GetBookDetails(ctx context.Context, productID string) (*BookDetailResponse, error)
+	GetBookCredits(ctx context.Context, productID, title string) (*BookCredits, error)
+	GetBookTOC(ctx context.Context, productID string) (*TableOfContentsResponse, error)
+	GetBookChapterContent(ctx context.Context, productID, chapterName string) (*ChapterContentResponse, error)
+	GetChapterHTMLContent(ctx context.Context, productID, chapterName string) (string, string, error)
+	GetBookImage(ctx context.Context, productID, imagePath string) (*BookImage, error)
+	SubmitQuestion(ctx context.Context, question string, opts QuestionOptions) (*QuestionResponse, error)
+	WaitForAnswer(ctx context.Context, questionID string, maxWaitTime time.Duration, onProgress AnswerProgressFunc) (*AnswerResponse, error)
+	GetQuestionByID(ctx context.Context, questionID string) (*AnswerResponse, error)
 	Reauthenticate() error
-	CheckAndResetAuth() error
+	CheckAndResetAuth(ctx context.Context) error
 	Close()
 }
 
@@ -51,7 +60,19 @@ type BrowserClient struct {
 	userAgent     string
 	cookieManager cookie.Manager
 	debug         bool
-	stateDir      string // XDG StateHome (Chrome一時データ用)
+	stateDir      string        // XDG StateHome (Chrome一時データ用)
+	apiTimeout    time.Duration // API 呼び出し 1 回の期限 (リトライを含む。0 で APIOperationTimeout)
+
+	// 書籍詳細・目次のプロセス内キャッシュ (product ID をキーとする。nil で無効)
+	detailsCache *lruCache[*BookDetailResponse]
+	tocCache     *lruCache[*TableOfContentsResponse]
+	creditsCache *lruCache[*BookCredits] // 著者・出版社 (引用時のみ検索 API で取得)
+
+	// 取得済みコンテンツのディスク保存先 (nil で無効)
+	contentStore *ContentStore
+
+	// 取得したチャプターのセクションを登録する全文検索インデックス (nil で無効)
+	fulltextIndex *fulltext.Index
 }
 
 // TableOfContentsItem represents a single item in the table of contents
@@ -99,8 +120,19 @@ type BookDetailResponse struct {
 	VirtualPages    int               `json:"virtual_pages"`
 	PageCount       int               `json:"page_count"`
 	Language        string            `json:"language"`
+	Edition         int               `json:"edition,omitempty"`   // from a title like "Learning Go, 2nd Edition"; 0 when not stated
+	Authors         []string          `json:"authors,omitempty"`   // from BookCredits; set by the book-details resource, not by GetBookDetails
+	Publisher       string            `json:"publisher,omitempty"` // from BookCredits, as Authors
 	Resources       []BookResource    `json:"resources,omitempty"`
 	Tags            []string          `json:"tags,omitempty"`
+	Metadata        map[string]any    `json:"metadata,omitempty"`
+}
+
+// BookCredits are the authors and publisher of a book, looked up in the search
+// API because the v2 epubs API does not return them.
+type BookCredits struct {
+	Authors   []string `json:"authors,omitempty"`
+	Publisher string   `json:"publisher,omitempty"`
 }
 
 // ChapterContentResponse represents structured chapter content with parsed HTML
@@ -124,6 +156,17 @@ type QuestionRequest struct {
 	PipelineConfig        PipelineConfig `json:"_pipeline_config"`
 }
 
+// QuestionOptions scopes a question to part of the catalog and tunes the
+// excerpts returned with the answer. The zero value asks across all books,
+// videos and articles with the default excerpt lengths.
+type QuestionOptions struct {
+	ProductIDs      []string // product IDs of books, or OURNs for other content
+	ContentTypes    []string // subset of AnswerContentTypes
+	Publishers      []string // exact publisher names
+	SnippetLength   int      // 0 means DefaultSnippetLength
+	HighlightLength int      // 0 means DefaultHighlightLength
+}
+
 // PipelineConfig represents configuration for the answer generation pipeline
 type PipelineConfig struct {
 	SnippetLength   int `json:"snippet_length"`
@@ -137,6 +180,10 @@ type QuestionResponse struct {
 	Message    string `json:"message"`
 }
 
+// AnswerProgressFunc is called on each poll while an answer is still being generated.
+// partial holds the unfinished answer fetched with includeUnfinished=true.
+type AnswerProgressFunc func(partial *AnswerResponse, elapsed time.Duration)
+
 // AnswerResponse represents the response containing the answer to a submitted question
 type AnswerResponse struct {
 	QuestionID   string       `json:"question_id"`
@@ -18,6 +18,7 @@ type SaveParams struct {
 	Results      []map[string]any
 	HistoryID    string
 	TotalResults int
+	Sort         string // requested sort order; empty for relevance
 }
 
 // markdownHeader groups the header fields for markdown generation.
@@ -27,6 +28,7 @@ type markdownHeader struct {
 	totalResults int
 	resultCount  int
 	historyID    string
+	sort         string
 }
 
 // resultView holds extracted fields from a search result map.
@@ -81,6 +83,7 @@ func SaveResponseAsMarkdown(p SaveParams) (string, error) {
 		totalResults: EffectiveTotalResults(p.TotalResults, len(p.Results)),
 		resultCount:  len(p.Results),
 		historyID:    p.HistoryID,
+		sort:         p.Sort,
 	}
 
 	var b strings.Builder
@@ -92,6 +95,9 @@ func SaveResponseAsMarkdown(p SaveParams) (string, error) {
 	fmt.Fprintf(&b, "- Total Results: %d\n", hdr.totalResults)
 	fmt.Fprintf(&b, "- Results in this file: %d\n", hdr.resultCount)
 	fmt.Fprintf(&b, "- History ID: %s\n", hdr.historyID)
+	if hdr.sort != "" {
+		fmt.Fprintf(&b, "- Sort: %s (ordered within this page only)\n", hdr.sort)
+	}
 	b.WriteString("\n---\n")
 
 	// Results
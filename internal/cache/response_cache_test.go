@@ -261,3 +261,32 @@ func TestSaveResponseAsMarkdown_EmptyResults(t *testing.T) {
 		t.Error("missing results count for empty results")
 	}
 }
+
+func TestSaveResponseAsMarkdown_Sort(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "responses")
+	results := []map[string]any{{"title": "Book", "product_id": "1"}}
+
+	filePath, err := SaveResponseAsMarkdown(SaveParams{Dir: cacheDir, Query: "sorted", Results: results, HistoryID: "req_sort", Sort: "newest"})
+	if err != nil {
+		t.Fatalf("SaveResponseAsMarkdown failed: %v", err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if !strings.Contains(string(data), "- Sort: newest (ordered within this page only)") {
+		t.Errorf("missing sort order, got:\n%s", data)
+	}
+
+	filePath, err = SaveResponseAsMarkdown(SaveParams{Dir: t.TempDir(), Query: "relevance", Results: results, HistoryID: "req_rel"})
+	if err != nil {
+		t.Fatalf("SaveResponseAsMarkdown failed: %v", err)
+	}
+	data, err = os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if strings.Contains(string(data), "- Sort:") {
+		t.Errorf("relevance should not add a sort line, got:\n%s", data)
+	}
+}
@@ -0,0 +1,124 @@
+package citation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// bibtexEscaper escapes the characters LaTeX treats specially.
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// titleStopWords are skipped when picking the title word of a citation key.
+var titleStopWords = map[string]bool{"a": true, "an": true, "the": true, "on": true, "of": true}
+
+// renderBibTeX formats works as BibTeX entries. Keys are made unique by
+// appending "a", "b", ... to repeated ones.
+func renderBibTeX(works []Work) string {
+	seen := map[string]int{}
+	entries := make([]string, len(works))
+	for i, w := range works {
+		key := bibtexKey(w)
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key += string(rune('a' + n - 2))
+		}
+		entries[i] = formatBibTeX(w, key)
+	}
+	return strings.Join(entries, "\n\n")
+}
+
+// formatBibTeX formats one work as a @book entry, or @misc for anything else.
+func formatBibTeX(w Work, key string) string {
+	entryType := "misc"
+	if w.Type == TypeBook {
+		entryType = "book"
+	}
+	var fields [][2]string
+	add := func(field, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{field, value})
+		}
+	}
+
+	var authors []string
+	for _, a := range w.Authors {
+		n := splitName(a)
+		switch {
+		case n.family == "":
+		case n.given == "":
+			authors = append(authors, "{"+bibtexEscaper.Replace(n.family)+"}") // braced so BibTeX does not split it
+		default:
+			authors = append(authors, bibtexEscaper.Replace(n.family+", "+n.given))
+		}
+	}
+	add("author", strings.Join(authors, " and "))
+	add("title", "{"+bibtexEscaper.Replace(w.title())+"}") // double braces keep the capitalization
+	if w.Edition > 0 {
+		add("edition", ordinal(w.Edition))
+	}
+	add("publisher", bibtexEscaper.Replace(w.publisher()))
+	add("year", w.year())
+	if parts := w.dateParts(); len(parts) > 1 {
+		add("month", fmt.Sprint(parts[1]))
+	}
+	add("isbn", w.ISBN)
+	if w.Type == TypeVideo {
+		add("howpublished", "Video")
+	}
+	add("url", w.URL)
+	add("language", w.Language)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s{%s,\n", entryType, key)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "  %-9s = {%s},\n", f[0], f[1])
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// bibtexKey builds an author-year-title key such as "bodner2024learning".
+// Works without authors use their product ID in place of the name.
+func bibtexKey(w Work) string {
+	var key string
+	if len(w.Authors) > 0 {
+		key = keyWord(splitName(w.Authors[0]).family)
+	}
+	if key == "" {
+		key = keyWord(w.ID)
+	}
+	key += w.year()
+	for _, word := range strings.Fields(w.title()) {
+		if word = keyWord(word); word != "" && !titleStopWords[word] {
+			key += word
+			break
+		}
+	}
+	if key == "" {
+		return "oreilly"
+	}
+	return key
+}
+
+// keyWord lowercases s and drops everything but ASCII letters and digits.
+func keyWord(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
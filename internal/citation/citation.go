@@ -0,0 +1,217 @@
+// Package citation renders bibliographic citations of O'Reilly content in
+// BibTeX, APA, Chicago and CSL-JSON.
+package citation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Citation styles.
+const (
+	StyleBibTeX  = "bibtex"
+	StyleAPA     = "apa"
+	StyleChicago = "chicago"
+	StyleCSLJSON = "csl-json"
+)
+
+// Styles lists the supported styles.
+var Styles = []string{StyleBibTeX, StyleAPA, StyleChicago, StyleCSLJSON}
+
+// Work types.
+const (
+	TypeBook    = "book"
+	TypeVideo   = "video"
+	TypeWebpage = "webpage" // anything else with a URL, e.g. an unresolved Answers source
+)
+
+// Publisher is used when a work's publisher is unknown; everything on the platform is published or distributed by O'Reilly.
+const Publisher = "O'Reilly Media"
+
+// Work is the bibliographic data of one cited work.
+type Work struct {
+	ID        string   // product ID; used as the CSL-JSON id
+	Type      string   // TypeBook, TypeVideo or TypeWebpage
+	Title     string   // may end with an edition statement such as ", 2nd Edition"
+	Authors   []string // personal names, "First Last" or "Last, First"
+	Publisher string
+	Edition   int    // 0 when not stated
+	Date      string // publication date, "2024-01-09", "2024-01" or "2024"
+	ISBN      string
+	URL       string
+	Language  string
+}
+
+// ParseStyle returns the style named by s, ignoring case. An empty s is APA.
+func ParseStyle(s string) (string, error) {
+	style := strings.ToLower(strings.TrimSpace(s))
+	switch style {
+	case "":
+		return StyleAPA, nil
+	case "bib":
+		return StyleBibTeX, nil
+	case "csl", "csljson", "csl_json":
+		return StyleCSLJSON, nil
+	}
+	if !slices.Contains(Styles, style) {
+		return "", fmt.Errorf("unknown citation style %q; use one of: %s", s, strings.Join(Styles, ", "))
+	}
+	return style, nil
+}
+
+// MIMEType returns the media type of citations rendered in style.
+func MIMEType(style string) string {
+	switch style {
+	case StyleBibTeX:
+		return "application/x-bibtex"
+	case StyleCSLJSON:
+		return "application/vnd.citationstyles.csl+json"
+	}
+	return "text/markdown" // APA and Chicago italicize titles with Markdown emphasis
+}
+
+// Render formats works in style. APA and Chicago entries are sorted
+// alphabetically as their reference lists require; BibTeX and CSL-JSON keep the given order.
+func Render(style string, works []Work) (string, error) {
+	switch style {
+	case StyleBibTeX:
+		return renderBibTeX(works), nil
+	case StyleAPA:
+		return renderList(works, formatAPA), nil
+	case StyleChicago:
+		return renderList(works, formatChicago), nil
+	case StyleCSLJSON:
+		return renderCSLJSON(works)
+	}
+	return "", fmt.Errorf("unknown citation style %q", style)
+}
+
+// renderList formats each work and sorts the entries, one per paragraph.
+func renderList(works []Work, format func(Work) string) string {
+	entries := make([]string, len(works))
+	for i, w := range works {
+		entries[i] = format(w)
+	}
+	slices.SortStableFunc(entries, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return strings.Join(entries, "\n\n")
+}
+
+// name is a personal name split for inverted display.
+type name struct {
+	family, given string
+}
+
+// splitName splits "First Middle Last" or "Last, First". A single word is a family name.
+func splitName(full string) name {
+	full = strings.Join(strings.Fields(full), " ")
+	if family, given, ok := strings.Cut(full, ","); ok {
+		return name{family: strings.TrimSpace(family), given: strings.TrimSpace(given)}
+	}
+	i := strings.LastIndex(full, " ")
+	if i < 0 {
+		return name{family: full}
+	}
+	return name{family: full[i+1:], given: full[:i]}
+}
+
+// initials abbreviates given names, e.g. "Jon Michael" → "J. M." and "Jean-Luc" → "J.-L.".
+func initials(given string) string {
+	var words []string
+	for _, word := range strings.Fields(given) {
+		var parts []string
+		for part := range strings.SplitSeq(word, "-") {
+			if r := []rune(part); len(r) > 0 {
+				parts = append(parts, string(r[0])+".")
+			}
+		}
+		words = append(words, strings.Join(parts, "-"))
+	}
+	return strings.Join(words, " ")
+}
+
+// editionSuffix matches an edition statement at the end of a title, e.g. ", 2nd Edition".
+var editionSuffix = regexp.MustCompile(`(?i)[,:]?\s*\(?\d+(?:st|nd|rd|th)\s+edition\)?\s*$`)
+
+// title returns the title without the edition statement rendered separately.
+func (w Work) title() string {
+	t := strings.TrimSpace(w.Title)
+	if w.Edition > 0 {
+		t = strings.TrimSpace(editionSuffix.ReplaceAllString(t, ""))
+	}
+	if t == "" {
+		return "Untitled"
+	}
+	return t
+}
+
+// publisher returns the publisher, defaulting to Publisher.
+func (w Work) publisher() string {
+	if w.Publisher != "" {
+		return w.Publisher
+	}
+	return Publisher
+}
+
+// dateParts returns the year, month and day of the publication date that are known.
+func (w Work) dateParts() []int {
+	date, _, _ := strings.Cut(strings.TrimSpace(w.Date), "T")
+	var parts []int
+	for i, s := range strings.SplitN(date, "-", 3) {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || i == 0 && len(s) != 4 {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// year returns the publication year, or "" when unknown.
+func (w Work) year() string {
+	if parts := w.dateParts(); len(parts) > 0 {
+		return strconv.Itoa(parts[0])
+	}
+	return ""
+}
+
+// ordinal returns "1st", "2nd", "3rd", "4th", ... "11th", "21st".
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// joinNames joins names as "A", "A and B" or "A, B, and C" with conj as the conjunction.
+func joinNames(names []string, conj string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " " + conj + " " + names[1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", " + conj + " " + names[len(names)-1]
+}
+
+// withPeriod ends s with a period unless it already ends with punctuation.
+func withPeriod(s string) string {
+	if strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
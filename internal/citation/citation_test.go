@@ -0,0 +1,148 @@
+package citation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var learningGo = Work{
+	ID:        "9781492077206",
+	Type:      TypeBook,
+	Title:     "Learning Go, 2nd Edition",
+	Authors:   []string{"Jon Bodner"},
+	Publisher: "O'Reilly Media, Inc.",
+	Edition:   2,
+	Date:      "2024-01-09",
+	ISBN:      "9781492077206",
+	URL:       "https://learning.oreilly.com/library/view/-/9781492077206/",
+}
+
+var ddia = Work{
+	ID:      "9781491903063",
+	Type:    TypeBook,
+	Title:   "Designing Data-Intensive Applications",
+	Authors: []string{"Martin Kleppmann", "Chris Riccomini"},
+	Date:    "2017",
+}
+
+func TestParseStyle(t *testing.T) {
+	tests := map[string]string{
+		"":         StyleAPA,
+		"APA":      StyleAPA,
+		" bibtex ": StyleBibTeX,
+		"bib":      StyleBibTeX,
+		"Chicago":  StyleChicago,
+		"csl-json": StyleCSLJSON,
+		"csl":      StyleCSLJSON,
+	}
+	for in, want := range tests {
+		got, err := ParseStyle(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseStyle("mla")
+	assert.ErrorContains(t, err, "bibtex, apa, chicago, csl-json")
+}
+
+func TestRender_APA(t *testing.T) {
+	got, err := Render(StyleAPA, []Work{learningGo, ddia})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Bodner, J. (2024). *Learning Go* (2nd ed.). O'Reilly Media, Inc. https://learning.oreilly.com/library/view/-/9781492077206/\n\n"+
+			"Kleppmann, M., & Riccomini, C. (2017). *Designing Data-Intensive Applications*. O'Reilly Media.",
+		got)
+}
+
+func TestRender_APAWithoutAuthorsOrDate(t *testing.T) {
+	got, err := Render(StyleAPA, []Work{{Type: TypeVideo, Title: "Kubernetes in 3 Hours"}})
+	require.NoError(t, err)
+	assert.Equal(t, "*Kubernetes in 3 Hours* [Video]. (n.d.). O'Reilly Media.", got)
+}
+
+func TestRender_Chicago(t *testing.T) {
+	got, err := Render(StyleChicago, []Work{ddia, learningGo})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Bodner, Jon. *Learning Go*. 2nd ed. O'Reilly Media, Inc., 2024. https://learning.oreilly.com/library/view/-/9781492077206/.\n\n"+
+			"Kleppmann, Martin, and Chris Riccomini. *Designing Data-Intensive Applications*. O'Reilly Media, 2017.",
+		got)
+}
+
+func TestRender_BibTeX(t *testing.T) {
+	got, err := Render(StyleBibTeX, []Work{learningGo, learningGo})
+	require.NoError(t, err)
+	assert.Equal(t, `@book{bodner2024learning,
+  author    = {Bodner, Jon},
+  title     = {{Learning Go}},
+  edition   = {2nd},
+  publisher = {O'Reilly Media, Inc.},
+  year      = {2024},
+  month     = {1},
+  isbn      = {9781492077206},
+  url       = {https://learning.oreilly.com/library/view/-/9781492077206/},
+}
+
+@book{bodner2024learninga,
+  author    = {Bodner, Jon},
+  title     = {{Learning Go}},
+  edition   = {2nd},
+  publisher = {O'Reilly Media, Inc.},
+  year      = {2024},
+  month     = {1},
+  isbn      = {9781492077206},
+  url       = {https://learning.oreilly.com/library/view/-/9781492077206/},
+}`, got)
+}
+
+func TestRender_BibTeXEscapes(t *testing.T) {
+	got, err := Render(StyleBibTeX, []Work{{ID: "123", Type: TypeWebpage, Title: "C# & .NET_Core 100%", Authors: []string{"Plato"}}})
+	require.NoError(t, err)
+	assert.Contains(t, got, "@misc{platoc,")
+	assert.Contains(t, got, `title     = {{C\# \& .NET\_Core 100\%}},`)
+	assert.Contains(t, got, "author    = {{Plato}},", "single names are braced")
+}
+
+func TestRender_CSLJSON(t *testing.T) {
+	got, err := Render(StyleCSLJSON, []Work{learningGo, {Type: TypeVideo, Title: "Intro", Authors: []string{"Cher"}, Date: "2023-05"}})
+	require.NoError(t, err)
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(got), &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, "9781492077206", items[0]["id"])
+	assert.Equal(t, "book", items[0]["type"])
+	assert.Equal(t, "Learning Go", items[0]["title"])
+	assert.Equal(t, "2", items[0]["edition"])
+	assert.Equal(t, []any{map[string]any{"family": "Bodner", "given": "Jon"}}, items[0]["author"])
+	assert.Equal(t, map[string]any{"date-parts": []any{[]any{2024.0, 1.0, 9.0}}}, items[0]["issued"])
+
+	assert.Equal(t, "item2", items[1]["id"])
+	assert.Equal(t, "motion_picture", items[1]["type"])
+	assert.Equal(t, []any{map[string]any{"literal": "Cher"}}, items[1]["author"])
+	assert.Equal(t, map[string]any{"date-parts": []any{[]any{2023.0, 5.0}}}, items[1]["issued"])
+	assert.Equal(t, Publisher, items[1]["publisher"])
+}
+
+func TestSplitName(t *testing.T) {
+	assert.Equal(t, name{family: "Bodner", given: "Jon"}, splitName("Jon Bodner"))
+	assert.Equal(t, name{family: "Bodner", given: "Jon"}, splitName("Bodner, Jon"))
+	assert.Equal(t, name{family: "Sousa", given: "Jean-Luc de"}, splitName("Jean-Luc de  Sousa"))
+	assert.Equal(t, name{family: "Cher"}, splitName("Cher"))
+	assert.Equal(t, "J.-L. M.", initials("Jean-Luc Michel"))
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd"} {
+		assert.Equal(t, want, ordinal(n))
+	}
+}
+
+func TestWork_DateParts(t *testing.T) {
+	assert.Equal(t, []int{2024, 1, 9}, Work{Date: "2024-01-09T00:00:00Z"}.dateParts())
+	assert.Equal(t, []int{2024}, Work{Date: "2024"}.dateParts())
+	assert.Empty(t, Work{Date: "January 2024"}.dateParts())
+	assert.Empty(t, Work{}.dateParts())
+}
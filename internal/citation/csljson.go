@@ -0,0 +1,84 @@
+package citation
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// cslItem is a CSL-JSON item as read by Zotero, Pandoc and citeproc.
+type cslItem struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Author    []cslName `json:"author,omitempty"`
+	Publisher string    `json:"publisher"`
+	Edition   string    `json:"edition,omitempty"`
+	Issued    *cslDate  `json:"issued,omitempty"`
+	ISBN      string    `json:"ISBN,omitempty"`
+	URL       string    `json:"URL,omitempty"`
+	Language  string    `json:"language,omitempty"`
+	Medium    string    `json:"medium,omitempty"`
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"` // single-word names and organizations
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// cslTypes maps work types to CSL item types.
+var cslTypes = map[string]string{
+	TypeBook:  "book",
+	TypeVideo: "motion_picture",
+}
+
+// renderCSLJSON formats works as a CSL-JSON array.
+func renderCSLJSON(works []Work) (string, error) {
+	items := make([]cslItem, len(works))
+	for i, w := range works {
+		item := cslItem{
+			ID:        w.ID,
+			Type:      cslTypes[w.Type],
+			Title:     w.title(),
+			Publisher: w.publisher(),
+			ISBN:      w.ISBN,
+			URL:       w.URL,
+			Language:  w.Language,
+		}
+		if item.ID == "" {
+			item.ID = "item" + strconv.Itoa(i+1)
+		}
+		if item.Type == "" {
+			item.Type = "webpage"
+		}
+		if w.Type == TypeVideo {
+			item.Medium = "Video"
+		}
+		if w.Edition > 0 {
+			item.Edition = strconv.Itoa(w.Edition)
+		}
+		if parts := w.dateParts(); len(parts) > 0 {
+			item.Issued = &cslDate{DateParts: [][]int{parts}}
+		}
+		for _, a := range w.Authors {
+			n := splitName(a)
+			switch {
+			case n.family == "":
+			case n.given == "":
+				item.Author = append(item.Author, cslName{Literal: n.family})
+			default:
+				item.Author = append(item.Author, cslName{Family: n.family, Given: n.given})
+			}
+		}
+		items[i] = item
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,109 @@
+package citation
+
+import "strings"
+
+// apaMaxAuthors is the number of authors APA lists before eliding with "...".
+const apaMaxAuthors = 20
+
+// formatAPA formats a reference list entry in APA 7th edition style:
+//
+//	Bodner, J. (2024). *Learning Go* (2nd ed.). O'Reilly Media. https://...
+func formatAPA(w Work) string {
+	year := w.year()
+	if year == "" {
+		year = "n.d."
+	}
+	title := "*" + w.title() + "*"
+	switch {
+	case w.Edition > 1:
+		title += " (" + ordinal(w.Edition) + " ed.)"
+	case w.Type == TypeVideo:
+		title += " [Video]"
+	}
+
+	var parts []string
+	if authors := apaAuthors(w.Authors); authors != "" {
+		parts = append(parts, withPeriod(authors), "("+year+").", withPeriod(title))
+	} else {
+		parts = append(parts, withPeriod(title), "("+year+").") // the title moves to the author position
+	}
+	parts = append(parts, withPeriod(w.publisher()))
+	if w.URL != "" {
+		parts = append(parts, w.URL)
+	}
+	return strings.Join(parts, " ")
+}
+
+// apaAuthors formats authors as "Bodner, J., Kleppmann, M., & Ford, N.".
+func apaAuthors(authors []string) string {
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		n := splitName(a)
+		if n.family == "" {
+			continue
+		}
+		if n.given != "" {
+			names = append(names, n.family+", "+initials(n.given))
+		} else {
+			names = append(names, n.family)
+		}
+	}
+	switch {
+	case len(names) == 0:
+		return ""
+	case len(names) == 1:
+		return names[0]
+	case len(names) > apaMaxAuthors:
+		return strings.Join(names[:apaMaxAuthors-1], ", ") + ", . . . " + names[len(names)-1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1]
+}
+
+// formatChicago formats a bibliography entry in Chicago notes-bibliography style:
+//
+//	Bodner, Jon. *Learning Go*. 2nd ed. O'Reilly Media, 2024. https://...
+func formatChicago(w Work) string {
+	var parts []string
+	if authors := chicagoAuthors(w.Authors); authors != "" {
+		parts = append(parts, withPeriod(authors))
+	}
+	parts = append(parts, withPeriod("*"+w.title()+"*"))
+	if w.Edition > 1 {
+		parts = append(parts, ordinal(w.Edition)+" ed.")
+	}
+	if w.Type == TypeVideo {
+		parts = append(parts, "Video.")
+	}
+	year := w.year()
+	if year == "" {
+		year = "n.d."
+	}
+	parts = append(parts, w.publisher()+", "+year+".")
+	if w.URL != "" {
+		parts = append(parts, withPeriod(w.URL))
+	}
+	return strings.Join(parts, " ")
+}
+
+// chicagoAuthors formats authors as "Bodner, Jon, Martin Kleppmann, and Neal Ford":
+// only the first name is inverted.
+func chicagoAuthors(authors []string) string {
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		n := splitName(a)
+		switch {
+		case n.family == "":
+			continue
+		case n.given == "":
+			names = append(names, n.family)
+		case len(names) == 0:
+			names = append(names, n.family+", "+n.given)
+		default:
+			names = append(names, n.given+" "+n.family)
+		}
+	}
+	if len(names) == 2 {
+		return names[0] + ", and " + names[1]
+	}
+	return joinNames(names, "and")
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 )
@@ -41,6 +42,7 @@ type logRotation struct {
 // HistoryOpts は調査履歴設定を保持する
 type HistoryOpts struct {
 	MaxEntries int
+	MaxThreads int // O'Reilly Answers の会話スレッドの保持数
 }
 
 // SamplingOpts はサンプリング設定を保持する
@@ -49,14 +51,68 @@ type SamplingOpts struct {
 	MaxTokens int
 }
 
+// ElicitationOpts は MCP elicitation (ユーザーへの問い合わせ) 設定を保持する
+type ElicitationOpts struct {
+	Enabled          bool          // 無効時は曖昧なチャプター名や長時間の処理をユーザーに確認しない
+	ConfirmLongWaits time.Duration // 同期質問で明示された max_wait_time_seconds がこれを超える場合に実行方法を確認する (0 で確認しない)
+}
+
+// HTTPOpts は O'Reilly API への HTTP リクエストのリトライ・レート制限設定を保持する
+type HTTPOpts struct {
+	MaxRetries     int           // 冪等リクエストの最大リトライ回数 (0 でリトライしない)
+	RetryBaseDelay time.Duration // 指数バックオフの初期待機時間
+	RetryMaxDelay  time.Duration // バックオフおよび Retry-After の待機上限
+	RateLimitRPS   int           // 1 秒あたりのリクエスト数上限 (0 で無制限)
+	RateLimitBurst int           // トークンバケットの容量
+}
+
+// BookCacheOpts は書籍詳細・目次のプロセス内キャッシュ設定を保持する
+type BookCacheOpts struct {
+	TTL        time.Duration // エントリの有効期間 (0 でキャッシュ無効)
+	MaxEntries int           // 保持する書籍数の上限 (LRU で追い出す)
+}
+
+// ContentStoreOpts は取得済みチャプター・目次・書籍詳細のディスク保存設定を保持する
+type ContentStoreOpts struct {
+	Enabled  bool          // 無効時は保存もオフライン配信も行わない
+	Dir      string        // 保存先ディレクトリ (CacheHome 配下)
+	MaxAge   time.Duration // 起動時にこれより前に取得した内容を削除する (0 で無期限)
+	MaxBytes int64         // 保存する内容の合計サイズ上限 (起動時に古いものから削除、0 で無制限)
+}
+
+// ImageOpts はチャプター画像をリソースに埋め込む際のサイズ上限を保持する
+type ImageOpts struct {
+	InlineMaxBytes      int // 埋め込む画像 1 枚あたりの上限 (超えるものは URI のみ返す)
+	InlineMaxTotalBytes int // 1 回の読み取りで埋め込む画像の合計上限
+}
+
+// FullTextOpts は取得済みチャプターの全文検索インデックス設定を保持する
+type FullTextOpts struct {
+	Enabled bool   // 無効時はチャプターを索引せず、ローカル検索も結果を返さない
+	Dir     string // インデックスの保存先ディレクトリ (CacheHome 配下)
+}
+
+// ExportOpts は書籍エクスポート設定を保持する
+type ExportOpts struct {
+	Dir         string // 出力先を指定しない場合のベースディレクトリ (StateHome 配下、書籍ごとにサブディレクトリを作る)
+	Concurrency int    // 同時に取得するチャプター数
+}
+
 // Config はアプリケーションの設定を保持します
 type Config struct {
-	Server   ServerOpts
-	Debug    debugOpts
-	XDGDirs  *XDGDirs
-	Log      LogOpts
-	History  HistoryOpts
-	Sampling SamplingOpts
+	Server       ServerOpts
+	Debug        debugOpts
+	XDGDirs      *XDGDirs
+	Log          LogOpts
+	History      HistoryOpts
+	Sampling     SamplingOpts
+	Elicitation  ElicitationOpts
+	HTTP         HTTPOpts
+	BookCache    BookCacheOpts
+	ContentStore ContentStoreOpts
+	Image        ImageOpts
+	FullText     FullTextOpts
+	Export       ExportOpts
 }
 
 // envString returns the environment variable value, or defaultVal if unset.
@@ -141,11 +197,45 @@ func LoadConfig() (*Config, error) {
 		},
 		History: HistoryOpts{
 			MaxEntries: envInt("ORM_MCP_GO_HISTORY_MAX_ENTRIES", 1000, 1),
+			MaxThreads: envInt("ORM_MCP_GO_HISTORY_MAX_THREADS", 200, 1),
 		},
 		Sampling: SamplingOpts{
 			Enabled:   envBool("ORM_MCP_GO_ENABLE_SAMPLING", true),
 			MaxTokens: envInt("ORM_MCP_GO_SAMPLING_MAX_TOKENS", 500, 1),
 		},
+		Elicitation: ElicitationOpts{
+			Enabled:          envBool("ORM_MCP_GO_ENABLE_ELICITATION", true),
+			ConfirmLongWaits: time.Duration(envInt("ORM_MCP_GO_ELICITATION_LONG_WAIT_SEC", 120, 0)) * time.Second,
+		},
+		HTTP: HTTPOpts{
+			MaxRetries:     envInt("ORM_MCP_GO_HTTP_MAX_RETRIES", 3, 0),
+			RetryBaseDelay: time.Duration(envInt("ORM_MCP_GO_HTTP_RETRY_BASE_DELAY_MS", 500, 1)) * time.Millisecond,
+			RetryMaxDelay:  time.Duration(envInt("ORM_MCP_GO_HTTP_RETRY_MAX_DELAY_MS", 30000, 1)) * time.Millisecond,
+			RateLimitRPS:   envInt("ORM_MCP_GO_HTTP_RATE_LIMIT_RPS", 5, 0),
+			RateLimitBurst: envInt("ORM_MCP_GO_HTTP_RATE_LIMIT_BURST", 10, 1),
+		},
+		BookCache: BookCacheOpts{
+			TTL:        time.Duration(envInt("ORM_MCP_GO_BOOK_CACHE_TTL_SEC", 3600, 0)) * time.Second,
+			MaxEntries: envInt("ORM_MCP_GO_BOOK_CACHE_MAX_ENTRIES", 256, 1),
+		},
+		ContentStore: ContentStoreOpts{
+			Enabled:  envBool("ORM_MCP_GO_CONTENT_STORE", true),
+			Dir:      xdgDirs.ContentStorePath(),
+			MaxAge:   time.Duration(envInt("ORM_MCP_GO_CONTENT_STORE_MAX_AGE_DAYS", 90, 0)) * 24 * time.Hour,
+			MaxBytes: int64(envInt("ORM_MCP_GO_CONTENT_STORE_MAX_MB", 1024, 0)) << 20,
+		},
+		Image: ImageOpts{
+			InlineMaxBytes:      envInt("ORM_MCP_GO_IMAGE_INLINE_MAX_BYTES", 1<<20, 0),
+			InlineMaxTotalBytes: envInt("ORM_MCP_GO_IMAGE_INLINE_MAX_TOTAL_BYTES", 5<<20, 0),
+		},
+		FullText: FullTextOpts{
+			Enabled: envBool("ORM_MCP_GO_FULLTEXT_INDEX", true),
+			Dir:     xdgDirs.FullTextIndexPath(),
+		},
+		Export: ExportOpts{
+			Dir:         xdgDirs.ExportPath(),
+			Concurrency: envInt("ORM_MCP_GO_EXPORT_CONCURRENCY", 4, 1),
+		},
 	}
 
 	setupLogger(config)
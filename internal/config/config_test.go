@@ -1,7 +1,9 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_BindAddress_Default(t *testing.T) {
@@ -33,3 +35,113 @@ func TestLoadConfig_BindAddress_EnvOverride(t *testing.T) {
 		t.Errorf("BindAddress = %q, want %q", cfg.Server.BindAddress, "0.0.0.0")
 	}
 }
+
+func TestLoadConfig_HTTP_Defaults(t *testing.T) {
+	t.Setenv("ORM_MCP_GO_DEBUG_DIR", t.TempDir())
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.HTTP.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d, want 3", cfg.HTTP.MaxRetries)
+	}
+	if cfg.HTTP.RetryBaseDelay != 500*time.Millisecond {
+		t.Errorf("RetryBaseDelay = %v, want 500ms", cfg.HTTP.RetryBaseDelay)
+	}
+	if cfg.HTTP.RateLimitRPS != 5 || cfg.HTTP.RateLimitBurst != 10 {
+		t.Errorf("RateLimit = %d/%d, want 5/10", cfg.HTTP.RateLimitRPS, cfg.HTTP.RateLimitBurst)
+	}
+}
+
+func TestLoadConfig_HTTP_EnvOverride(t *testing.T) {
+	t.Setenv("ORM_MCP_GO_DEBUG_DIR", t.TempDir())
+	t.Setenv("ORM_MCP_GO_HTTP_MAX_RETRIES", "0")
+	t.Setenv("ORM_MCP_GO_HTTP_RETRY_MAX_DELAY_MS", "2000")
+	t.Setenv("ORM_MCP_GO_HTTP_RATE_LIMIT_RPS", "0")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.HTTP.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %d, want 0", cfg.HTTP.MaxRetries)
+	}
+	if cfg.HTTP.RetryMaxDelay != 2*time.Second {
+		t.Errorf("RetryMaxDelay = %v, want 2s", cfg.HTTP.RetryMaxDelay)
+	}
+	if cfg.HTTP.RateLimitRPS != 0 {
+		t.Errorf("RateLimitRPS = %d, want 0", cfg.HTTP.RateLimitRPS)
+	}
+}
+
+func TestLoadConfig_BookCache(t *testing.T) {
+	t.Setenv("ORM_MCP_GO_DEBUG_DIR", t.TempDir())
+	t.Setenv("ORM_MCP_GO_BOOK_CACHE_TTL_SEC", "60")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.BookCache.TTL != time.Minute {
+		t.Errorf("TTL = %v, want 1m", cfg.BookCache.TTL)
+	}
+	if cfg.BookCache.MaxEntries != 256 {
+		t.Errorf("MaxEntries = %d, want 256", cfg.BookCache.MaxEntries)
+	}
+}
+
+func TestLoadConfig_ContentStore(t *testing.T) {
+	t.Setenv("ORM_MCP_GO_DEBUG_DIR", t.TempDir())
+	t.Setenv("ORM_MCP_GO_CONTENT_STORE_MAX_AGE_DAYS", "0")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.ContentStore.MaxAge != 0 {
+		t.Errorf("MaxAge = %v, want 0", cfg.ContentStore.MaxAge)
+	}
+	if cfg.ContentStore.MaxBytes != 1<<30 {
+		t.Errorf("MaxBytes = %d, want 1GiB", cfg.ContentStore.MaxBytes)
+	}
+}
+
+func TestLoadConfig_Elicitation(t *testing.T) {
+	t.Setenv("ORM_MCP_GO_DEBUG_DIR", t.TempDir())
+	t.Setenv("ORM_MCP_GO_ELICITATION_LONG_WAIT_SEC", "0")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if !cfg.Elicitation.Enabled {
+		t.Errorf("Elicitation.Enabled = false, want true by default")
+	}
+	if cfg.Elicitation.ConfirmLongWaits != 0 {
+		t.Errorf("ConfirmLongWaits = %v, want 0", cfg.Elicitation.ConfirmLongWaits)
+	}
+}
+
+func TestLoadConfig_Export(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ORM_MCP_GO_DEBUG_DIR", dir)
+	t.Setenv("ORM_MCP_GO_EXPORT_CONCURRENCY", "0")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.Export.Concurrency != 4 {
+		t.Errorf("Export.Concurrency = %d, want 4 (0 is invalid)", cfg.Export.Concurrency)
+	}
+	if want := filepath.Join(dir, "exports"); cfg.Export.Dir != want {
+		t.Errorf("Export.Dir = %q, want %q", cfg.Export.Dir, want)
+	}
+}
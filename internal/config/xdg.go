@@ -119,7 +119,31 @@ func (x *XDGDirs) ResponseCachePath() string {
 	return filepath.Join(x.CacheHome, "responses")
 }
 
+// ContentStorePath は取得済み書籍コンテンツの保存ディレクトリのパスを返す
+// CacheHomeに保存（再取得可能なデータのため）
+func (x *XDGDirs) ContentStorePath() string {
+	return filepath.Join(x.CacheHome, "content")
+}
+
+// FullTextIndexPath は全文検索インデックスの保存ディレクトリのパスを返す
+// CacheHomeに保存（取得済みチャプターから再生成可能なデータのため）
+func (x *XDGDirs) FullTextIndexPath() string {
+	return filepath.Join(x.CacheHome, "fulltext")
+}
+
+// ExportPath は書籍エクスポートの既定の出力先ディレクトリのパスを返す
+// StateHomeに保存（ユーザーが読むために残すデータのため）
+func (x *XDGDirs) ExportPath() string {
+	return filepath.Join(x.StateHome, "exports")
+}
+
 // ResearchHistoryPath は調査履歴ファイルのパスを返す
 func (x *XDGDirs) ResearchHistoryPath() string {
 	return filepath.Join(x.StateHome, "research-history.json")
 }
+
+// AnswerThreadsPath は O'Reilly Answers の会話スレッドファイルのパスを返す
+// 調査履歴と同じ StateHome に保存
+func (x *XDGDirs) AnswerThreadsPath() string {
+	return filepath.Join(x.StateHome, "answer-threads.json")
+}
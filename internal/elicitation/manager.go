@@ -0,0 +1,109 @@
+package elicitation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+)
+
+// MCP elicitation actions
+const (
+	ActionAccept  = "accept"
+	ActionDecline = "decline"
+	ActionCancel  = "cancel"
+)
+
+// choiceField is the form field that carries the selected option.
+const choiceField = "choice"
+
+// Option is a single choice offered to the user.
+type Option struct {
+	Value string // returned by Choose when selected
+	Title string // shown to the user
+}
+
+// Manager asks the user through MCP elicitation instead of guessing.
+// A nil *Manager never elicits.
+type Manager struct {
+	config *config.Config
+}
+
+// NewManager creates a new Manager.
+func NewManager(cfg *config.Config) *Manager {
+	return &Manager{
+		config: cfg,
+	}
+}
+
+// CanElicit checks if elicitation is enabled and the client supports form elicitation.
+func (em *Manager) CanElicit(session *mcp.ServerSession) bool {
+	if em == nil || !em.config.Elicitation.Enabled {
+		slog.Debug("Elicitation is disabled")
+		return false
+	}
+	if session == nil {
+		slog.Debug("ServerSession is nil, cannot perform elicitation")
+		return false
+	}
+
+	initParams := session.InitializeParams()
+	if initParams == nil || initParams.Capabilities == nil {
+		slog.Debug("Client capabilities not available")
+		return false
+	}
+
+	caps := initParams.Capabilities.Elicitation
+	// Form is implied when neither mode is declared (pre-2025-11 clients)
+	if caps == nil || (caps.Form == nil && caps.URL != nil) {
+		slog.Debug("Client does not support form elicitation")
+		return false
+	}
+	return true
+}
+
+// Choose asks the user to pick one of options and returns the selected Value.
+// ok is false when elicitation is unavailable or the user declined or cancelled;
+// callers then fall back to their non-interactive behavior.
+func (em *Manager) Choose(ctx context.Context, session *mcp.ServerSession, message string, options []Option) (value string, ok bool, err error) {
+	if len(options) == 0 || !em.CanElicit(session) {
+		return "", false, nil
+	}
+
+	values := make([]string, len(options))
+	titles := make([]string, len(options))
+	for i, o := range options {
+		values[i] = o.Value
+		titles[i] = o.Title
+	}
+
+	slog.Debug("Sending elicitation request", "message", message, "option_count", len(options))
+	result, err := session.Elicit(ctx, &mcp.ElicitParams{
+		Message: message,
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				choiceField: map[string]any{
+					"type":      "string",
+					"title":     "Choice",
+					"enum":      values,
+					"enumNames": titles,
+				},
+			},
+			"required": []string{choiceField},
+		},
+	})
+	if err != nil {
+		slog.Warn("Elicitation request failed", "error", err)
+		return "", false, fmt.Errorf("elicitation request failed: %w", err)
+	}
+	if result.Action != ActionAccept {
+		slog.Debug("Elicitation was not accepted", "action", result.Action)
+		return "", false, nil
+	}
+
+	value, _ = result.Content[choiceField].(string)
+	return value, value != "", nil
+}
@@ -0,0 +1,82 @@
+package elicitation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+)
+
+// connect returns the server session of an in-memory client/server pair.
+func connect(t *testing.T, clientOpts *mcp.ClientOptions) *mcp.ServerSession {
+	t.Helper()
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "test"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, clientOpts)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+	return serverSession
+}
+
+func enabled() *Manager {
+	return NewManager(&config.Config{Elicitation: config.ElicitationOpts{Enabled: true}})
+}
+
+func TestManager_CanElicit(t *testing.T) {
+	var nilManager *Manager
+	assert.False(t, nilManager.CanElicit(nil))
+	assert.False(t, enabled().CanElicit(nil), "nil session")
+
+	noElicit := connect(t, nil)
+	assert.False(t, enabled().CanElicit(noElicit), "client without elicitation capability")
+
+	withElicit := connect(t, &mcp.ClientOptions{
+		ElicitationHandler: func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			return &mcp.ElicitResult{Action: ActionCancel}, nil
+		},
+	})
+	assert.True(t, enabled().CanElicit(withElicit))
+	assert.False(t, NewManager(&config.Config{}).CanElicit(withElicit), "disabled by config")
+}
+
+func TestManager_Choose(t *testing.T) {
+	var got *mcp.ElicitParams
+	session := connect(t, &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			got = req.Params
+			return &mcp.ElicitResult{Action: ActionAccept, Content: map[string]any{"choice": "ch11.html"}}, nil
+		},
+	})
+
+	value, ok, err := enabled().Choose(context.Background(), session, "Which chapter?", []Option{
+		{Value: "ch10.html", Title: "10. Batch Processing"},
+		{Value: "ch11.html", Title: "11. Stream Processing"},
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ch11.html", value)
+	require.NotNil(t, got)
+	assert.Equal(t, "Which chapter?", got.Message)
+}
+
+func TestManager_Choose_Declined(t *testing.T) {
+	session := connect(t, &mcp.ClientOptions{
+		ElicitationHandler: func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			return &mcp.ElicitResult{Action: ActionDecline}, nil
+		},
+	})
+
+	value, ok, err := enabled().Choose(context.Background(), session, "Continue?", []Option{{Value: "yes", Title: "Yes"}})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, value)
+}
@@ -0,0 +1,437 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fsutil"
+)
+
+// Files written to the export directory besides the chapters.
+const (
+	IndexFile    = "index.md"
+	ManifestFile = ".export-manifest.json"
+	ImagesDir    = "images"
+)
+
+// DefaultConcurrency is the number of chapters fetched at once when Options.Concurrency is not set.
+const DefaultConcurrency = 4
+
+var reUnsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Options controls a book export.
+type Options struct {
+	OutputDir   string       // directory to write to, created if missing
+	Concurrency int          // chapters fetched at once (default: DefaultConcurrency)
+	Force       bool         // re-export chapters already recorded in the manifest
+	OnProgress  ProgressFunc // called after each chapter; may be nil
+}
+
+// Progress reports how far an export has come.
+type Progress struct {
+	Done    int    // chapters finished, including failed and resumed ones
+	Total   int    // chapters in the book
+	Chapter string // title of the chapter just finished
+}
+
+// ProgressFunc is called after each chapter. Calls are serialized.
+type ProgressFunc func(Progress)
+
+// FailedChapter is a chapter that could not be exported.
+type FailedChapter struct {
+	Title string `json:"title"`
+	Href  string `json:"href"`
+	Error string `json:"error"`
+}
+
+// Result summarizes an export.
+type Result struct {
+	BookID       string          `json:"book_id"`
+	Title        string          `json:"title"`
+	OutputDir    string          `json:"output_dir"`
+	IndexPath    string          `json:"index_path"`
+	Chapters     int             `json:"chapters"`
+	Exported     int             `json:"exported"`
+	Resumed      int             `json:"resumed"` // chapters skipped because a previous run finished them
+	Images       int             `json:"images"`
+	FailedImages int             `json:"failed_images"`
+	Failed       []FailedChapter `json:"failed,omitempty"`
+}
+
+// manifest records finished chapters so that an interrupted export can resume.
+type manifest struct {
+	BookID   string                   `json:"book_id"`
+	Chapters map[string]chapterRecord `json:"chapters"` // keyed by chapter file href
+}
+
+type chapterRecord struct {
+	File   string `json:"file"`
+	Title  string `json:"title"`
+	Images int    `json:"images"`
+}
+
+// chapter is one chapter file of the book, in TOC order.
+type chapter struct {
+	href  string
+	title string
+	file  string // Markdown file name in the output directory
+}
+
+// exporter holds the state shared by the chapter workers.
+type exporter struct {
+	client    browser.Client
+	productID string
+	dir       string
+
+	mu       sync.Mutex
+	manifest *manifest
+	images   map[string]*imageDownload // keyed by book-relative path
+	result   *Result
+}
+
+// imageDownload is an image download shared by the chapters that reference it.
+type imageDownload struct {
+	done chan struct{} // closed when the download has finished
+	ok   bool
+}
+
+// Book exports a book to opts.OutputDir: an index file built from the book
+// details, one Markdown file per chapter and the chapter images under
+// images/, referenced by relative paths. Chapters are fetched with bounded
+// concurrency; a chapter that fails is reported in the result and the export
+// continues. Finished chapters are recorded in a manifest, so running the
+// export again after an interruption only fetches what is missing.
+func Book(ctx context.Context, client browser.Client, productID string, opts Options) (*Result, error) {
+	if client == nil {
+		return nil, errors.New("browser client is not available")
+	}
+	if opts.OutputDir == "" {
+		return nil, errors.New("output directory is required")
+	}
+	dir, err := filepath.Abs(opts.OutputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	toc, err := client.GetBookTOC(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book TOC: %w", err)
+	}
+	details, err := client.GetBookDetails(ctx, productID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		slog.Warn("書籍詳細を取得できないため目次の情報で索引を作成します", "product_id", productID, "error", err)
+		details = &browser.BookDetailResponse{Identifier: productID, Title: toc.BookTitle}
+	}
+
+	chapters := bookChapters(toc)
+	e := &exporter{
+		client:    client,
+		productID: productID,
+		dir:       dir,
+		manifest:  loadManifest(dir, productID, opts.Force),
+		images:    map[string]*imageDownload{},
+		result: &Result{
+			BookID:    productID,
+			Title:     details.Title,
+			OutputDir: dir,
+			IndexPath: filepath.Join(dir, IndexFile),
+			Chapters:  len(chapters),
+		},
+	}
+	slog.Info("書籍のエクスポートを開始します", "product_id", productID, "chapters", len(chapters), "output_dir", dir)
+
+	jobs := make(chan chapter)
+	var wg sync.WaitGroup
+	done := 0
+	for range min(concurrency, max(len(chapters), 1)) {
+		wg.Go(func() {
+			for ch := range jobs {
+				e.exportChapter(ctx, ch)
+				e.mu.Lock()
+				done++
+				if opts.OnProgress != nil {
+					opts.OnProgress(Progress{Done: done, Total: len(chapters), Chapter: ch.title})
+				}
+				e.mu.Unlock()
+			}
+		})
+	}
+feed:
+	for _, ch := range chapters {
+		select {
+		case jobs <- ch:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := fsutil.WriteFileAtomic(e.result.IndexPath, []byte(renderIndex(details, toc, chapters, e.manifest)), 0755); err != nil {
+		return e.result, fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		slog.Info("書籍のエクスポートを中断しました", "product_id", productID, "exported", e.result.Exported)
+		return e.result, err
+	}
+	slog.Info("書籍のエクスポートが完了しました", "product_id", productID,
+		"exported", e.result.Exported, "resumed", e.result.Resumed, "failed", len(e.result.Failed))
+	return e.result, nil
+}
+
+// bookChapters returns the chapter files of the book in TOC order. TOC
+// entries pointing into the same file (a chapter and its sections) are one
+// chapter, titled by the entry without a fragment when there is one.
+func bookChapters(toc *browser.TableOfContentsResponse) []chapter {
+	var chapters []chapter
+	byHref := map[string]int{}
+	for _, item := range toc.TableOfContents {
+		href, fragment, _ := strings.Cut(item.Href, "#")
+		if href == "" {
+			continue
+		}
+		if i, ok := byHref[href]; ok {
+			if fragment == "" {
+				chapters[i].title = item.Title
+			}
+			continue
+		}
+		byHref[href] = len(chapters)
+		chapters = append(chapters, chapter{href: href, title: item.Title})
+	}
+
+	width := len(fmt.Sprint(len(chapters)))
+	for i := range chapters {
+		base := path.Base(chapters[i].href)
+		stem := strings.Trim(reUnsafeFileChars.ReplaceAllString(strings.TrimSuffix(base, path.Ext(base)), "-"), "-")
+		if stem == "" {
+			stem = "chapter"
+		}
+		chapters[i].file = fmt.Sprintf("%0*d-%s.md", max(width, 2), i+1, stem)
+	}
+	return chapters
+}
+
+// exportChapter fetches one chapter with its images and writes it, unless the
+// manifest shows a previous run already did.
+func (e *exporter) exportChapter(ctx context.Context, ch chapter) {
+	if ctx.Err() != nil {
+		return
+	}
+	e.mu.Lock()
+	record, ok := e.manifest.Chapters[ch.href]
+	e.mu.Unlock()
+	if ok && record.File == ch.file {
+		if _, err := os.Stat(filepath.Join(e.dir, ch.file)); err == nil {
+			e.mu.Lock()
+			e.result.Resumed++
+			e.mu.Unlock()
+			return
+		}
+	}
+
+	content, err := e.client.GetBookChapterContent(ctx, e.productID, ch.href)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("チャプターのエクスポートに失敗しました", "product_id", e.productID, "href", ch.href, "error", err)
+		e.fail(ch, err)
+		return
+	}
+	if content.ChapterTitle == "" {
+		content.ChapterTitle = ch.title
+	}
+
+	images := 0
+	for i := range content.Content.Sections {
+		images += e.localizeImages(ctx, content.Content.Sections[i].Content, content.SourceURL)
+	}
+	if err := fsutil.WriteFileAtomic(filepath.Join(e.dir, ch.file), []byte(content.Markdown()), 0755); err != nil {
+		e.fail(ch, err)
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.manifest.Chapters[ch.href] = chapterRecord{File: ch.file, Title: ch.title, Images: images}
+	e.result.Exported++
+	if err := e.saveManifest(); err != nil {
+		slog.Warn("エクスポートのマニフェストを保存できませんでした", "error", err)
+	}
+}
+
+func (e *exporter) fail(ch chapter, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.result.Failed = append(e.result.Failed, FailedChapter{Title: ch.title, Href: ch.href, Error: err.Error()})
+}
+
+// localizeImages downloads the book images in content and points them at the
+// local copies. Images that cannot be downloaded keep their original source.
+// It returns the number of images pointing at local copies.
+func (e *exporter) localizeImages(ctx context.Context, content []any, baseURL string) int {
+	n := 0
+	for i, elem := range content {
+		switch el := elem.(type) {
+		case htmlparse.ImageElement:
+			imagePath := browser.ResolveBookFilePath(e.productID, baseURL, el.Src)
+			if imagePath == "" || !e.downloadImage(ctx, imagePath) {
+				continue
+			}
+			el.URI = imageLink(imagePath)
+			content[i] = el
+			n++
+		case htmlparse.AdmonitionElement:
+			n += e.localizeImages(ctx, el.Content, baseURL)
+		}
+	}
+	return n
+}
+
+// downloadImage writes an image under images/ unless it is already there and
+// reports whether the local copy exists. Chapters sharing an image download it
+// once per run; the others wait for that download.
+func (e *exporter) downloadImage(ctx context.Context, imagePath string) bool {
+	rel := filepath.Join(ImagesDir, filepath.FromSlash(imagePath))
+	if !filepath.IsLocal(rel) {
+		return false
+	}
+
+	e.mu.Lock()
+	d, claimed := e.images[imagePath]
+	if !claimed {
+		d = &imageDownload{done: make(chan struct{})}
+		e.images[imagePath] = d
+	}
+	e.mu.Unlock()
+	if claimed {
+		<-d.done
+		return d.ok
+	}
+	defer close(d.done)
+
+	local := filepath.Join(e.dir, rel)
+	if _, err := os.Stat(local); err == nil {
+		d.ok = true
+		return true
+	}
+	image, err := e.client.GetBookImage(ctx, e.productID, imagePath)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(local, image.Data, 0755)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		slog.Debug("画像をエクスポートできませんでした", "product_id", e.productID, "path", imagePath, "error", err)
+		e.result.FailedImages++
+		return false
+	}
+	e.result.Images++
+	d.ok = true
+	return true
+}
+
+// imageLink returns the relative Markdown link to a local image copy.
+func imageLink(imagePath string) string {
+	segments := strings.Split(imagePath, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return ImagesDir + "/" + strings.Join(segments, "/")
+}
+
+// loadManifest reads the manifest of a previous export of the same book.
+// A missing or foreign manifest, or force, starts a fresh one.
+func loadManifest(dir, productID string, force bool) *manifest {
+	fresh := &manifest{BookID: productID, Chapters: map[string]chapterRecord{}}
+	if force {
+		return fresh
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return fresh
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil || m.BookID != productID || m.Chapters == nil {
+		slog.Warn("エクスポートのマニフェストを利用できないため最初からエクスポートします", "dir", dir, "error", err)
+		return fresh
+	}
+	return &m
+}
+
+// saveManifest writes the manifest. The caller must hold e.mu.
+func (e *exporter) saveManifest() error {
+	data, err := json.MarshalIndent(e.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(filepath.Join(e.dir, ManifestFile), data, 0755)
+}
+
+// renderIndex renders the index file: book details and a contents list that
+// links each exported chapter.
+func renderIndex(details *browser.BookDetailResponse, toc *browser.TableOfContentsResponse, chapters []chapter, m *manifest) string {
+	var b strings.Builder
+	title := details.Title
+	if title == "" {
+		title = toc.BookTitle
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	fields := []struct{ name, value string }{
+		{"ISBN", details.ISBN},
+		{"Publication date", details.PublicationDate},
+		{"Language", details.Language},
+		{"Source", details.URL},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", f.name, f.value)
+		}
+	}
+	if details.PageCount > 0 {
+		fmt.Fprintf(&b, "- **Pages:** %d\n", details.PageCount)
+	}
+	if len(details.Tags) > 0 {
+		fmt.Fprintf(&b, "- **Tags:** %s\n", strings.Join(details.Tags, ", "))
+	}
+	b.WriteString("\n")
+
+	if desc := details.Descriptions["text/plain"]; desc != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(desc))
+	}
+
+	b.WriteString("## Contents\n\n")
+	for _, ch := range chapters {
+		if record, ok := m.Chapters[ch.href]; ok {
+			fmt.Fprintf(&b, "1. [%s](%s)\n", ch.title, url.PathEscape(record.File))
+		} else {
+			fmt.Fprintf(&b, "1. %s (not exported)\n", ch.title)
+		}
+	}
+	b.WriteString("\n---\n\nExported from O'Reilly Learning for offline study. Cite the book, its authors and O'Reilly Media when quoting it.\n")
+	return b.String()
+}
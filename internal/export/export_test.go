@@ -0,0 +1,189 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+const filesURL = "https://learning.oreilly.com/api/v2/epubs/urn:orm:book:123/files/"
+
+// fakeClient serves a two-chapter book. Methods not used by the exporter panic.
+type fakeClient struct {
+	browser.Client
+
+	mu          sync.Mutex
+	chapterErrs map[string]error
+	chapterReqs []string
+	imageReqs   []string
+}
+
+func (f *fakeClient) GetBookTOC(_ context.Context, productID string) (*browser.TableOfContentsResponse, error) {
+	return &browser.TableOfContentsResponse{
+		BookID:    productID,
+		BookTitle: "Test Book",
+		TableOfContents: []browser.TableOfContentsItem{
+			{ID: "pr", Title: "Preface", Href: "preface.html", Level: 1},
+			{ID: "ch01", Title: "Chapter 1. Basics", Href: "ch01.html", Level: 1},
+			{ID: "ch01-s1", Title: "Setup", Href: "ch01.html#setup", Level: 2, Parent: "ch01"},
+			{ID: "ch02", Title: "Chapter 2. Advanced", Href: "ch02.html", Level: 1},
+		},
+	}, nil
+}
+
+func (f *fakeClient) GetBookDetails(_ context.Context, productID string) (*browser.BookDetailResponse, error) {
+	return &browser.BookDetailResponse{
+		Identifier:   productID,
+		Title:        "Test Book",
+		ISBN:         "9780000000000",
+		Descriptions: map[string]string{"text/plain": "A book for tests."},
+	}, nil
+}
+
+func (f *fakeClient) GetBookChapterContent(_ context.Context, productID, chapterName string) (*browser.ChapterContentResponse, error) {
+	f.mu.Lock()
+	f.chapterReqs = append(f.chapterReqs, chapterName)
+	err := f.chapterErrs[chapterName]
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return &browser.ChapterContentResponse{
+		BookID:      productID,
+		ChapterName: chapterName,
+		SourceURL:   filesURL + chapterName,
+		Content: htmlparse.ParsedChapterContent{
+			Title: "Title of " + chapterName,
+			Sections: []htmlparse.ContentSection{{
+				Heading: htmlparse.ContentHeading{Level: 1, Text: "Body"},
+				Content: []any{
+					htmlparse.ImageElement{Type: "image", Src: "assets/figure.png", Alt: "Figure"},
+					htmlparse.AdmonitionElement{Type: "admonition", Kind: "note", Content: []any{
+						htmlparse.ImageElement{Type: "image", Src: "assets/missing.png", Alt: "Missing"},
+					}},
+					htmlparse.ImageElement{Type: "image", Src: "https://cdn.example.com/x.png", Alt: "External"},
+				},
+			}},
+		},
+	}, nil
+}
+
+func (f *fakeClient) GetBookImage(_ context.Context, productID, imagePath string) (*browser.BookImage, error) {
+	f.mu.Lock()
+	f.imageReqs = append(f.imageReqs, imagePath)
+	f.mu.Unlock()
+	if imagePath != "assets/figure.png" {
+		return nil, errors.New("not found")
+	}
+	return &browser.BookImage{BookID: productID, Path: imagePath, MIMEType: "image/png", Data: []byte("png")}, nil
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestBook(t *testing.T) {
+	dir := t.TempDir()
+	client := &fakeClient{}
+	var progress []Progress
+
+	result, err := Book(t.Context(), client, "123", Options{
+		OutputDir:   dir,
+		Concurrency: 2,
+		OnProgress:  func(p Progress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Chapters, "sections sharing a chapter file are one chapter")
+	assert.Equal(t, 3, result.Exported)
+	assert.Equal(t, 1, result.Images, "a shared image is downloaded once")
+	assert.Equal(t, 1, result.FailedImages)
+	assert.ElementsMatch(t, []string{"preface.html", "ch01.html", "ch02.html"}, client.chapterReqs)
+	require.Len(t, progress, 3)
+	assert.Equal(t, Progress{Done: 3, Total: 3, Chapter: progress[2].Chapter}, progress[2])
+
+	index := readFile(t, filepath.Join(dir, IndexFile))
+	assert.Contains(t, index, "# Test Book")
+	assert.Contains(t, index, "**ISBN:** 9780000000000")
+	assert.Contains(t, index, "A book for tests.")
+	assert.Contains(t, index, "1. [Preface](01-preface.md)")
+	assert.Contains(t, index, "1. [Chapter 1. Basics](02-ch01.md)", "the chapter entry titles the file, not its sections")
+
+	chapter := readFile(t, filepath.Join(dir, "02-ch01.md"))
+	assert.Contains(t, chapter, "# Title of ch01.html")
+	assert.Contains(t, chapter, "![Figure](images/assets/figure.png)")
+	assert.Contains(t, chapter, "![Missing](assets/missing.png)", "images that fail to download keep their source")
+	assert.Contains(t, chapter, "![External](https://cdn.example.com/x.png)")
+	assert.Equal(t, "png", readFile(t, filepath.Join(dir, ImagesDir, "assets", "figure.png")))
+}
+
+func TestBook_Resume(t *testing.T) {
+	dir := t.TempDir()
+	client := &fakeClient{chapterErrs: map[string]error{"ch02.html": errors.New("boom")}}
+
+	result, err := Book(t.Context(), client, "123", Options{OutputDir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Exported)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "ch02.html", result.Failed[0].Href)
+	assert.Contains(t, readFile(t, filepath.Join(dir, IndexFile)), "1. Chapter 2. Advanced (not exported)")
+
+	// The second run only fetches the chapter that failed
+	client = &fakeClient{}
+	result, err = Book(t.Context(), client, "123", Options{OutputDir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch02.html"}, client.chapterReqs)
+	assert.Equal(t, 1, result.Exported)
+	assert.Equal(t, 2, result.Resumed)
+	assert.NotContains(t, client.imageReqs, "assets/figure.png", "images already on disk are not downloaded again")
+	assert.Contains(t, readFile(t, filepath.Join(dir, IndexFile)), "1. [Chapter 2. Advanced](03-ch02.md)")
+
+	// A chapter file removed since is fetched again
+	require.NoError(t, os.Remove(filepath.Join(dir, "01-preface.md")))
+	client = &fakeClient{}
+	_, err = Book(t.Context(), client, "123", Options{OutputDir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"preface.html"}, client.chapterReqs)
+
+	// Force re-exports everything
+	client = &fakeClient{}
+	result, err = Book(t.Context(), client, "123", Options{OutputDir: dir, Force: true})
+	require.NoError(t, err)
+	assert.Len(t, client.chapterReqs, 3)
+	assert.Equal(t, 0, result.Resumed)
+}
+
+func TestBook_Cancelled(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	result, err := Book(ctx, &fakeClient{}, "123", Options{OutputDir: dir})
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.Equal(t, 0, result.Exported)
+	assert.FileExists(t, filepath.Join(dir, IndexFile), "the index is written for what was exported")
+}
+
+func TestBookChapters_FileNames(t *testing.T) {
+	chapters := bookChapters(&browser.TableOfContentsResponse{TableOfContents: []browser.TableOfContentsItem{
+		{Title: "Part I", Href: "part01.html#p1"},
+		{Title: "Odd name", Href: "text/Chapter 2 (draft).xhtml"},
+		{Title: "No href"},
+	}})
+	require.Len(t, chapters, 2)
+	assert.Equal(t, "01-part01.md", chapters[0].file)
+	assert.Equal(t, "Part I", chapters[0].title)
+	assert.Equal(t, "02-Chapter-2-draft.md", chapters[1].file)
+}
@@ -0,0 +1,311 @@
+package fulltext
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fsutil"
+)
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// headingWeight is how many times heading terms count relative to body terms.
+	headingWeight = 2
+)
+
+// Document is one section of a chapter.
+type Document struct {
+	BookID       string `json:"book_id"`
+	BookTitle    string `json:"book_title,omitempty"`
+	ChapterName  string `json:"chapter_name"`
+	ChapterTitle string `json:"chapter_title,omitempty"`
+	SectionIndex int    `json:"section_index"`
+	Heading      string `json:"heading,omitempty"`
+	Text         string `json:"text"`
+}
+
+// Hit is a section matching a search, best first.
+type Hit struct {
+	BookID       string  `json:"book_id"`
+	BookTitle    string  `json:"book_title,omitempty"`
+	ChapterName  string  `json:"chapter_name"`
+	ChapterTitle string  `json:"chapter_title,omitempty"`
+	SectionIndex int     `json:"section_index"`
+	Heading      string  `json:"heading,omitempty"`
+	Score        float64 `json:"score"`
+	Snippet      string  `json:"snippet"`
+}
+
+// SearchOptions narrows a search.
+type SearchOptions struct {
+	BookID string // only sections of this book when set
+	Limit  int    // maximum hits returned (0 means all)
+}
+
+// Stats describes the contents of the index.
+type Stats struct {
+	Books    int `json:"books"`
+	Chapters int `json:"chapters"`
+	Sections int `json:"sections"`
+}
+
+// chapterFile is the on-disk form of one indexed chapter.
+type chapterFile struct {
+	BookID      string     `json:"book_id"`
+	ChapterName string     `json:"chapter_name"`
+	IndexedAt   time.Time  `json:"indexed_at"`
+	Documents   []Document `json:"documents"`
+}
+
+type indexedDoc struct {
+	Document
+	length int
+	terms  []string // distinct terms, for removal
+}
+
+// Index is an inverted index over chapter sections ranked with BM25.
+//
+// Each chapter is persisted as a JSON file under the index directory and the
+// postings are rebuilt in memory on first use, so the index works without
+// network access. A nil *Index is valid: it indexes nothing and finds nothing.
+type Index struct {
+	dir string
+
+	loadOnce sync.Once
+	mu       sync.RWMutex
+	docs     map[int]*indexedDoc
+	chapters map[string][]int       // chapter key → document IDs
+	postings map[string]map[int]int // term → document ID → term frequency
+	totalLen int
+	nextID   int
+}
+
+// New returns an index rooted at dir. With an empty dir the index lives in
+// memory only.
+func New(dir string) *Index {
+	return &Index{
+		dir:      dir,
+		docs:     map[int]*indexedDoc{},
+		chapters: map[string][]int{},
+		postings: map[string]map[int]int{},
+	}
+}
+
+// Open returns the index described by opts, or nil when it is disabled. Open
+// it once and share it, so that chapters indexed by the browser client are
+// immediately searchable from the server.
+func Open(opts config.FullTextOpts) *Index {
+	if !opts.Enabled || opts.Dir == "" {
+		return nil
+	}
+	return New(opts.Dir)
+}
+
+func chapterKey(bookID, chapterName string) string { return bookID + "/" + chapterName }
+
+func (ix *Index) chapterPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(ix.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads the persisted chapters once.
+func (ix *Index) load() {
+	ix.loadOnce.Do(func() {
+		if ix.dir == "" {
+			return
+		}
+		entries, err := os.ReadDir(ix.dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				slog.Warn("全文検索インデックスの読み込みに失敗しました", "dir", ix.dir, "error", err)
+			}
+			return
+		}
+		ix.mu.Lock()
+		defer ix.mu.Unlock()
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(ix.dir, e.Name()))
+			if err != nil {
+				continue
+			}
+			var cf chapterFile
+			if err := json.Unmarshal(data, &cf); err != nil {
+				slog.Warn("全文検索インデックスの破損したファイルを無視します", "file", e.Name(), "error", err)
+				continue
+			}
+			ix.replaceLocked(chapterKey(cf.BookID, cf.ChapterName), cf.Documents)
+		}
+		slog.Debug("全文検索インデックスを読み込みました", "dir", ix.dir, "sections", len(ix.docs))
+	})
+}
+
+// IndexChapter replaces the sections indexed for a chapter and persists them.
+func (ix *Index) IndexChapter(bookID, chapterName string, docs []Document) error {
+	if ix == nil {
+		return nil
+	}
+	ix.load()
+	key := chapterKey(bookID, chapterName)
+	if ix.dir == "" {
+		ix.mu.Lock()
+		defer ix.mu.Unlock()
+		ix.replaceLocked(key, docs)
+		return nil
+	}
+	data, err := json.Marshal(chapterFile{BookID: bookID, ChapterName: chapterName, IndexedAt: time.Now().UTC(), Documents: docs})
+	if err != nil {
+		return fmt.Errorf("failed to encode chapter index: %w", err)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.replaceLocked(key, docs)
+	return fsutil.WriteFileAtomic(ix.chapterPath(key), data, 0700)
+}
+
+// replaceLocked swaps the documents of a chapter in the postings. The caller must hold ix.mu.
+func (ix *Index) replaceLocked(key string, docs []Document) {
+	for _, id := range ix.chapters[key] {
+		doc := ix.docs[id]
+		for _, term := range doc.terms {
+			delete(ix.postings[term], id)
+			if len(ix.postings[term]) == 0 {
+				delete(ix.postings, term)
+			}
+		}
+		ix.totalLen -= doc.length
+		delete(ix.docs, id)
+	}
+	delete(ix.chapters, key)
+
+	for _, d := range docs {
+		id := ix.nextID
+		ix.nextID++
+		freqs := map[string]int{}
+		length := 0
+		for _, term := range Tokenize(d.Heading) {
+			freqs[term] += headingWeight
+			length += headingWeight
+		}
+		for _, term := range Tokenize(d.Text) {
+			freqs[term]++
+			length++
+		}
+		doc := &indexedDoc{Document: d, length: length}
+		for term, tf := range freqs {
+			if ix.postings[term] == nil {
+				ix.postings[term] = map[int]int{}
+			}
+			ix.postings[term][id] = tf
+			doc.terms = append(doc.terms, term)
+		}
+		ix.docs[id] = doc
+		ix.totalLen += length
+		ix.chapters[key] = append(ix.chapters[key], id)
+	}
+}
+
+// Search ranks the sections matching any query term with BM25 and returns
+// up to opts.Limit hits with snippets, along with the total number of matches.
+func (ix *Index) Search(query string, opts SearchOptions) ([]Hit, int) {
+	if ix == nil {
+		return nil, 0
+	}
+	ix.load()
+	terms := slices.Compact(slices.Sorted(slices.Values(Tokenize(query))))
+	if len(terms) == 0 {
+		return nil, 0
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	n := float64(len(ix.docs))
+	if n == 0 {
+		return nil, 0
+	}
+	avgLen := float64(ix.totalLen) / n
+	scores := map[int]float64{}
+	for _, term := range terms {
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range postings {
+			doc := ix.docs[id]
+			if opts.BookID != "" && doc.BookID != opts.BookID {
+				continue
+			}
+			f := float64(tf)
+			scores[id] += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*float64(doc.length)/avgLen))
+		}
+	}
+
+	ids := make([]int, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b int) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return a - b
+	})
+	total := len(ids)
+	if opts.Limit > 0 && len(ids) > opts.Limit {
+		ids = ids[:opts.Limit]
+	}
+
+	hits := make([]Hit, 0, len(ids))
+	for _, id := range ids {
+		doc := ix.docs[id]
+		hits = append(hits, Hit{
+			BookID:       doc.BookID,
+			BookTitle:    doc.BookTitle,
+			ChapterName:  doc.ChapterName,
+			ChapterTitle: doc.ChapterTitle,
+			SectionIndex: doc.SectionIndex,
+			Heading:      doc.Heading,
+			Score:        math.Round(scores[id]*1000) / 1000,
+			Snippet:      Snippet(doc.Text, terms, snippetLength),
+		})
+	}
+	return hits, total
+}
+
+// Stats returns the number of books, chapters and sections in the index.
+func (ix *Index) Stats() Stats {
+	if ix == nil {
+		return Stats{}
+	}
+	ix.load()
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	books := map[string]bool{}
+	for key := range ix.chapters {
+		book, _, _ := strings.Cut(key, "/")
+		books[book] = true
+	}
+	return Stats{Books: len(books), Chapters: len(ix.chapters), Sections: len(ix.docs)}
+}
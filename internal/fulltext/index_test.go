@@ -0,0 +1,136 @@
+package fulltext
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+)
+
+func section(bookID, chapter string, index int, heading, text string) Document {
+	return Document{BookID: bookID, BookTitle: "Book " + bookID, ChapterName: chapter, SectionIndex: index, Heading: heading, Text: text}
+}
+
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+	ix := New(t.TempDir())
+	require.NoError(t, ix.IndexChapter("111", "ch01.html", []Document{
+		section("111", "ch01.html", 0, "Retries", "Clients retry requests after timeouts. Retries need care."),
+		section("111", "ch01.html", 1, "Idempotency keys", "An idempotency key lets the server detect a retried request."),
+		section("111", "ch01.html", 2, "Summary", "Nothing about the topic here."),
+	}))
+	require.NoError(t, ix.IndexChapter("222", "ch02.html", []Document{
+		section("222", "ch02.html", 0, "冪等性", "冪等性キーを使うと、再試行されたリクエストを検出できます。"),
+	}))
+	return ix
+}
+
+func TestIndex_SearchRanksWithBM25(t *testing.T) {
+	ix := newTestIndex(t)
+
+	hits, total := ix.Search("idempotency key", SearchOptions{})
+	require.Equal(t, 1, total)
+	assert.Equal(t, "Idempotency keys", hits[0].Heading)
+	assert.Equal(t, "Book 111", hits[0].BookTitle)
+	assert.Positive(t, hits[0].Score)
+
+	hits, total = ix.Search("retry request", SearchOptions{})
+	require.Equal(t, 2, total)
+	assert.Equal(t, 0, hits[0].SectionIndex, "the section repeating the terms ranks first")
+
+	hits, _ = ix.Search("retries", SearchOptions{Limit: 1})
+	assert.Len(t, hits, 1)
+}
+
+func TestIndex_SearchCJK(t *testing.T) {
+	ix := newTestIndex(t)
+
+	hits, total := ix.Search("冪等性", SearchOptions{})
+	require.Equal(t, 1, total)
+	assert.Equal(t, "222", hits[0].BookID)
+	assert.Contains(t, hits[0].Snippet, "冪等性キー")
+
+	_, total = ix.Search("再試行", SearchOptions{})
+	assert.Equal(t, 1, total, "words inside a longer run are found through bigrams")
+}
+
+func TestIndex_SearchFiltersByBook(t *testing.T) {
+	ix := newTestIndex(t)
+	require.NoError(t, ix.IndexChapter("222", "ch03.html", []Document{
+		section("222", "ch03.html", 0, "Retries", "Retry with backoff."),
+	}))
+
+	hits, total := ix.Search("retry", SearchOptions{BookID: "222"})
+	require.Equal(t, 1, total)
+	assert.Equal(t, "ch03.html", hits[0].ChapterName)
+}
+
+func TestIndex_IndexChapterReplaces(t *testing.T) {
+	ix := newTestIndex(t)
+	require.NoError(t, ix.IndexChapter("111", "ch01.html", []Document{
+		section("111", "ch01.html", 0, "Rewritten", "Completely different text."),
+	}))
+
+	_, total := ix.Search("idempotency", SearchOptions{})
+	assert.Equal(t, 0, total, "the previous sections of the chapter are gone")
+	_, total = ix.Search("different", SearchOptions{})
+	assert.Equal(t, 1, total)
+	assert.Equal(t, Stats{Books: 2, Chapters: 2, Sections: 2}, ix.Stats())
+}
+
+func TestIndex_PersistsAcrossOpen(t *testing.T) {
+	dir := t.TempDir()
+	ix := New(dir)
+	require.NoError(t, ix.IndexChapter("111", "ch01.html", []Document{
+		section("111", "ch01.html", 0, "Consensus", "Raft elects a leader."),
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600))
+
+	reopened := New(dir)
+	hits, total := reopened.Search("raft leader", SearchOptions{})
+	require.Equal(t, 1, total, "corrupt files are skipped")
+	assert.Equal(t, "Consensus", hits[0].Heading)
+}
+
+func TestOpen(t *testing.T) {
+	assert.Nil(t, Open(config.FullTextOpts{Enabled: false, Dir: t.TempDir()}))
+
+	assert.Nil(t, Open(config.FullTextOpts{Enabled: true}))
+	assert.NotNil(t, Open(config.FullTextOpts{Enabled: true, Dir: t.TempDir()}))
+}
+
+func TestIndex_Nil(t *testing.T) {
+	var ix *Index
+	assert.NoError(t, ix.IndexChapter("111", "ch01.html", nil))
+	hits, total := ix.Search("anything", SearchOptions{})
+	assert.Empty(t, hits)
+	assert.Zero(t, total)
+	assert.Equal(t, Stats{}, ix.Stats())
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("filler words here ", 30) + "the Idempotency key matters " + strings.Repeat("tail text ", 30)
+
+	s := Snippet(text, []string{"idempotency"}, 80)
+	assert.True(t, strings.HasPrefix(s, "…"))
+	assert.True(t, strings.HasSuffix(s, "…"))
+	assert.Contains(t, s, "Idempotency key")
+	assert.LessOrEqual(t, len([]rune(s)), 82)
+
+	assert.Equal(t, "short text", Snippet("short\ntext", []string{"x"}, 80), "whitespace is collapsed")
+	assert.True(t, strings.HasPrefix(Snippet(text, []string{"absent"}, 40), "filler"), "without a match the start is used")
+}
+
+func TestIndex_InMemory(t *testing.T) {
+	ix := New("")
+	require.NoError(t, ix.IndexChapter("111", "ch01.html", []Document{
+		section("111", "ch01.html", 0, "Consensus", "Raft elects a leader."),
+	}))
+
+	_, total := ix.Search("raft", SearchOptions{})
+	assert.Equal(t, 1, total)
+}
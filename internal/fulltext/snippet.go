@@ -0,0 +1,66 @@
+package fulltext
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// snippetLength is the length of search snippets in runes.
+const snippetLength = 200
+
+// Snippet returns about length runes of text around the first occurrence of
+// any of terms, on a single line, with "…" where text was cut. Without a
+// match the start of text is returned.
+func Snippet(text string, terms []string, length int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= length {
+		return string(runes)
+	}
+
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	pos := -1
+	for _, term := range terms {
+		if i := indexRunes(lower, []rune(term)); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+
+	start := 0
+	if pos > length/3 {
+		start = pos - length/3
+		// Start at a word boundary when one is near
+		for i := start; i < pos && i < start+20; i++ {
+			if runes[i] == ' ' {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := min(start+length, len(runes))
+
+	s := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(runes) {
+		s += "…"
+	}
+	return s
+}
+
+// indexRunes returns the index of the first occurrence of sub in s, or -1.
+func indexRunes(s, sub []rune) int {
+	if len(sub) == 0 {
+		return -1
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
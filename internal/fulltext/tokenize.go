@@ -0,0 +1,58 @@
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into index terms. Runs of letters and digits become
+// lower-case words; runs of CJK characters, which are written without spaces,
+// become overlapping bigrams (a lone character is kept as a unigram), so that
+// "冪等性キー" matches "冪等性" without a dictionary.
+func Tokenize(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	var cjk []rune
+
+	flushWord := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+		case 1:
+			tokens = append(tokens, string(cjk))
+		default:
+			for i := 0; i+1 < len(cjk); i++ {
+				tokens = append(tokens, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+// isCJK reports whether r belongs to a script written without word separators.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		r == 'ー' // katakana prolonged sound mark is in Common
+}
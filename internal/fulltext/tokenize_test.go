@@ -0,0 +1,27 @@
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"words are lower-cased", "Idempotency Keys, HTTP/2!", []string{"idempotency", "keys", "http", "2"}},
+		{"cjk runs become bigrams", "冪等性キー", []string{"冪等", "等性", "性キ", "キー"}},
+		{"a lone cjk character is kept", "鍵", []string{"鍵"}},
+		{"latin and cjk runs are split", "Kafkaのパーティション", []string{"kafka", "のパ", "パー", "ーテ", "ティ", "ィシ", "ショ", "ョン"}},
+		{"punctuation separates cjk runs", "分散、合意", []string{"分散", "合意"}},
+		{"empty", "  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Tokenize(tt.text))
+		})
+	}
+}
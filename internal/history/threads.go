@@ -0,0 +1,144 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThreadManager は O'Reilly Answers の会話スレッドを管理する
+// 調査履歴と同じく1つの JSON ファイルに保存し、上限を超えると更新が古いスレッドから削除する
+type ThreadManager struct {
+	mu         sync.RWMutex
+	filePath   string
+	maxThreads int
+	data       *threadsData
+}
+
+// NewThreadManager は新しいThreadManagerを作成する
+func NewThreadManager(filePath string, maxThreads int) *ThreadManager {
+	return &ThreadManager{filePath: filePath, maxThreads: maxThreads}
+}
+
+// Load はファイルからスレッドを読み込む
+func (m *ThreadManager) Load() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := os.ReadFile(m.filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			m.data = newThreadsData()
+			return nil
+		}
+		return fmt.Errorf("failed to read answer threads file: %w", err)
+	}
+
+	var d threadsData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("failed to unmarshal answer threads: %w", err)
+	}
+	m.data = &d
+	return nil
+}
+
+// Save はスレッドをファイルに保存する
+func (m *ThreadManager) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.data == nil {
+		return nil
+	}
+	m.data.LastUpdated = time.Now()
+
+	data, err := json.MarshalIndent(m.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal answer threads: %w", err)
+	}
+	if err := os.WriteFile(m.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write answer threads file: %w", err)
+	}
+	return nil
+}
+
+// AppendTurn はスレッドに質疑を追加し、スレッドIDを返す
+// threadID が空の場合は新しいスレッドを作成し、存在しないIDの場合はそのIDでスレッドを作成する
+// スレッドに同じ QuestionID のターンがある場合は追加せずに置き換える (回答待ちターンの完了)
+func (m *ThreadManager) AppendTurn(threadID string, turn Turn) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.data == nil {
+		m.data = newThreadsData()
+	}
+	if threadID == "" {
+		threadID = NewThreadID()
+	}
+	if turn.Timestamp.IsZero() {
+		turn.Timestamp = time.Now()
+	}
+
+	thread := Thread{ID: threadID, CreatedAt: turn.Timestamp}
+	for i, t := range m.data.Threads {
+		if t.ID == threadID {
+			thread = t
+			// 更新したスレッドを末尾に移し、削除対象を更新の古い順に保つ
+			m.data.Threads = append(m.data.Threads[:i], m.data.Threads[i+1:]...)
+			break
+		}
+	}
+	thread.UpdatedAt = turn.Timestamp
+	if i := slices.IndexFunc(thread.Turns, func(t Turn) bool {
+		return turn.QuestionID != "" && t.QuestionID == turn.QuestionID
+	}); i >= 0 {
+		turn.Timestamp = thread.Turns[i].Timestamp // 質問した時刻を保つ
+		thread.Turns[i] = turn
+	} else {
+		thread.Turns = append(thread.Turns, turn)
+	}
+	m.data.Threads = append(m.data.Threads, thread)
+
+	if over := len(m.data.Threads) - m.maxThreads; m.maxThreads > 0 && over > 0 {
+		m.data.Threads = m.data.Threads[over:]
+	}
+	return threadID
+}
+
+// GetThread は特定のIDのスレッドを取得する
+func (m *ThreadManager) GetThread(id string) *Thread {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.data == nil {
+		return nil
+	}
+	for i := range m.data.Threads {
+		if m.data.Threads[i].ID == id {
+			thread := m.data.Threads[i]
+			thread.Turns = append([]Turn(nil), thread.Turns...)
+			return &thread
+		}
+	}
+	return nil
+}
+
+// NewThreadID は新しいスレッドIDを生成する
+func NewThreadID() string {
+	return "thr_" + uuid.New().String()[:8]
+}
+
+// newThreadsData は空の threadsData を初期化して返す
+func newThreadsData() *threadsData {
+	return &threadsData{
+		Version:     1,
+		LastUpdated: time.Now(),
+		Threads:     []Thread{},
+	}
+}
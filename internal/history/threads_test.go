@@ -0,0 +1,106 @@
+package history
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThreadManager_AppendTurn(t *testing.T) {
+	m := NewThreadManager(filepath.Join(t.TempDir(), "threads.json"), 10)
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	id := m.AppendTurn("", Turn{QuestionID: "q1", Question: "What is Raft?", Answer: "A consensus algorithm."})
+	if !strings.HasPrefix(id, "thr_") {
+		t.Errorf("expected generated thread ID, got %q", id)
+	}
+	if got := m.AppendTurn(id, Turn{QuestionID: "q2", Question: "How are leaders elected?"}); got != id {
+		t.Errorf("expected follow-up in thread %q, got %q", id, got)
+	}
+
+	thread := m.GetThread(id)
+	if thread == nil {
+		t.Fatal("thread not found")
+	}
+	if len(thread.Turns) != 2 || thread.Turns[1].QuestionID != "q2" {
+		t.Errorf("unexpected turns: %+v", thread.Turns)
+	}
+	if thread.Turns[0].Timestamp.IsZero() || !thread.UpdatedAt.Equal(thread.Turns[1].Timestamp) {
+		t.Errorf("timestamps not set: %+v", thread)
+	}
+	if m.GetThread("thr_missing") != nil {
+		t.Error("expected nil for unknown thread")
+	}
+}
+
+func TestThreadManager_AppendTurnWithNewID(t *testing.T) {
+	m := NewThreadManager(filepath.Join(t.TempDir(), "threads.json"), 10)
+
+	if got := m.AppendTurn("thr_fixed", Turn{QuestionID: "q1"}); got != "thr_fixed" {
+		t.Errorf("expected the given ID to be used, got %q", got)
+	}
+	if m.GetThread("thr_fixed") == nil {
+		t.Error("thread not created")
+	}
+}
+
+func TestThreadManager_PrunesLeastRecentlyUpdated(t *testing.T) {
+	m := NewThreadManager(filepath.Join(t.TempDir(), "threads.json"), 2)
+
+	first := m.AppendTurn("", Turn{QuestionID: "q1"})
+	second := m.AppendTurn("", Turn{QuestionID: "q2"})
+	m.AppendTurn(first, Turn{QuestionID: "q3"}) // first becomes the most recent
+	third := m.AppendTurn("", Turn{QuestionID: "q4"})
+
+	if m.GetThread(second) != nil {
+		t.Error("expected the least recently updated thread to be pruned")
+	}
+	if m.GetThread(first) == nil || m.GetThread(third) == nil {
+		t.Error("expected recently updated threads to be kept")
+	}
+}
+
+func TestThreadManager_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "threads.json")
+	m := NewThreadManager(path, 10)
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	id := m.AppendTurn("", Turn{
+		QuestionID: "q1",
+		Question:   "What is Raft?",
+		Sources:    []ThreadSource{{Title: "Designing Data-Intensive Applications", URL: "https://learning.oreilly.com/library/view/-/9781449373320/"}},
+	})
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded := NewThreadManager(path, 10)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	thread := reloaded.GetThread(id)
+	if thread == nil {
+		t.Fatal("thread not found after reload")
+	}
+	if len(thread.Turns) != 1 || len(thread.Turns[0].Sources) != 1 {
+		t.Errorf("unexpected thread after reload: %+v", thread)
+	}
+}
+
+func TestThreadManager_AppendTurnReplacesPendingTurn(t *testing.T) {
+	m := NewThreadManager(filepath.Join(t.TempDir(), "threads.json"), 10)
+
+	id := m.AppendTurn("", Turn{QuestionID: "q1", Question: "What is Raft?", Pending: true})
+	m.AppendTurn(id, Turn{QuestionID: "q1", Question: "What is Raft?", Answer: "A consensus algorithm."})
+
+	thread := m.GetThread(id)
+	if thread == nil || len(thread.Turns) != 1 {
+		t.Fatalf("expected the pending turn to be replaced, got %+v", thread)
+	}
+	if thread.Turns[0].Pending || thread.Turns[0].Answer != "A consensus algorithm." {
+		t.Errorf("unexpected turn: %+v", thread.Turns[0])
+	}
+}
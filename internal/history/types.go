@@ -51,6 +51,9 @@ type ResultSummary struct {
 	AnswerPreview string `json:"answer_preview,omitempty"`
 	SourcesCount  int    `json:"sources_count,omitempty"`
 	FollowupCount int    `json:"followup_count,omitempty"`
+
+	// Summary は MCP サンプリングで生成した要約 (summarize 指定時のみ)
+	Summary string `json:"summary,omitempty"`
 }
 
 // TopResultSummary は検索結果のトップ結果サマリー
@@ -59,3 +62,42 @@ type TopResultSummary struct {
 	Author    string `json:"author,omitempty"`
 	ProductID string `json:"product_id,omitempty"`
 }
+
+// threadsData は O'Reilly Answers の会話スレッド全体を保持する構造体
+type threadsData struct {
+	Version     int       `json:"version"`
+	LastUpdated time.Time `json:"last_updated"`
+	Threads     []Thread  `json:"threads"`
+}
+
+// Thread は O'Reilly Answers との一連の質疑 (会話スレッド)
+type Thread struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Turns     []Turn    `json:"turns"`
+}
+
+// Turn はスレッド内の1回の質問と回答
+type Turn struct {
+	QuestionID string    `json:"question_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Question   string    `json:"question"`
+	// SubmittedQuestion は前の質疑を文脈として添えて実際に送信した質問 (フォローアップ時のみ)
+	SubmittedQuestion string         `json:"submitted_question,omitempty"`
+	Answer            string         `json:"answer"`
+	Sources           []ThreadSource `json:"sources"`
+	FollowupQuestions []string       `json:"followup_questions,omitempty"`
+	// Pending は非同期で送信した質問の回答待ち (回答が届くと同じ QuestionID のターンで置き換える)
+	Pending bool `json:"pending,omitempty"`
+	// Error は非同期の回答を取得できなかった理由
+	Error string `json:"error,omitempty"`
+}
+
+// ThreadSource は回答の出典
+type ThreadSource struct {
+	Title   string   `json:"title"`
+	URL     string   `json:"url"`
+	Authors []string `json:"authors,omitempty"`
+	Excerpt string   `json:"excerpt,omitempty"`
+}
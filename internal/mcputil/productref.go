@@ -0,0 +1,140 @@
+package mcputil
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ResolveProductRef normalizes a link or identifier naming O'Reilly content:
+// an O'Reilly web URL, an oreilly:// resource URI, an OURN (urn:orm:book:...),
+// an ISBN-10 or ISBN-13 with or without dashes, or a bare product ID.
+// ISBN-10 product IDs are converted to ISBN-13. ok is false when input is none of these.
+func ResolveProductRef(input string) (WebResource, bool) {
+	input = strings.TrimSpace(input)
+	var r WebResource
+	switch {
+	case input == "":
+		return WebResource{}, false
+	case strings.HasPrefix(input, "oreilly://"):
+		var ok bool
+		if r, ok = resolveResourceURI(input); !ok {
+			return WebResource{}, false
+		}
+	case strings.HasPrefix(input, "urn:"):
+		contentType, id, ok := parseOURN(input)
+		if !ok {
+			return WebResource{}, false
+		}
+		r = WebResource{ProductID: id, ContentType: contentType}
+	case strings.Contains(input, "/"):
+		if !strings.Contains(input, "://") && !strings.HasPrefix(input, "/") {
+			input = "https://" + input // pasted without a scheme
+		}
+		var ok bool
+		if r, ok = ResolveWebURL(input); !ok {
+			return WebResource{}, false
+		}
+	default:
+		if isbn, ok := NormalizeISBN(input); ok {
+			return WebResource{ProductID: isbn, ContentType: WebContentBook}, true
+		}
+		if !isProductID(input) {
+			return WebResource{}, false
+		}
+		r = WebResource{ProductID: input}
+	}
+	if isbn, ok := NormalizeISBN(r.ProductID); ok {
+		r.ProductID = isbn
+	}
+	return r, true
+}
+
+// resolveResourceURI reads the product and chapter of an oreilly://book-* URI.
+func resolveResourceURI(uri string) (WebResource, bool) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return WebResource{}, false
+	}
+	switch u.Host {
+	case "book-details", "book-toc", "book-search", "book-image", "book-citation":
+		productID, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+		if productID == "" {
+			return WebResource{}, false
+		}
+		return WebResource{ProductID: productID, ContentType: WebContentBook}, true
+	case "book-chapter", "book-chapter-markdown":
+		productID, chapterName, _ := ExtractChapterSubresourceFromURI(uri)
+		if productID == "" {
+			return WebResource{}, false
+		}
+		return WebResource{ProductID: productID, ContentType: WebContentBook, ChapterName: chapterName}, true
+	}
+	return WebResource{}, false
+}
+
+// isProductID reports whether s can be a bare O'Reilly product ID.
+func isProductID(s string) bool {
+	for _, r := range s {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// NormalizeISBN returns the ISBN-13 of an ISBN-10 or ISBN-13 written with or
+// without dashes or spaces. ok is false when s is not a valid ISBN.
+func NormalizeISBN(s string) (string, bool) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(s))
+
+	switch len(digits) {
+	case 10:
+		sum := 0
+		for i, r := range digits {
+			d := int(r - '0')
+			if r == 'X' && i == 9 {
+				d = 10
+			} else if r < '0' || r > '9' {
+				return "", false
+			}
+			sum += (10 - i) * d
+		}
+		if sum%11 != 0 {
+			return "", false
+		}
+		isbn := "978" + digits[:9]
+		return isbn + string(rune('0'+isbn13CheckDigit(isbn))), true
+	case 13:
+		for _, r := range digits {
+			if r < '0' || r > '9' {
+				return "", false
+			}
+		}
+		if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return "", false
+		}
+		if isbn13CheckDigit(digits[:12]) != int(digits[12]-'0') {
+			return "", false
+		}
+		return digits, true
+	}
+	return "", false
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits of an ISBN-13.
+func isbn13CheckDigit(first12 string) int {
+	sum := 0
+	for i, r := range first12 {
+		d := int(r - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
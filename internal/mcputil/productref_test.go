@@ -0,0 +1,66 @@
+package mcputil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"ISBN-10 with dashes", "1-4493-7332-1", "9781449373320", true},
+		{"ISBN-10 with X check digit", "0-8044-2957-X", "9780804429573", true},
+		{"ISBN-10 lowercase x", "080442957x", "9780804429573", true},
+		{"ISBN-13 with dashes", "978-1-4493-7332-0", "9781449373320", true},
+		{"ISBN-13 with spaces", "978 1 4493 7332 0", "9781449373320", true},
+		{"ISBN-10 bad checksum", "1449373322", "", false},
+		{"ISBN-13 bad checksum", "9781449373321", "", false},
+		{"13 digits without ISBN prefix", "0636920034254", "", false},
+		{"letters", "97814493733ab", "", false},
+		{"too short", "12345", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeISBN(tt.input)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveProductRef(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   WebResource
+		wantOK bool
+	}{
+		{"ISBN-10", "1-4493-7332-1", WebResource{ProductID: "9781449373320", ContentType: WebContentBook}, true},
+		{"OURN", "urn:orm:book:9781491903063", WebResource{ProductID: "9781491903063", ContentType: WebContentBook}, true},
+		{"video OURN", "urn:orm:video:9780135918845", WebResource{ProductID: "9780135918845", ContentType: WebContentVideo}, true},
+		{"web URL", "https://learning.oreilly.com/library/view/ddia/9781491903063/ch05.html", WebResource{ProductID: "9781491903063", ContentType: WebContentBook, ChapterName: "ch05.html"}, true},
+		{"web URL without scheme", "learning.oreilly.com/library/view/ddia/9781491903063/", WebResource{ProductID: "9781491903063"}, true},
+		{"web URL with ISBN-10", "https://learning.oreilly.com/library/view/learning-python/0596007124/", WebResource{ProductID: "9780596007126"}, true},
+		{"details URI", "oreilly://book-details/9781491903063", WebResource{ProductID: "9781491903063", ContentType: WebContentBook}, true},
+		{"chapter URI", "oreilly://book-chapter/9781491903063/ch05.html", WebResource{ProductID: "9781491903063", ContentType: WebContentBook, ChapterName: "ch05.html"}, true},
+		{"bare product ID", " 0636920034254 ", WebResource{ProductID: "0636920034254"}, true},
+		{"citation URI", "oreilly://book-citation/9781491903063?style=bibtex", WebResource{ProductID: "9781491903063", ContentType: WebContentBook}, true},
+		{"answer URI", "oreilly://answer/q-1", WebResource{}, false},
+		{"malformed OURN", "urn:isbn:9781491903063", WebResource{}, false},
+		{"title", "Designing Data-Intensive Applications", WebResource{}, false},
+		{"other site", "https://example.com/book/9781491903063", WebResource{}, false},
+		{"empty", "  ", WebResource{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveProductRef(tt.input)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
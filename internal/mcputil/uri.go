@@ -55,3 +55,84 @@ func ExtractProductIDAndChapterFromURI(uri string) (string, string) {
 func ExtractQuestionIDFromURI(uri string) string {
 	return ExtractProductIDFromURI(uri)
 }
+
+// ExtractQueryParam returns the value of the query parameter key in uri,
+// e.g. "markdown" for key "format" in "oreilly://book-chapter/123/ch01?format=markdown".
+func ExtractQueryParam(uri, key string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(key)
+}
+
+// BookDetailsURI builds "oreilly://book-details/{product_id}".
+func BookDetailsURI(productID string) string {
+	return "oreilly://book-details/" + url.PathEscape(productID)
+}
+
+// BookTOCURI builds "oreilly://book-toc/{product_id}".
+func BookTOCURI(productID string) string {
+	return "oreilly://book-toc/" + url.PathEscape(productID)
+}
+
+// BookCitationURI builds "oreilly://book-citation/{product_id}?style={style}".
+// An empty style leaves the query out.
+func BookCitationURI(productID, style string) string {
+	uri := "oreilly://book-citation/" + url.PathEscape(productID)
+	if style != "" {
+		uri += "?style=" + url.QueryEscape(style)
+	}
+	return uri
+}
+
+// BookChapterURI builds "oreilly://book-chapter/{product_id}/{chapter_name}",
+// escaping each segment so that chapter names containing "/" survive a round trip.
+func BookChapterURI(productID, chapterName string) string {
+	return "oreilly://book-chapter/" + url.PathEscape(productID) + "/" + url.PathEscape(chapterName)
+}
+
+// ExtractChapterSubresourceFromURI splits URIs like
+// "oreilly://book-chapter/{product_id}/{chapter_name}/section/{n}" into the
+// product ID, chapter name and the remaining unescaped path segments.
+func ExtractChapterSubresourceFromURI(uri string) (string, string, []string) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", "", nil
+	}
+	rawPath := u.RawPath
+	if rawPath == "" {
+		rawPath = u.Path
+	}
+	parts := strings.Split(strings.TrimPrefix(rawPath, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", nil
+	}
+	segments := make([]string, len(parts))
+	for i, p := range parts {
+		if segments[i], err = url.PathUnescape(p); err != nil {
+			return "", "", nil
+		}
+	}
+	return segments[0], segments[1], segments[2:]
+}
+
+// BookImageURI builds the oreilly://book-image URI of a book-relative image path.
+// Each path segment is escaped so that the path keeps its "/" separators.
+func BookImageURI(productID, imagePath string) string {
+	segments := strings.Split(imagePath, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return "oreilly://book-image/" + url.PathEscape(productID) + "/" + strings.Join(segments, "/")
+}
+
+// ExtractBookImageFromURI extracts the product ID and the unescaped image path
+// from URIs like "oreilly://book-image/{product_id}/{path}".
+func ExtractBookImageFromURI(uri string) (string, string) {
+	productID, first, rest := ExtractChapterSubresourceFromURI(uri)
+	if productID == "" {
+		return "", ""
+	}
+	return productID, strings.Join(append([]string{first}, rest...), "/")
+}
@@ -0,0 +1,118 @@
+package mcputil
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Content types inferred from O'Reilly web URLs.
+const (
+	WebContentBook   = "book"
+	WebContentVideo  = "video"
+	WebContentCourse = "course"
+)
+
+// WebResource is the server resource an O'Reilly web URL points to.
+type WebResource struct {
+	ProductID   string
+	ContentType string // WebContentBook, WebContentVideo or WebContentCourse; "" when the URL does not tell
+	ChapterName string // chapter file inside a book; "" for the whole product
+}
+
+// IsBook reports whether the resource can be read through oreilly://book-*
+// resources: a book, or a /library/view page whose content type is unknown.
+func (r WebResource) IsBook() bool {
+	return r.ContentType == "" || r.ContentType == WebContentBook
+}
+
+// URI returns "oreilly://book-chapter/{product_id}/{chapter_name}" when the URL
+// points inside a book and "oreilly://book-details/{product_id}" for a book.
+// It returns "" for videos and courses, which no server resource serves.
+func (r WebResource) URI() string {
+	switch {
+	case !r.IsBook():
+		return ""
+	case r.ChapterName != "":
+		return BookChapterURI(r.ProductID, r.ChapterName)
+	}
+	return BookDetailsURI(r.ProductID)
+}
+
+// LibraryURL returns the learning.oreilly.com page of a book. The site accepts
+// "-" in place of the title slug.
+func LibraryURL(productID string) string {
+	return "https://learning.oreilly.com/library/view/-/" + url.PathEscape(productID) + "/"
+}
+
+// ResolveWebURL maps an O'Reilly Learning URL onto server resources. It accepts
+//
+//	/library/view/{slug}/{id}/[{chapter}.html]
+//	/api/v2/epubs/urn:orm:{type}:{id}/[files/{chapter}.html]
+//	/videos/{slug}/{id}/...
+//	/course/{slug}/{id}/...
+//
+// on learning.oreilly.com or www.oreilly.com, or as a path without a host.
+// ok is false for any other URL.
+func ResolveWebURL(rawURL string) (WebResource, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !isOReillyHost(u.Host) {
+		return WebResource{}, false
+	}
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	switch {
+	case len(segments) >= 4 && segments[0] == "library" && segments[1] == "view":
+		r := WebResource{ProductID: segments[3]}
+		if chapter := strings.Join(segments[4:], "/"); isChapterFile(chapter) {
+			r.ContentType = WebContentBook
+			r.ChapterName = chapter
+		}
+		return r, true
+	case len(segments) >= 4 && segments[0] == "api" && segments[1] == "v2" && segments[2] == "epubs":
+		contentType, id, ok := parseOURN(segments[3])
+		if !ok {
+			return WebResource{}, false
+		}
+		r := WebResource{ProductID: id, ContentType: contentType}
+		if len(segments) > 5 && segments[4] == "files" {
+			if chapter := strings.Join(segments[5:], "/"); isChapterFile(chapter) {
+				r.ChapterName = chapter
+			}
+		}
+		return r, true
+	case len(segments) >= 3 && segments[0] == "videos":
+		return WebResource{ProductID: segments[2], ContentType: WebContentVideo}, true
+	case len(segments) >= 3 && segments[0] == "course":
+		return WebResource{ProductID: segments[2], ContentType: WebContentCourse}, true
+	}
+	return WebResource{}, false
+}
+
+// isOReillyHost reports whether host serves O'Reilly Learning pages.
+// An empty host accepts site-relative paths.
+func isOReillyHost(host string) bool {
+	switch strings.ToLower(host) {
+	case "", "learning.oreilly.com", "www.oreilly.com", "oreilly.com":
+		return true
+	}
+	return false
+}
+
+// isChapterFile reports whether p names a chapter document of a book.
+func isChapterFile(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".html", ".xhtml", ".htm":
+		return true
+	}
+	return false
+}
+
+// parseOURN splits "urn:orm:{type}:{id}" into its content type and ID.
+func parseOURN(ourn string) (contentType, id string, ok bool) {
+	parts := strings.Split(ourn, ":")
+	if len(parts) != 4 || parts[0] != "urn" || parts[1] != "orm" || parts[2] == "" || parts[3] == "" {
+		return "", "", false
+	}
+	return parts[2], parts[3], true
+}
@@ -0,0 +1,89 @@
+package mcputil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveWebURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		want   WebResource
+		wantOK bool
+		uri    string
+	}{
+		{
+			name:   "book chapter",
+			url:    "https://learning.oreilly.com/library/view/designing-data-intensive-applications/9781491903063/ch05.html#sec_replication",
+			want:   WebResource{ProductID: "9781491903063", ContentType: WebContentBook, ChapterName: "ch05.html"},
+			wantOK: true,
+			uri:    "oreilly://book-chapter/9781491903063/ch05.html",
+		},
+		{
+			name:   "book landing page",
+			url:    "https://learning.oreilly.com/library/view/designing-data-intensive-applications/9781491903063/",
+			want:   WebResource{ProductID: "9781491903063"},
+			wantOK: true,
+			uri:    "oreilly://book-details/9781491903063",
+		},
+		{
+			name:   "chapter in a subdirectory",
+			url:    "https://learning.oreilly.com/library/view/-/9781098131814/text/ch01.xhtml",
+			want:   WebResource{ProductID: "9781098131814", ContentType: WebContentBook, ChapterName: "text/ch01.xhtml"},
+			wantOK: true,
+			uri:    "oreilly://book-chapter/9781098131814/text%2Fch01.xhtml",
+		},
+		{
+			name:   "epub API file",
+			url:    "https://learning.oreilly.com/api/v2/epubs/urn:orm:book:9781098131814/files/ch02.html",
+			want:   WebResource{ProductID: "9781098131814", ContentType: WebContentBook, ChapterName: "ch02.html"},
+			wantOK: true,
+			uri:    "oreilly://book-chapter/9781098131814/ch02.html",
+		},
+		{
+			name:   "epub API root",
+			url:    "/api/v2/epubs/urn:orm:book:9781098131814/",
+			want:   WebResource{ProductID: "9781098131814", ContentType: WebContentBook},
+			wantOK: true,
+			uri:    "oreilly://book-details/9781098131814",
+		},
+		{
+			name:   "video clip",
+			url:    "https://learning.oreilly.com/videos/kubernetes-fundamentals/9780135918845/9780135918845-KF1_01_00/",
+			want:   WebResource{ProductID: "9780135918845", ContentType: WebContentVideo},
+			wantOK: true,
+			uri:    "",
+		},
+		{
+			name:   "course",
+			url:    "https://www.oreilly.com/course/python-fundamentals/9780135917411/",
+			want:   WebResource{ProductID: "9780135917411", ContentType: WebContentCourse},
+			wantOK: true,
+			uri:    "",
+		},
+		{
+			name:   "video OURN",
+			url:    "/api/v2/epubs/urn:orm:video:9780135918845/",
+			want:   WebResource{ProductID: "9780135918845", ContentType: WebContentVideo},
+			wantOK: true,
+			uri:    "",
+		},
+		{name: "other host", url: "https://example.com/library/view/x/9781491903063/ch01.html"},
+		{name: "unknown path", url: "https://learning.oreilly.com/playlists/abc/"},
+		{name: "malformed ourn", url: "https://learning.oreilly.com/api/v2/epubs/9781098131814/files/ch02.html"},
+		{name: "empty", url: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveWebURL(tt.url)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+			if tt.wantOK {
+				assert.Equal(t, tt.uri, got.URI())
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ const (
 )
 
 // Manager handles MCP Sampling requests to generate summaries.
+// A nil *Manager never samples.
 type Manager struct {
 	config *config.Config
 }
@@ -47,7 +48,7 @@ func (sm *Manager) CanSample(session *mcp.ServerSession) bool {
 
 // canProceed checks if sampling is enabled and session is available.
 func (sm *Manager) canProceed(session *mcp.ServerSession) bool {
-	if !sm.config.Sampling.Enabled {
+	if sm == nil || !sm.config.Sampling.Enabled {
 		slog.Debug("Sampling is disabled")
 		return false
 	}
@@ -142,6 +143,13 @@ Question: %s
 Answer: %s
 
 Provide a 2-3 sentence summary of the key points.`, question, answer)
+	if len(sources) > 0 {
+		sourcesJSON, err := json.Marshal(sources)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal sources: %w", err)
+		}
+		userPrompt += "\nMention the most relevant of these sources by title.\n\nSources:\n" + string(sourcesJSON)
+	}
 
 	params := &mcp.CreateMessageParams{
 		Messages: []*mcp.SamplingMessage{
@@ -12,3 +12,8 @@ func TestManager_CanSample_NilSession(t *testing.T) {
 	result := sm.CanSample(nil)
 	assert.False(t, result, "CanSample should return false when session is nil")
 }
+
+func TestManager_CanSample_NilManager(t *testing.T) {
+	var sm *Manager
+	assert.False(t, sm.CanSample(nil), "a nil Manager never samples")
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// AnswerSourceLink maps an Answers source or related resource onto a server resource.
+type AnswerSourceLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`           // learning.oreilly.com URL as returned by Answers
+	URI   string `json:"uri,omitempty"` // oreilly://book-chapter/... inside a book, oreilly://book-details/... for a book; none for videos and courses
+}
+
+// answerSourceLinks resolves the sources, then the related resources, of an
+// answer to resource URIs. URLs that do not point to O'Reilly content are
+// skipped, and each book page or other product is listed once. Videos and
+// courses keep only their web URL.
+func answerSourceLinks(data browser.AnswerData) []AnswerSourceLink {
+	links := []AnswerSourceLink{}
+	seen := map[string]bool{}
+	add := func(title, webURL string) {
+		r, ok := mcputil.ResolveWebURL(webURL)
+		if !ok {
+			return
+		}
+		uri := r.URI()
+		key := uri
+		if key == "" {
+			key = r.ContentType + ":" + r.ProductID
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		links = append(links, AnswerSourceLink{Title: title, URL: webURL, URI: uri})
+	}
+	for _, src := range data.Sources {
+		add(src.Title, src.URL)
+	}
+	for _, rel := range data.RelatedResources {
+		add(rel.Title, rel.URL)
+	}
+	return links
+}
+
+// sourceResourceLinks returns a ResourceLink content entry per source link
+// that has a server resource.
+func sourceResourceLinks(links []AnswerSourceLink) []mcp.Content {
+	content := make([]mcp.Content, 0, len(links))
+	for _, link := range links {
+		if link.URI == "" {
+			continue
+		}
+		name := link.Title
+		if name == "" {
+			name = link.URI
+		}
+		content = append(content, &mcp.ResourceLink{
+			URI:      link.URI,
+			Name:     name,
+			MIMEType: "application/json",
+		})
+	}
+	return content
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+func linkedAnswer() *browser.AnswerResponse {
+	return &browser.AnswerResponse{
+		QuestionID: "q-links",
+		IsFinished: true,
+		MisoResponse: browser.MisoResponse{Data: browser.AnswerData{
+			Answer: "Use leader election.",
+			Sources: []browser.AnswerSource{
+				{Title: "Replication", URL: "https://learning.oreilly.com/library/view/ddia/9781491903063/ch05.html#sec"},
+				{Title: "Replication again", URL: "https://learning.oreilly.com/library/view/ddia/9781491903063/ch05.html"},
+				{Title: "Blog post", URL: "https://example.com/raft"},
+			},
+			RelatedResources: []browser.RelatedResource{
+				{Title: "Kubernetes Fundamentals", URL: "https://learning.oreilly.com/videos/kubernetes-fundamentals/9780135918845/"},
+			},
+		}},
+	}
+}
+
+func TestAnswerSourceLinks(t *testing.T) {
+	links := answerSourceLinks(linkedAnswer().MisoResponse.Data)
+	assert.Equal(t, []AnswerSourceLink{
+		{Title: "Replication", URL: "https://learning.oreilly.com/library/view/ddia/9781491903063/ch05.html#sec", URI: "oreilly://book-chapter/9781491903063/ch05.html"},
+		{Title: "Kubernetes Fundamentals", URL: "https://learning.oreilly.com/videos/kubernetes-fundamentals/9780135918845/", URI: ""},
+	}, links, "duplicates and non-O'Reilly URLs are skipped; videos have no resource URI")
+}
+
+func TestAskQuestionHandler_SourceResourceLinks(t *testing.T) {
+	for _, format := range []string{"", "markdown"} {
+		t.Run("format="+format, func(t *testing.T) {
+			srv := newTestServer(t, &mockBrowserClient{askAnswer: linkedAnswer()})
+			session := connectTestSession(t, srv, nil)
+
+			res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+				Name:      "oreilly_ask_question",
+				Arguments: map[string]any{"question": "How is a leader elected?", "format": format},
+			})
+			require.NoError(t, err)
+			require.False(t, res.IsError)
+			require.Len(t, res.Content, 2, "the answer followed by one link per source with a server resource")
+
+			text, ok := res.Content[0].(*mcp.TextContent)
+			require.True(t, ok)
+			assert.Contains(t, text.Text, "Use leader election.")
+
+			link, ok := res.Content[1].(*mcp.ResourceLink)
+			require.True(t, ok)
+			assert.Equal(t, "oreilly://book-chapter/9781491903063/ch05.html", link.URI)
+			assert.Equal(t, "Replication", link.Name)
+
+			structured, ok := res.StructuredContent.(map[string]any)
+			require.True(t, ok)
+			assert.Len(t, structured["source_links"], 2)
+		})
+	}
+}
+
+func TestAnswerResource_SourceLinks(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{askAnswer: linkedAnswer()})
+	session := connectTestSession(t, srv, nil)
+
+	var got struct {
+		SourceLinks []AnswerSourceLink `json:"source_links"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://answer/q-links").Text), &got))
+	require.Len(t, got.SourceLinks, 2)
+	assert.Equal(t, "oreilly://book-chapter/9781491903063/ch05.html", got.SourceLinks[0].URI)
+}
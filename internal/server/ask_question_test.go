@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+func TestAskQuestionHandler_Scope(t *testing.T) {
+	mock := &mockBrowserClient{
+		submitResp: &browser.QuestionResponse{QuestionID: "q-scoped"},
+		askAnswer: &browser.AnswerResponse{
+			QuestionID:   "q-scoped",
+			IsFinished:   true,
+			MisoResponse: browser.MisoResponse{Data: browser.AnswerData{Answer: "Use leader election."}},
+		},
+	}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+	want := browser.QuestionOptions{
+		ProductIDs:      []string{"9781449373320"},
+		ContentTypes:    []string{"book"},
+		Publishers:      []string{"O'Reilly Media, Inc."},
+		SnippetLength:   1000,
+		HighlightLength: 300,
+	}
+
+	for _, async := range []bool{false, true} {
+		mock.questionOpts = browser.QuestionOptions{}
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name: "oreilly_ask_question",
+			Arguments: map[string]any{
+				"question":         "How does Raft elect a leader?",
+				"async":            async,
+				"product_ids":      []string{"9781449373320"},
+				"content_types":    []string{"book"},
+				"publishers":       []string{"O'Reilly Media, Inc."},
+				"snippet_length":   1000,
+				"highlight_length": 300,
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, res.IsError, "async=%v", async)
+		assert.Equal(t, want, mock.questionOpts, "async=%v", async)
+	}
+}
+
+func TestAskQuestionHandler_InvalidScope(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+	session := connectTestSession(t, srv, nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "What is Raft?", "content_types": []string{"podcast"}},
+	})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, `invalid content type "podcast"`)
+}
+
+func TestAskQuestionHandler_NegativeLength(t *testing.T) {
+	mock := &mockBrowserClient{}
+	srv := newTestServer(t, mock)
+
+	// Called directly, as a client skipping the input schema would
+	res, _, err := srv.AskQuestionHandler(context.Background(), &mcp.CallToolRequest{}, AskQuestionArgs{Question: "What is Raft?", SnippetLength: -1})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "snippet_length must not be negative")
+	assert.Empty(t, mock.questions, "the question is not sent")
+}
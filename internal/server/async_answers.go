@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// answerURIPrefix is the URI prefix of the oreilly://answer/{question_id} resource.
+const answerURIPrefix = "oreilly://answer/"
+
+// answerURI returns the answer resource URI for a question ID.
+func answerURI(questionID string) string {
+	return answerURIPrefix + url.PathEscape(questionID)
+}
+
+// SubscribeResourceHandler accepts resources/subscribe requests.
+// Only oreilly://answer/{question_id} resources change over time, so other URIs are rejected.
+func (s *Server) SubscribeResourceHandler(_ context.Context, req *mcp.SubscribeRequest) error {
+	if !strings.HasPrefix(req.Params.URI, answerURIPrefix) || mcputil.ExtractQuestionIDFromURI(req.Params.URI) == "" {
+		return fmt.Errorf("subscriptions are only supported for %s{question_id}", answerURIPrefix)
+	}
+	slog.Info("リソース購読を受け付けました", "uri", req.Params.URI)
+	return nil
+}
+
+// UnsubscribeResourceHandler accepts resources/unsubscribe requests.
+func (s *Server) UnsubscribeResourceHandler(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	slog.Info("リソース購読を解除しました", "uri", req.Params.URI)
+	return nil
+}
+
+// askQuestionAsync submits the question and returns immediately with the answer resource URI.
+// The answer is polled in the background and subscribers are notified when it finishes.
+// The question is recorded to its thread as a pending turn up front so that
+// follow-ups can refer to the thread before the answer arrives.
+func (s *Server) askQuestionAsync(ctx context.Context, args AskQuestionArgs, asked askedQuestion, maxWaitTime time.Duration, start time.Time) (*mcp.CallToolResult, *AskQuestionResult, error) {
+	client := s.getBrowserClient()
+	submitted, err := client.SubmitQuestion(ctx, asked.submitted, args.questionOptions())
+	if err != nil {
+		return newToolResultError(errH.Sanitize(err, "operation", "submit_question", "question", args.Question)), nil, nil
+	}
+
+	uri := answerURI(submitted.QuestionID)
+	slog.Info("質問を非同期で送信しました", "question_id", submitted.QuestionID, "uri", uri)
+	if threadID := s.recordPendingTurn(asked, submitted.QuestionID); threadID != "" {
+		asked.threadID = threadID
+	}
+	go s.pollAnswerInBackground(client, asked, submitted.QuestionID, maxWaitTime, start)
+
+	structured := &AskQuestionResult{
+		QuestionID:          submitted.QuestionID,
+		Question:            args.Question,
+		IsFinished:          false,
+		AnswerURI:           uri,
+		Sources:             []browser.AnswerSource{},
+		RelatedResources:    []browser.RelatedResource{},
+		AffiliationProducts: []browser.AffiliationProduct{},
+		FollowupQuestions:   []string{},
+		SourceLinks:         []AnswerSourceLink{},
+		CitationNote:        "IMPORTANT: When referencing this information, always cite the sources listed above with proper attribution to O'Reilly Media.",
+	}
+	if args.Summarize {
+		structured.SummaryNote = summaryAsyncNote
+	}
+	if asked.threadID != "" {
+		structured.ThreadID = asked.threadID
+		structured.ThreadURI = threadURI(asked.threadID)
+	}
+
+	text := fmt.Sprintf("Question submitted (question_id: %s). The answer is being generated in the background.\n"+
+		"Read %s to get the answer. Subscribe to it to receive notifications/resources/updated when it finishes.",
+		submitted.QuestionID, uri)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+			&mcp.ResourceLink{URI: uri, Name: "Answer: " + args.Question, MIMEType: "application/json"},
+		},
+	}, structured, nil
+}
+
+// pollAnswerInBackground waits for the answer on the server's background context,
+// records it to the research history and its thread and notifies resource subscribers.
+// When polling fails or times out, the failure is kept for the answer resource
+// and subscribers are notified all the same.
+func (s *Server) pollAnswerInBackground(client browser.Client, asked askedQuestion, questionID string, maxWaitTime time.Duration, start time.Time) {
+	defer s.notifyAnswerUpdated(questionID)
+
+	answer, err := client.WaitForAnswer(s.bgCtx, questionID, maxWaitTime, nil)
+	if err != nil {
+		s.answerFailures.Store(questionID, errH.Sanitize(err, "operation", "wait_for_answer", "question_id", questionID))
+		s.recordFailedTurn(asked, questionID, err)
+		return
+	}
+	slog.Info("非同期回答が完了しました", "question_id", questionID)
+	answered := *answer
+	answered.QuestionID = questionID // the polled ID, which matches the pending turn recorded on submit
+
+	s.recordQuestionHistory(asked.question, &answered, time.Since(start), "")
+	s.recordThreadTurn(asked, &answered)
+}
+
+// notifyAnswerUpdated sends notifications/resources/updated for the answer resource.
+func (s *Server) notifyAnswerUpdated(questionID string) {
+	uri := answerURI(questionID)
+	if err := s.server.ResourceUpdated(s.bgCtx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+		slog.Warn("リソース更新通知の送信に失敗しました", "uri", uri, "error", err)
+	}
+}
+
+// answerFailure returns why background polling for questionID failed, if it did.
+func (s *Server) answerFailure(questionID string) (string, bool) {
+	v, ok := s.answerFailures.Load(questionID)
+	if !ok {
+		return "", false
+	}
+	return v.(string), true
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/history"
+)
+
+func TestAnswerURI(t *testing.T) {
+	assert.Equal(t, "oreilly://answer/q-abc-123", answerURI("q-abc-123"))
+}
+
+func TestSubscribeResourceHandler(t *testing.T) {
+	srv := &Server{}
+	tests := []struct {
+		name    string
+		uri     string
+		wantErr bool
+	}{
+		{"answer URI", "oreilly://answer/q-1", false},
+		{"answer URI without id", "oreilly://answer/", true},
+		{"book URI", "oreilly://book-details/123", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := srv.SubscribeResourceHandler(context.Background(), &mcp.SubscribeRequest{
+				Params: &mcp.SubscribeParams{URI: tt.uri},
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAskQuestionHandler_AsyncNotifiesSubscribers(t *testing.T) {
+	mock := &mockBrowserClient{
+		submitResp: &browser.QuestionResponse{QuestionID: "q-async"},
+		askAnswer: &browser.AnswerResponse{
+			QuestionID:   "q-async",
+			IsFinished:   true,
+			MisoResponse: browser.MisoResponse{Data: browser.AnswerData{Answer: "done"}},
+		},
+	}
+	srv := newTestServer(t, mock)
+
+	updated := make(chan string, 1)
+	session := connectTestSession(t, srv, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+
+	ctx := context.Background()
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: "oreilly://answer/q-async"}))
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "What is Go?", "async": true},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok, "expected structured content map, got %T", res.StructuredContent)
+	assert.Equal(t, "q-async", structured["question_id"])
+	assert.Equal(t, "oreilly://answer/q-async", structured["answer_uri"])
+	assert.Equal(t, false, structured["is_finished"])
+
+	select {
+	case uri := <-updated:
+		assert.Equal(t, "oreilly://answer/q-async", uri)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected notifications/resources/updated for the answer URI")
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(srv.historyManager.SearchByType(history.EntryTypeQuestion)) == 1
+	}, time.Second, 10*time.Millisecond, "async answer should be recorded to history")
+}
+
+func TestAskQuestionHandler_AsyncFailureNotifiesSubscribers(t *testing.T) {
+	mock := &mockBrowserClient{
+		submitResp: &browser.QuestionResponse{QuestionID: "q-fail"},
+		askErr:     errors.New("timeout waiting for answer"),
+	}
+	srv := newTestServer(t, mock)
+
+	updated := make(chan string, 1)
+	session := connectTestSession(t, srv, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+
+	ctx := context.Background()
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: "oreilly://answer/q-fail"}))
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "What is Go?", "async": true},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	select {
+	case uri := <-updated:
+		assert.Equal(t, "oreilly://answer/q-fail", uri)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected notifications/resources/updated when polling fails")
+	}
+
+	read, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "oreilly://answer/q-fail"})
+	require.NoError(t, err)
+	require.Len(t, read.Contents, 1)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(read.Contents[0].Text), &got))
+	assert.Equal(t, "q-fail", got["question_id"])
+	assert.Equal(t, false, got["is_finished"])
+	assert.NotEmpty(t, got["error"], "the answer resource reports the polling failure")
+}
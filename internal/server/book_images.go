@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// imagesInline is the "images" query value that embeds chapter images in the read result.
+const imagesInline = "inline"
+
+// GetBookImageResource returns a book image, such as a figure, as a blob.
+func (s *Server) GetBookImageResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	productID, imagePath := mcputil.ExtractBookImageFromURI(req.Params.URI)
+	if productID == "" || imagePath == "" {
+		return paramErrorResult(req.Params.URI, "product_id or image path not found in URI"), nil
+	}
+	return s.readResource(ctx, req.Params.URI, func() (any, error) {
+		image, err := s.getBrowserClient().GetBookImage(ctx, productID, imagePath)
+		if errors.Is(err, browser.ErrNotImage) {
+			return nil, &resourceParamError{fmt.Sprintf("%s is not an image", imagePath)}
+		}
+		return image, err
+	}, func() (any, error) {
+		return s.contentStore.StaleBookImage(productID, imagePath)
+	}, encodeImageBlob, "get_book_image", "product_id", productID, "path", imagePath)
+}
+
+// encodeImageBlob renders a *browser.BookImage as a blob resource.
+func encodeImageBlob(uri string, data any) (*mcp.ReadResourceResult, error) {
+	image, ok := data.(*browser.BookImage)
+	if !ok || image == nil {
+		return nil, fmt.Errorf("unexpected image data type %T", data)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{imageContents(uri, image)},
+	}, nil
+}
+
+func imageContents(uri string, image *browser.BookImage) *mcp.ResourceContents {
+	contents := &mcp.ResourceContents{URI: uri, MIMEType: image.MIMEType, Blob: image.Data}
+	if len(image.Metadata) > 0 {
+		contents.Meta = mcp.Meta(image.Metadata)
+	}
+	return contents
+}
+
+// getBookImage fetches an image, falling back to the content store.
+func (s *Server) getBookImage(ctx context.Context, productID, imagePath string) (*browser.BookImage, error) {
+	if client := s.getBrowserClient(); client != nil {
+		image, err := client.GetBookImage(ctx, productID, imagePath)
+		if err == nil || ctx.Err() != nil {
+			return image, err
+		}
+		slog.Debug("画像の取得に失敗したため保存済みのものを探します", "product_id", productID, "path", imagePath, "error", err)
+	}
+	return s.contentStore.StaleBookImage(productID, imagePath)
+}
+
+// linkChapterImages sets the oreilly://book-image URI of every image in the
+// chapter that resolves to a file of the book.
+func linkChapterImages(chapter *browser.ChapterContentResponse) *browser.ChapterContentResponse {
+	if chapter == nil {
+		return nil
+	}
+	for i := range chapter.Content.Sections {
+		linkImages(chapter.Content.Sections[i].Content, chapter.BookID, chapter.SourceURL)
+	}
+	return chapter
+}
+
+func linkImages(content []any, productID, baseURL string) {
+	for i, elem := range content {
+		switch e := elem.(type) {
+		case htmlparse.ImageElement:
+			if imagePath := browser.ResolveBookFilePath(productID, baseURL, e.Src); imagePath != "" {
+				e.URI = mcputil.BookImageURI(productID, imagePath)
+				content[i] = e
+			}
+		case htmlparse.AdmonitionElement:
+			linkImages(e.Content, productID, baseURL)
+		}
+	}
+}
+
+// collectImages returns the linked images of sections in document order.
+func collectImages(sections []htmlparse.ContentSection) []htmlparse.ImageElement {
+	var images []htmlparse.ImageElement
+	var walk func([]any)
+	walk = func(content []any) {
+		for _, elem := range content {
+			switch e := elem.(type) {
+			case htmlparse.ImageElement:
+				if e.URI != "" {
+					images = append(images, e)
+				}
+			case htmlparse.AdmonitionElement:
+				walk(e.Content)
+			}
+		}
+	}
+	for _, section := range sections {
+		walk(section.Content)
+	}
+	return images
+}
+
+// withInlineImages wraps encode so that the images of the chapter or section
+// are appended to the result as blob contents. Images larger than the per-image
+// cap, or beyond the total cap, are left out and stay readable through their URI.
+func (s *Server) withInlineImages(ctx context.Context, encode resourceEncoder) resourceEncoder {
+	return func(uri string, data any) (*mcp.ReadResourceResult, error) {
+		result, err := encode(uri, data)
+		if err != nil {
+			return nil, err
+		}
+
+		var productID string
+		var sections []htmlparse.ContentSection
+		switch d := data.(type) {
+		case *browser.ChapterContentResponse:
+			productID, sections = d.BookID, d.Content.Sections
+		case *ChapterSectionResult:
+			productID, sections = d.BookID, []htmlparse.ContentSection{d.Section}
+		default:
+			return result, nil
+		}
+
+		maxBytes, remaining := s.config.Image.InlineMaxBytes, s.config.Image.InlineMaxTotalBytes
+		seen := map[string]bool{}
+		for _, img := range collectImages(sections) {
+			if seen[img.URI] {
+				continue
+			}
+			seen[img.URI] = true
+
+			_, imagePath := mcputil.ExtractBookImageFromURI(img.URI)
+			image, err := s.getBookImage(ctx, productID, imagePath)
+			if err != nil {
+				slog.Debug("埋め込み用の画像を取得できませんでした", "uri", img.URI, "error", err)
+				continue
+			}
+			if len(image.Data) > maxBytes || len(image.Data) > remaining {
+				slog.Debug("サイズ上限を超えるため画像を埋め込みません", "uri", img.URI, "size", len(image.Data))
+				continue
+			}
+			remaining -= len(image.Data)
+			result.Contents = append(result.Contents, imageContents(img.URI, image))
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+func newIllustratedChapter() *browser.ChapterContentResponse {
+	return &browser.ChapterContentResponse{
+		BookID:       "123",
+		ChapterName:  "ch01",
+		ChapterTitle: "Diagrams",
+		SourceURL:    "https://learning.oreilly.com/api/v2/epubs/urn:orm:book:123/files/ch01.html",
+		Content: htmlparse.ParsedChapterContent{
+			Sections: []htmlparse.ContentSection{{
+				Heading: htmlparse.ContentHeading{Level: 1, Text: "Diagrams"},
+				Content: []any{
+					htmlparse.ImageElement{Type: "image", Src: "assets/small.png", Alt: "Small"},
+					htmlparse.AdmonitionElement{Type: "admonition", Kind: "note", Content: []any{
+						htmlparse.ImageElement{Type: "image", Src: "assets/large.png", Alt: "Large"},
+					}},
+					htmlparse.ImageElement{Type: "image", Src: "https://cdn.example.com/x.png", Alt: "External"},
+				},
+			}},
+		},
+	}
+}
+
+func newImageMock() *mockBrowserClient {
+	return &mockBrowserClient{
+		chapter: newIllustratedChapter(),
+		images: map[string]*browser.BookImage{
+			"assets/small.png": {BookID: "123", Path: "assets/small.png", MIMEType: "image/png", Data: []byte("small")},
+			"assets/large.png": {BookID: "123", Path: "assets/large.png", MIMEType: "image/png", Data: []byte("a much larger image")},
+		},
+	}
+}
+
+func TestBookImageResource(t *testing.T) {
+	srv := newTestServer(t, newImageMock())
+	session := connectTestSession(t, srv, nil)
+
+	contents := readResourceText(t, session, "oreilly://book-image/123/assets/small.png")
+	assert.Equal(t, "image/png", contents.MIMEType)
+	assert.Equal(t, []byte("small"), contents.Blob)
+}
+
+func TestBookChapterResource_LinksImages(t *testing.T) {
+	srv := newTestServer(t, newImageMock())
+	session := connectTestSession(t, srv, nil)
+
+	text := readResourceText(t, session, "oreilly://book-chapter/123/ch01").Text
+	assert.Contains(t, text, `"uri":"oreilly://book-image/123/assets/small.png"`)
+	assert.Contains(t, text, `"uri":"oreilly://book-image/123/assets/large.png"`, "images inside admonitions are linked")
+	assert.NotContains(t, text, "oreilly://book-image/123/x.png")
+
+	md := readResourceText(t, session, "oreilly://book-chapter-markdown/123/ch01").Text
+	assert.Contains(t, md, "![Small](oreilly://book-image/123/assets/small.png)")
+	assert.Contains(t, md, "![External](https://cdn.example.com/x.png)")
+}
+
+func TestBookChapterResource_InlineImagesRespectsCaps(t *testing.T) {
+	srv := newTestServer(t, newImageMock())
+	srv.config.Image.InlineMaxBytes = 10
+	srv.config.Image.InlineMaxTotalBytes = 100
+	session := connectTestSession(t, srv, nil)
+
+	result, err := session.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "oreilly://book-chapter/123/ch01?images=inline"})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 2, "the large image exceeds the per-image cap")
+
+	var chapter browser.ChapterContentResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].Text), &chapter))
+	assert.Equal(t, "oreilly://book-image/123/assets/small.png", result.Contents[1].URI)
+	assert.Equal(t, []byte("small"), result.Contents[1].Blob)
+
+	// Without ?images=inline only the chapter is returned
+	result, err = session.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "oreilly://book-chapter/123/ch01"})
+	require.NoError(t, err)
+	assert.Len(t, result.Contents, 1)
+}
@@ -3,7 +3,10 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -16,14 +19,16 @@ type resourceDef struct {
 	uri, name, desc, mimeType string
 	handler                   func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error)
 	tmplDesc                  string // if non-empty, also register as ResourceTemplate
+	tmplQuery                 string // optional query expansion appended to the template URI, e.g. "{?format}"
 }
 
 // registerResources registers the resource handlers using a data-driven table.
 func (s *Server) registerResources() {
 	resources := []resourceDef{
-		{uri: "oreilly://book-details/{product_id}", name: "O'Reilly Book Details", desc: descResBookDetails, mimeType: "application/json", handler: s.GetBookDetailsResource, tmplDesc: descTmplBookDetails},
-		{uri: "oreilly://book-toc/{product_id}", name: "O'Reilly Book Table of Contents", desc: descResBookTOC, mimeType: "application/json", handler: s.GetBookTOCResource, tmplDesc: descTmplBookTOC},
-		{uri: "oreilly://book-chapter/{product_id}/{chapter_name}", name: "O'Reilly Book Chapter Content", desc: descResBookChapter, mimeType: "application/json", handler: s.GetBookChapterContentResource, tmplDesc: descTmplBookChapter},
+		{uri: "oreilly://book-details/{product_id}", name: "O'Reilly Book Details", desc: descResBookDetails, mimeType: "application/json", handler: s.GetBookDetailsResource, tmplDesc: descTmplBookDetails, tmplQuery: "{?refresh}"},
+		{uri: "oreilly://book-toc/{product_id}", name: "O'Reilly Book Table of Contents", desc: descResBookTOC, mimeType: "application/json", handler: s.GetBookTOCResource, tmplDesc: descTmplBookTOC, tmplQuery: "{?view,refresh}"},
+		{uri: "oreilly://book-chapter/{product_id}/{chapter_name}", name: "O'Reilly Book Chapter Content", desc: descResBookChapter, mimeType: "application/json", handler: s.GetBookChapterContentResource, tmplDesc: descTmplBookChapter, tmplQuery: "{?format,images}"},
+		{uri: "oreilly://book-chapter-markdown/{product_id}/{chapter_name}", name: "O'Reilly Book Chapter Markdown", desc: descResBookChapterMD, mimeType: "text/markdown", handler: s.GetBookChapterMarkdownResource, tmplDesc: descTmplBookChapterMD, tmplQuery: "{?images}"},
 		{uri: "oreilly://answer/{question_id}", name: "O'Reilly Answers Response", desc: descResAnswer, mimeType: "application/json", handler: s.GetAnswerResource, tmplDesc: descTmplAnswer},
 		{uri: "orm-mcp://server/status", name: "MCP Server Status", desc: "Server startup time and version for restart verification", mimeType: "application/json", handler: s.GetServerStatusResource},
 	}
@@ -31,25 +36,144 @@ func (s *Server) registerResources() {
 	for _, r := range resources {
 		s.server.AddResource(&mcp.Resource{URI: r.uri, Name: r.name, Description: r.desc, MIMEType: r.mimeType}, r.handler)
 		if r.tmplDesc != "" {
-			s.server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: r.uri, Name: r.name + " Template", Description: r.tmplDesc, MIMEType: r.mimeType}, r.handler)
+			s.server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: r.uri + r.tmplQuery, Name: r.name + " Template", Description: r.tmplDesc, MIMEType: r.mimeType}, r.handler)
 		}
 	}
+
+	// Template-only resources for reading a chapter section by section
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "oreilly://book-chapter/{product_id}/{chapter_name}/sections",
+		Name:        "O'Reilly Book Chapter Sections",
+		Description: descTmplChapterSections,
+		MIMEType:    "application/json",
+	}, s.GetChapterSectionsResource)
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "oreilly://book-chapter/{product_id}/{chapter_name}/section/{section}{?format,images}",
+		Name:        "O'Reilly Book Chapter Section",
+		Description: descTmplChapterSection,
+		MIMEType:    "application/json",
+	}, s.GetChapterSectionResource)
+
+	// Sections of one book matching a query, fetched and ranked on demand
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "oreilly://book-search/{product_id}{?q,limit}",
+		Name:        "O'Reilly Book Search",
+		Description: descTmplBookSearch,
+		MIMEType:    "application/json",
+	}, s.GetBookSearchResource)
+
+	// Formatted citation of a book; the MIME type depends on the style
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "oreilly://book-citation/{product_id}{?style}",
+		Name:        "O'Reilly Book Citation",
+		Description: descTmplBookCitation,
+	}, s.GetBookCitationResource)
+
+	// Images referenced from chapters; {+path} keeps the "/" separators of the book-relative path
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "oreilly://book-image/{product_id}/{+path}",
+		Name:        "O'Reilly Book Image",
+		Description: descTmplBookImage,
+	}, s.GetBookImageResource)
+}
+
+// resourceEncoder converts fetched data into resource contents for uri.
+type resourceEncoder func(uri string, data any) (*mcp.ReadResourceResult, error)
+
+// encodeJSON is the default resourceEncoder.
+func encodeJSON(uri string, data any) (*mcp.ReadResourceResult, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceResult(uri, jsonBytes), nil
 }
 
 // readResourceJSON is a generic helper for resource handlers that fetch data and return JSON.
-func (s *Server) readResourceJSON(uri string, fetch func() (any, error), opName string, kvs ...any) (*mcp.ReadResourceResult, error) {
+// When offline is non-nil it is used as a fallback while the browser client is
+// unavailable or the fetch fails, so previously fetched content stays readable.
+func (s *Server) readResourceJSON(ctx context.Context, uri string, fetch, offline func() (any, error), opName string, kvs ...any) (*mcp.ReadResourceResult, error) {
+	return s.readResource(ctx, uri, fetch, offline, encodeJSON, opName, kvs...)
+}
+
+// readResource is readResourceJSON with a custom encoder for the fetched data.
+func (s *Server) readResource(ctx context.Context, uri string, fetch, offline func() (any, error), encode resourceEncoder, opName string, kvs ...any) (*mcp.ReadResourceResult, error) {
 	if s.getBrowserClient() == nil {
+		if result, ok := s.readOffline(uri, offline, encode, opName); ok {
+			return result, nil
+		}
 		return clientUnavailableResult(uri), nil
 	}
 	data, err := fetch()
 	if err != nil {
+		var paramErr *resourceParamError
+		if errors.As(err, &paramErr) {
+			return paramErrorResult(uri, paramErr.msg), nil
+		}
+		if ctx.Err() == nil {
+			if result, ok := s.readOffline(uri, offline, encode, opName); ok {
+				slog.Warn("取得に失敗したため保存済みコンテンツを返します", "uri", uri, "error", err)
+				return result, nil
+			}
+		}
 		return errH.ResourceContents(uri, err, append([]any{"operation", opName}, kvs...)...), nil
 	}
-	jsonBytes, err := json.Marshal(data)
+	result, err := encode(uri, data)
 	if err != nil {
 		return errH.ResourceContents(uri, err, "operation", "marshal_"+opName), nil
 	}
-	return jsonResourceResult(uri, jsonBytes), nil
+	return result, nil
+}
+
+// resourceParamError reports a problem with the requested URI parameters.
+// Its message is safe to show and is returned verbatim instead of being sanitized.
+type resourceParamError struct{ msg string }
+
+func (e *resourceParamError) Error() string { return e.msg }
+
+// chapterLookupError turns a chapter resolution failure into a resourceParamError
+// that lists the closest chapters with ready-to-read URIs. Other errors are returned as is.
+func chapterLookupError(err error) error {
+	var notFound *browser.ChapterNotFoundError
+	if !errors.As(err, &notFound) {
+		return err
+	}
+	var b strings.Builder
+	if notFound.Ambiguous {
+		fmt.Fprintf(&b, "chapter %q is ambiguous in book %s.", notFound.ChapterName, notFound.ProductID)
+	} else {
+		fmt.Fprintf(&b, "chapter %q not found in book %s.", notFound.ChapterName, notFound.ProductID)
+	}
+	if len(notFound.Candidates) > 0 {
+		b.WriteString(" Closest matches:")
+		for _, c := range notFound.Candidates {
+			fmt.Fprintf(&b, "\n- %s: %s", c.Title, mcputil.BookChapterURI(notFound.ProductID, c.Href))
+		}
+	}
+	fmt.Fprintf(&b, "\nFull table of contents: oreilly://book-toc/%s?view=tree", notFound.ProductID)
+	return &resourceParamError{b.String()}
+}
+
+// readOffline serves uri from the local content store. ok is false when
+// offline is nil or the content has never been fetched.
+func (s *Server) readOffline(uri string, offline func() (any, error), encode resourceEncoder, opName string) (*mcp.ReadResourceResult, bool) {
+	if offline == nil {
+		return nil, false
+	}
+	data, err := offline()
+	var paramErr *resourceParamError
+	if errors.As(err, &paramErr) {
+		return paramErrorResult(uri, paramErr.msg), true
+	}
+	if err != nil {
+		slog.Debug("保存済みコンテンツがありません", "uri", uri, "error", err)
+		return nil, false
+	}
+	result, err := encode(uri, data)
+	if err != nil {
+		return errH.ResourceContents(uri, err, "operation", "marshal_offline_"+opName), true
+	}
+	return result, true
 }
 
 func clientUnavailableResult(uri string) *mcp.ReadResourceResult {
@@ -82,78 +206,191 @@ func paramErrorResult(uri, msg string) *mcp.ReadResourceResult {
 	}
 }
 
-// GetBookDetailsResource handles book detail resource requests.
-func (s *Server) GetBookDetailsResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+// GetBookDetailsResource handles book detail resource requests. The authors and
+// publisher, which the details API does not return, come from the search API.
+// "?refresh=true" bypasses the in-process cache for a book updated since it was cached.
+func (s *Server) GetBookDetailsResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
 	productID := mcputil.ExtractProductIDFromURI(req.Params.URI)
 	if productID == "" {
 		return paramErrorResult(req.Params.URI, "product_id not found in URI"), nil
 	}
-	return s.readResourceJSON(req.Params.URI, func() (any, error) {
-		return s.getBrowserClient().GetBookDetails(productID)
+	if mcputil.ExtractQueryParam(req.Params.URI, "refresh") == "true" {
+		s.invalidateBookCache(productID)
+	}
+	return s.readResourceJSON(ctx, req.Params.URI, func() (any, error) {
+		client := s.getBrowserClient()
+		details, err := client.GetBookDetails(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		return withBookCredits(details, s.bookCredits(ctx, client, productID, details)), nil
+	}, func() (any, error) {
+		return s.contentStore.StaleBookDetails(productID)
 	}, "get_book_details", "product_id", productID)
 }
 
+// withBookCredits returns a copy of details with the authors and publisher in
+// credits, which may be nil. The cached details themselves are left as they are.
+func withBookCredits(details *browser.BookDetailResponse, credits *browser.BookCredits) *browser.BookDetailResponse {
+	if credits == nil {
+		return details
+	}
+	d := *details
+	d.Authors, d.Publisher = credits.Authors, credits.Publisher
+	return &d
+}
+
 // GetBookTOCResource handles book TOC resource requests.
-func (s *Server) GetBookTOCResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+// "?view=tree" returns the nested hierarchy instead of the flattened list.
+// "?refresh=true" bypasses the in-process cache, as for book details.
+func (s *Server) GetBookTOCResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
 	productID := mcputil.ExtractProductIDFromURI(req.Params.URI)
 	if productID == "" {
 		return paramErrorResult(req.Params.URI, "product_id not found in URI"), nil
 	}
-	return s.readResourceJSON(req.Params.URI, func() (any, error) {
-		return s.getBrowserClient().GetBookTOC(productID)
-	}, "get_book_toc", "product_id", productID)
+	encode := encodeJSON
+	if mcputil.ExtractQueryParam(req.Params.URI, "view") == tocViewTree {
+		encode = encodeTOCTree
+	}
+	if mcputil.ExtractQueryParam(req.Params.URI, "refresh") == "true" {
+		s.invalidateBookCache(productID)
+	}
+	return s.readResource(ctx, req.Params.URI, func() (any, error) {
+		return s.getBrowserClient().GetBookTOC(ctx, productID)
+	}, func() (any, error) {
+		return s.contentStore.StaleBookTOC(productID)
+	}, encode, "get_book_toc", "product_id", productID)
 }
 
 // GetBookChapterContentResource handles book chapter content resource requests.
-func (s *Server) GetBookChapterContentResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-	productID, chapterName := mcputil.ExtractProductIDAndChapterFromURI(req.Params.URI)
+// "?format=markdown" returns the chapter rendered as Markdown instead of JSON.
+func (s *Server) GetBookChapterContentResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	encode := encodeJSON
+	if mcputil.ExtractQueryParam(req.Params.URI, "format") == string(ResponseFormatMarkdown) {
+		encode = encodeChapterMarkdown
+	}
+	return s.readChapterResource(ctx, req, encode)
+}
+
+// GetBookChapterMarkdownResource handles oreilly://book-chapter-markdown resource requests.
+func (s *Server) GetBookChapterMarkdownResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	return s.readChapterResource(ctx, req, encodeChapterMarkdown)
+}
+
+// chapterEncoder returns encode, wrapped to embed the chapter's images when
+// uri asks for "?images=inline".
+func (s *Server) chapterEncoder(ctx context.Context, uri string, encode resourceEncoder) resourceEncoder {
+	if mcputil.ExtractQueryParam(uri, "images") == imagesInline {
+		return s.withInlineImages(ctx, encode)
+	}
+	return encode
+}
+
+// readChapterResource fetches a chapter and encodes it with encode.
+func (s *Server) readChapterResource(ctx context.Context, req *mcp.ReadResourceRequest, encode resourceEncoder) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	productID, chapterName := mcputil.ExtractProductIDAndChapterFromURI(uri)
 	if productID == "" || chapterName == "" {
-		return paramErrorResult(req.Params.URI, "product_id or chapter_name not found in URI"), nil
+		return paramErrorResult(uri, "product_id or chapter_name not found in URI"), nil
+	}
+	return s.readResource(ctx, uri, func() (any, error) {
+		chapter, err := s.getChapterContent(ctx, req.Session, productID, chapterName)
+		return linkChapterImages(chapter), chapterLookupError(err)
+	}, func() (any, error) {
+		chapter, err := s.contentStore.StaleBookChapterContent(productID, chapterName)
+		return linkChapterImages(chapter), chapterLookupError(err)
+	}, s.chapterEncoder(ctx, uri, encode), "get_chapter", "product_id", productID, "chapter_name", chapterName)
+}
+
+// encodeChapterMarkdown renders a *browser.ChapterContentResponse as text/markdown.
+func encodeChapterMarkdown(uri string, data any) (*mcp.ReadResourceResult, error) {
+	chapter, ok := data.(*browser.ChapterContentResponse)
+	if !ok || chapter == nil {
+		return nil, fmt.Errorf("unexpected chapter data type %T", data)
 	}
-	return s.readResourceJSON(req.Params.URI, func() (any, error) {
-		return s.getBrowserClient().GetBookChapterContent(productID, chapterName)
-	}, "get_chapter", "product_id", productID, "chapter_name", chapterName)
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: "text/markdown",
+			Text:     formatChapterMarkdown(chapter),
+		}},
+	}, nil
 }
 
 // GetAnswerResource handles answer resource requests.
-func (s *Server) GetAnswerResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+func (s *Server) GetAnswerResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
 	questionID := mcputil.ExtractQuestionIDFromURI(req.Params.URI)
 	if questionID == "" {
 		return paramErrorResult(req.Params.URI, "question_id not found in URI"), nil
 	}
-	return s.readResourceJSON(req.Params.URI, func() (any, error) {
-		answer, err := s.getBrowserClient().GetQuestionByID(questionID)
+	return s.readResourceJSON(ctx, req.Params.URI, func() (any, error) {
+		failure, failed := s.answerFailure(questionID)
+		answer, err := s.getBrowserClient().GetQuestionByID(ctx, questionID)
 		if err != nil {
-			return nil, err
+			if failed {
+				// The failure is the answer's final state, so report it rather than the lookup error
+				answer = &browser.AnswerResponse{QuestionID: questionID}
+			} else {
+				return nil, err
+			}
+		}
+		if answer.IsFinished {
+			failure = "" // finished after polling gave up
 		}
 		return struct {
 			QuestionID          string                       `json:"question_id"`
 			Answer              string                       `json:"answer"`
 			IsFinished          bool                         `json:"is_finished"`
+			Error               string                       `json:"error,omitempty"` // why background polling failed (async mode)
 			Sources             []browser.AnswerSource       `json:"sources"`
 			RelatedResources    []browser.RelatedResource    `json:"related_resources"`
 			AffiliationProducts []browser.AffiliationProduct `json:"affiliation_products"`
 			FollowupQuestions   []string                     `json:"followup_questions"`
+			SourceLinks         []AnswerSourceLink           `json:"source_links"`
 			CitationNote        string                       `json:"citation_note"`
 		}{
 			QuestionID:          answer.QuestionID,
+			Error:               failure,
 			Answer:              answer.MisoResponse.Data.Answer,
 			IsFinished:          answer.IsFinished,
 			Sources:             answer.MisoResponse.Data.Sources,
 			RelatedResources:    answer.MisoResponse.Data.RelatedResources,
 			AffiliationProducts: answer.MisoResponse.Data.AffiliationProducts,
 			FollowupQuestions:   answer.MisoResponse.Data.FollowupQuestions,
+			SourceLinks:         answerSourceLinks(answer.MisoResponse.Data),
 			CitationNote:        "IMPORTANT: When referencing this information, always cite the sources listed above with proper attribution to O'Reilly Media.",
 		}, nil
-	}, "get_answer", "question_id", questionID)
+	}, nil, "get_answer", "question_id", questionID)
+}
+
+// bookCacheReporter is implemented by clients that cache book details and TOCs in process.
+type bookCacheReporter interface {
+	BookCacheStats() browser.BookCacheStats
+}
+
+// bookCacheInvalidator is implemented by clients whose in-process book caches can be dropped.
+type bookCacheInvalidator interface {
+	InvalidateBookCache(productID string)
+}
+
+// invalidateBookCache drops the cached details and TOC of productID, or of
+// every book when productID is empty, so that they are fetched again.
+func (s *Server) invalidateBookCache(productID string) {
+	if invalidator, ok := s.getBrowserClient().(bookCacheInvalidator); ok {
+		invalidator.InvalidateBookCache(productID)
+		slog.Debug("書籍キャッシュを破棄しました", "product_id", productID)
+	}
 }
 
 // GetServerStatusResource returns server startup time and version for restart verification.
 func (s *Server) GetServerStatusResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-	status := map[string]string{
+	status := map[string]any{
 		"started_at": s.startedAt.UTC().Format(time.RFC3339),
 		"version":    s.serverVersion,
 	}
+	if reporter, ok := s.getBrowserClient().(bookCacheReporter); ok {
+		status["book_cache"] = reporter.BookCacheStats()
+	}
 	jsonBytes, _ := json.Marshal(status)
 	return &mcp.ReadResourceResult{
 		Contents: []*mcp.ResourceContents{{
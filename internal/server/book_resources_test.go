@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+func readBookDetails(t *testing.T, srv *Server, productID string) map[string]any {
+	t.Helper()
+	result, err := srv.GetBookDetailsResource(context.Background(), &mcp.ReadResourceRequest{
+		Params: &mcp.ReadResourceParams{URI: "oreilly://book-details/" + productID},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].Text), &got))
+	return got
+}
+
+func newStoreWithBookDetails(t *testing.T, productID string) *browser.ContentStore {
+	t.Helper()
+	store := browser.NewContentStore(t.TempDir())
+	data, err := json.Marshal(&browser.BookDetailResponse{Title: "Stored Book"})
+	require.NoError(t, err)
+	require.NoError(t, store.Put("book-details/"+productID, data, "", ""))
+	return store
+}
+
+func TestGetBookDetailsResource_ServesStoreWhenClientUnavailable(t *testing.T) {
+	srv := newTestServer(t, nil)
+	srv.browserClient = nil // degraded mode
+	srv.contentStore = newStoreWithBookDetails(t, "123")
+
+	got := readBookDetails(t, srv, "123")
+	assert.Equal(t, "Stored Book", got["title"])
+	assert.Equal(t, true, got["metadata"].(map[string]any)["stale"])
+
+	// Never fetched: keep the unavailable error
+	got = readBookDetails(t, srv, "999")
+	assert.Equal(t, "browser client is not available", got["error"])
+}
+
+func TestGetBookDetailsResource_FallsBackOnFetchError(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookErr: errors.New("dial tcp: network is unreachable")})
+	srv.contentStore = newStoreWithBookDetails(t, "123")
+
+	got := readBookDetails(t, srv, "123")
+	assert.Equal(t, "Stored Book", got["title"])
+	assert.Equal(t, true, got["metadata"].(map[string]any)["stale"])
+}
+
+func TestGetBookDetailsResource_PrefersLiveData(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookDetails: &browser.BookDetailResponse{Title: "Live Book"}})
+	srv.contentStore = newStoreWithBookDetails(t, "123")
+
+	got := readBookDetails(t, srv, "123")
+	assert.Equal(t, "Live Book", got["title"])
+	assert.Nil(t, got["metadata"])
+}
+
+func TestBookResources_Refresh(t *testing.T) {
+	mock := &mockBrowserClient{bookDetails: &browser.BookDetailResponse{Title: "Live Book"}, toc: &browser.TableOfContentsResponse{BookID: "123"}}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	readResourceText(t, session, "oreilly://book-details/123")
+	readResourceText(t, session, "oreilly://book-toc/123?view=tree")
+	assert.Empty(t, mock.invalidated, "the cache is used by default")
+
+	readResourceText(t, session, "oreilly://book-details/123?refresh=true")
+	readResourceText(t, session, "oreilly://book-toc/456?view=tree&refresh=true")
+	assert.Equal(t, []string{"123", "456"}, mock.invalidated)
+}
+
+func TestReauthenticateHandler_ClearsBookCache(t *testing.T) {
+	mock := &mockBrowserClient{}
+	srv := newTestServer(t, mock)
+
+	_, result, err := srv.ReauthenticateHandler(context.Background(), nil, struct{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "authenticated", result.Status)
+	assert.Empty(t, mock.invalidated, "a valid session keeps the cache")
+
+	mock.authErr = errors.New("session expired")
+	_, result, err = srv.ReauthenticateHandler(context.Background(), nil, struct{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "setup_completed", result.Status)
+	assert.Equal(t, []string{""}, mock.invalidated, "a new session clears every book")
+}
+
+func TestGetBookDetailsResource_AddsCredits(t *testing.T) {
+	details := &browser.BookDetailResponse{Identifier: "123", Title: "Live Book"}
+	mock := &mockBrowserClient{
+		bookDetails: details,
+		credits:     map[string]*browser.BookCredits{"123": {Authors: []string{"Ann Author"}, Publisher: "O'Reilly Media, Inc."}},
+	}
+	srv := newTestServer(t, mock)
+
+	got := readBookDetails(t, srv, "123")
+	assert.Equal(t, []any{"Ann Author"}, got["authors"])
+	assert.Equal(t, "O'Reilly Media, Inc.", got["publisher"])
+	assert.Empty(t, details.Authors, "the cached details are not modified")
+
+	mock.creditsErr = errors.New("search unavailable")
+	got = readBookDetails(t, srv, "123")
+	assert.Equal(t, "Live Book", got["title"], "details are served without credits")
+	assert.Nil(t, got["authors"])
+}
+
+func TestBookChapterResource_MarkdownFormat(t *testing.T) {
+	mock := &mockBrowserClient{chapter: &browser.ChapterContentResponse{
+		BookID:       "123",
+		ChapterTitle: "Basics",
+		SourceURL:    "https://learning.oreilly.com/ch01.html",
+		Content: htmlparse.ParsedChapterContent{
+			Sections: []htmlparse.ContentSection{{
+				Heading: htmlparse.ContentHeading{Level: 2, Text: "Setup"},
+				Content: []any{htmlparse.CodeBlockElement{Type: "code_block", Language: "sh", Code: "go test ./..."}},
+			}},
+		},
+	}}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name, uri, wantMIME string
+	}{
+		{"default JSON", "oreilly://book-chapter/123/ch01", "application/json"},
+		{"format query", "oreilly://book-chapter/123/ch01?format=markdown", "text/markdown"},
+		{"markdown template", "oreilly://book-chapter-markdown/123/ch01", "text/markdown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tt.uri})
+			require.NoError(t, err)
+			require.Len(t, result.Contents, 1)
+			content := result.Contents[0]
+			assert.Equal(t, tt.wantMIME, content.MIMEType)
+			if tt.wantMIME == "text/markdown" {
+				assert.Contains(t, content.Text, "# Basics\n\n## Setup\n\n```sh\ngo test ./...\n```")
+				assert.Contains(t, content.Text, "O'Reilly Media — https://learning.oreilly.com/ch01.html")
+			} else {
+				assert.True(t, json.Valid([]byte(content.Text)))
+			}
+		})
+	}
+}
+
+func TestBookChapterResource_UnresolvedChapterListsCandidates(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookErr: fmt.Errorf("チャプターHTML取得失敗: %w", &browser.ChapterNotFoundError{
+		ProductID:   "123",
+		ChapterName: "processing",
+		Ambiguous:   true,
+		Candidates: []browser.ChapterCandidate{
+			{Title: "10. Batch Processing", Href: "ch10.html", Score: 0.7},
+			{Title: "11. Stream Processing", Href: "ch11.html", Score: 0.7},
+		},
+	})})
+	session := connectTestSession(t, srv, nil)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-chapter/123/processing").Text), &got))
+	msg, _ := got["error"].(string)
+	assert.Contains(t, msg, `chapter "processing" is ambiguous in book 123.`)
+	assert.Contains(t, msg, "- 10. Batch Processing: oreilly://book-chapter/123/ch10.html")
+	assert.Contains(t, msg, "- 11. Stream Processing: oreilly://book-chapter/123/ch11.html")
+	assert.Contains(t, msg, "oreilly://book-toc/123?view=tree")
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+const (
+	// defaultBookSearchResults is the number of sections returned when limit is not set.
+	defaultBookSearchResults = 10
+	// maxBookSearchChapters caps the chapters fetched for one search; chapters
+	// whose TOC titles match the query are fetched first.
+	maxBookSearchChapters = 40
+	// bookSearchConcurrency is the number of chapters fetched at once.
+	bookSearchConcurrency = 4
+)
+
+// BookSearchHit is a section of the book matching the query.
+type BookSearchHit struct {
+	ChapterName  string   `json:"chapter_name"`
+	ChapterTitle string   `json:"chapter_title"`
+	SectionIndex int      `json:"section_index"`
+	Heading      string   `json:"heading,omitempty"`
+	HeadingPath  []string `json:"heading_path"` // chapter title down to the section heading
+	Score        float64  `json:"score"`
+	Excerpt      string   `json:"excerpt"`
+	SectionURI   string   `json:"section_uri"`
+}
+
+// BookSearchTOCMatch is a TOC entry whose title contains a query term.
+type BookSearchTOCMatch struct {
+	Title string `json:"title"`
+	URI   string `json:"uri"`
+}
+
+// BookSearchResult is the response of the book-search resource.
+type BookSearchResult struct {
+	BookID           string               `json:"book_id"`
+	Query            string               `json:"query"`
+	Count            int                  `json:"count"`
+	Total            int                  `json:"total"`
+	ChaptersSearched int                  `json:"chapters_searched"`
+	ChaptersTotal    int                  `json:"chapters_total"`
+	FailedChapters   []string             `json:"failed_chapters,omitempty"`
+	TOCMatches       []BookSearchTOCMatch `json:"toc_matches,omitempty"`
+	Results          []BookSearchHit      `json:"results"`
+}
+
+// bookSearchChapter is one chapter file of the book with its TOC relevance.
+type bookSearchChapter struct {
+	name     string // href without fragment, used in chapter URIs
+	title    string
+	tocScore int // distinct query terms found in the titles of its TOC entries
+}
+
+// GetBookSearchResource searches the sections of one book for q.
+// 目次のタイトルで候補を絞ってからチャプターを並行取得し、セクション単位で順位付けします。
+func (s *Server) GetBookSearchResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	productID := mcputil.ExtractProductIDFromURI(uri)
+	if productID == "" {
+		return paramErrorResult(uri, "product_id not found in URI"), nil
+	}
+	query := strings.TrimSpace(mcputil.ExtractQueryParam(uri, "q"))
+	if query == "" {
+		return paramErrorResult(uri, "query parameter q is required, e.g. oreilly://book-search/"+productID+"?q=idempotency"), nil
+	}
+	if len(query) > maxQueryLength {
+		return paramErrorResult(uri, fmt.Sprintf("q is too long. Please use %d characters or fewer.", maxQueryLength)), nil
+	}
+	limit := defaultBookSearchResults
+	if v := mcputil.ExtractQueryParam(uri, "limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return paramErrorResult(uri, "limit must be a positive integer"), nil
+		}
+		limit = min(n, maxLocalResults)
+	}
+
+	return s.readResourceJSON(ctx, uri, func() (any, error) {
+		return s.searchBook(ctx, productID, query, limit)
+	}, nil, "search_book", "product_id", productID, "query", query)
+}
+
+// searchBook fetches the chapters of a book and ranks their sections for query.
+func (s *Server) searchBook(ctx context.Context, productID, query string, limit int) (*BookSearchResult, error) {
+	terms := slices.Compact(slices.Sorted(slices.Values(fulltext.Tokenize(query))))
+	if len(terms) == 0 {
+		return nil, &resourceParamError{"q contains no searchable words"}
+	}
+	client := s.getBrowserClient()
+	toc, err := client.GetBookTOC(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	chapters, tocMatches := bookSearchChapters(toc, terms)
+	if len(chapters) == 0 {
+		return nil, &resourceParamError{"book " + productID + " has no chapters to search"}
+	}
+	result := &BookSearchResult{
+		BookID:        productID,
+		Query:         query,
+		ChaptersTotal: len(chapters),
+		TOCMatches:    tocMatches,
+		Results:       []BookSearchHit{},
+	}
+	chapters = chapters[:min(len(chapters), maxBookSearchChapters)]
+
+	index := fulltext.New("")
+	paths := map[string][][]string{} // chapter name → heading path per section
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	queue := make(chan bookSearchChapter)
+	for range min(bookSearchConcurrency, len(chapters)) {
+		wg.Go(func() {
+			for ch := range queue {
+				docs, sectionPaths, err := fetchSearchChapter(ctx, client, productID, ch)
+				mu.Lock()
+				if err != nil {
+					slog.Warn("書籍内検索でチャプターの取得に失敗しました", "product_id", productID, "chapter", ch.name, "error", err)
+					result.FailedChapters = append(result.FailedChapters, ch.name)
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					paths[ch.name] = sectionPaths
+					result.ChaptersSearched++
+				}
+				mu.Unlock()
+				if err == nil {
+					_ = index.IndexChapter(productID, ch.name, docs)
+				}
+			}
+		})
+	}
+feed:
+	for _, ch := range chapters {
+		select {
+		case queue <- ch:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if result.ChaptersSearched == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	slices.Sort(result.FailedChapters)
+
+	hits, total := index.Search(query, fulltext.SearchOptions{Limit: limit})
+	result.Total = total
+	for _, hit := range hits {
+		result.Results = append(result.Results, BookSearchHit{
+			ChapterName:  hit.ChapterName,
+			ChapterTitle: hit.ChapterTitle,
+			SectionIndex: hit.SectionIndex,
+			Heading:      hit.Heading,
+			HeadingPath:  paths[hit.ChapterName][hit.SectionIndex],
+			Score:        hit.Score,
+			Excerpt:      hit.Snippet,
+			SectionURI:   chapterSectionURI(productID, hit.ChapterName, hit.SectionIndex),
+		})
+	}
+	result.Count = len(result.Results)
+	slog.Info("書籍内検索完了", "product_id", productID, "query", query,
+		"chapters_searched", result.ChaptersSearched, "result_count", result.Count, "total", total)
+	return result, nil
+}
+
+// bookSearchChapters lists the chapter files of a book, those whose TOC
+// entries mention more query terms first, and the TOC entries mentioning any.
+func bookSearchChapters(toc *browser.TableOfContentsResponse, terms []string) ([]bookSearchChapter, []BookSearchTOCMatch) {
+	var chapters []bookSearchChapter
+	var matches []BookSearchTOCMatch
+	byName := map[string]int{}
+	matched := map[string]map[string]bool{} // chapter name → query terms in its titles
+
+	for _, item := range toc.TableOfContents {
+		name, fragment, _ := strings.Cut(item.Href, "#")
+		if name == "" {
+			continue
+		}
+		i, ok := byName[name]
+		if !ok {
+			i = len(chapters)
+			byName[name] = i
+			chapters = append(chapters, bookSearchChapter{name: name, title: item.Title})
+		} else if fragment == "" {
+			chapters[i].title = item.Title
+		}
+
+		titleTerms := fulltext.Tokenize(item.Title)
+		found := false
+		for _, term := range terms {
+			if slices.Contains(titleTerms, term) {
+				found = true
+				if matched[name] == nil {
+					matched[name] = map[string]bool{}
+				}
+				matched[name][term] = true
+			}
+		}
+		if found {
+			matches = append(matches, BookSearchTOCMatch{Title: item.Title, URI: mcputil.BookChapterURI(toc.BookID, item.Href)})
+		}
+	}
+
+	for i := range chapters {
+		chapters[i].tocScore = len(matched[chapters[i].name])
+	}
+	slices.SortStableFunc(chapters, func(a, b bookSearchChapter) int {
+		return cmp.Compare(b.tocScore, a.tocScore)
+	})
+	return chapters, matches
+}
+
+// fetchSearchChapter downloads and parses a chapter into one document per
+// section, with the heading path of each section.
+func fetchSearchChapter(ctx context.Context, client browser.Client, productID string, ch bookSearchChapter) ([]fulltext.Document, [][]string, error) {
+	html, _, err := client.GetChapterHTMLContent(ctx, productID, ch.name)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, err := htmlparse.ParseHTMLContent(html)
+	if err != nil {
+		return nil, nil, fmt.Errorf("HTML解析失敗: %w", err)
+	}
+	title := ch.title
+	if title == "" {
+		title = cmp.Or(parsed.Title, ch.name)
+	}
+
+	docs := make([]fulltext.Document, 0, len(parsed.Sections))
+	paths := make([][]string, len(parsed.Sections))
+	type open struct {
+		level int
+		text  string
+	}
+	var stack []open
+	for i, section := range parsed.Sections {
+		heading := strings.TrimSpace(section.Heading.Text)
+		if heading != "" {
+			for len(stack) > 0 && stack[len(stack)-1].level >= section.Heading.Level {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, open{level: section.Heading.Level, text: heading})
+		}
+		path := []string{title}
+		for _, h := range stack {
+			if h.text != title {
+				path = append(path, h.text)
+			}
+		}
+		paths[i] = path
+
+		text := htmlparse.SectionText(section)
+		if text == "" && heading == "" {
+			continue
+		}
+		docs = append(docs, fulltext.Document{
+			BookID:       productID,
+			ChapterName:  ch.name,
+			ChapterTitle: title,
+			SectionIndex: i,
+			Heading:      heading,
+			Text:         text,
+		})
+	}
+	return docs, paths, nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+func newSearchableBookMock() *mockBrowserClient {
+	return &mockBrowserClient{
+		toc: &browser.TableOfContentsResponse{
+			BookID: "123",
+			TableOfContents: []browser.TableOfContentsItem{
+				{ID: "ch01", Title: "1. Reliable Services", Href: "ch01.html"},
+				{ID: "ch01-retries", Title: "Retries", Href: "ch01.html#retries", Parent: "ch01"},
+				{ID: "ch02", Title: "2. Idempotency", Href: "ch02.html"},
+				{ID: "ch03", Title: "3. Storage", Href: "ch03.html"},
+			},
+		},
+		chapterHTML: map[string]string{
+			"ch01.html": `<html><body>
+				<h1>Reliable Services</h1><p>Services fail in many ways.</p>
+				<h2 id="retries">Retries</h2><p>Clients retry failed requests.</p>
+				<h3>Safe retries</h3><p>Attach an idempotency key so that a retried request is applied once.</p>
+			</body></html>`,
+			"ch02.html": `<html><body>
+				<h1>Idempotency</h1><p>An idempotency key identifies a request. Store each idempotency key with its response.</p>
+				<h2>Expiry</h2><p>Keys expire after a day.</p>
+			</body></html>`,
+			"ch03.html": `<html><body><h1>Storage</h1><p>Nothing relevant.</p></body></html>`,
+		},
+	}
+}
+
+func TestBookSearchResource(t *testing.T) {
+	srv := newTestServer(t, newSearchableBookMock())
+	session := connectTestSession(t, srv, nil)
+
+	var got BookSearchResult
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-search/123?q=idempotency%20key").Text), &got))
+
+	assert.Equal(t, 3, got.ChaptersSearched)
+	assert.Equal(t, 3, got.ChaptersTotal)
+	assert.Equal(t, []BookSearchTOCMatch{{Title: "2. Idempotency", URI: "oreilly://book-chapter/123/ch02.html"}}, got.TOCMatches)
+	require.Equal(t, 2, got.Total)
+	require.Len(t, got.Results, 2)
+
+	first := got.Results[0]
+	assert.Equal(t, "ch02.html", first.ChapterName)
+	assert.Equal(t, []string{"2. Idempotency", "Idempotency"}, first.HeadingPath)
+	assert.Equal(t, "oreilly://book-chapter/123/ch02.html/section/0", first.SectionURI)
+	assert.Contains(t, first.Excerpt, "idempotency key")
+
+	second := got.Results[1]
+	assert.Equal(t, []string{"1. Reliable Services", "Reliable Services", "Retries", "Safe retries"}, second.HeadingPath)
+	assert.Equal(t, 2, second.SectionIndex)
+}
+
+func TestBookSearchResource_Limit(t *testing.T) {
+	srv := newTestServer(t, newSearchableBookMock())
+	session := connectTestSession(t, srv, nil)
+
+	var got BookSearchResult
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-search/123?q=idempotency&limit=1").Text), &got))
+	assert.Equal(t, 2, got.Total)
+	assert.Len(t, got.Results, 1)
+}
+
+func TestBookSearchResource_FailedChapters(t *testing.T) {
+	mock := newSearchableBookMock()
+	mock.chapterErrs = map[string]error{"ch03.html": assert.AnError}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	var got BookSearchResult
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-search/123?q=retries").Text), &got))
+	assert.Equal(t, 2, got.ChaptersSearched)
+	assert.Equal(t, []string{"ch03.html"}, got.FailedChapters)
+	assert.NotEmpty(t, got.Results)
+}
+
+func TestBookSearchResource_Errors(t *testing.T) {
+	srv := newTestServer(t, newSearchableBookMock())
+	session := connectTestSession(t, srv, nil)
+
+	tests := []struct {
+		name, uri, want string
+	}{
+		{"missing query", "oreilly://book-search/123", "query parameter q is required"},
+		{"invalid limit", "oreilly://book-search/123?q=retry&limit=x", "limit must be a positive integer"},
+		{"no searchable words", "oreilly://book-search/123?q=%21%21", "no searchable words"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, readResourceText(t, session, tt.uri).Text, tt.want)
+		})
+	}
+}
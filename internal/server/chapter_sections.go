@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// ChapterSectionSummary describes one section of a chapter without its content.
+type ChapterSectionSummary struct {
+	Index     int    `json:"index"`
+	Heading   string `json:"heading"`
+	Level     int    `json:"level"`
+	ID        string `json:"id,omitempty"`
+	WordCount int    `json:"word_count"`
+	URI       string `json:"uri"`
+}
+
+// ChapterSectionsResult is the response of the chapter sections resource.
+type ChapterSectionsResult struct {
+	BookID       string                  `json:"book_id"`
+	ChapterName  string                  `json:"chapter_name"`
+	ChapterTitle string                  `json:"chapter_title"`
+	TotalWords   int                     `json:"total_words"`
+	Sections     []ChapterSectionSummary `json:"sections"`
+	Metadata     map[string]any          `json:"metadata,omitempty"`
+}
+
+// ChapterSectionResult is the response of the single chapter section resource.
+type ChapterSectionResult struct {
+	BookID        string                   `json:"book_id"`
+	ChapterName   string                   `json:"chapter_name"`
+	ChapterTitle  string                   `json:"chapter_title"`
+	Index         int                      `json:"index"`
+	TotalSections int                      `json:"total_sections"`
+	Section       htmlparse.ContentSection `json:"section"`
+	PrevURI       string                   `json:"prev_uri,omitempty"`
+	NextURI       string                   `json:"next_uri,omitempty"`
+	SourceURL     string                   `json:"source_url"`
+	Metadata      map[string]any           `json:"metadata,omitempty"`
+
+	chapter *browser.ChapterContentResponse // Markdown 描画用
+}
+
+// chapterSectionURI builds the URI of a single section of a chapter.
+func chapterSectionURI(productID, chapterName string, index int) string {
+	return mcputil.BookChapterURI(productID, chapterName) + "/section/" + strconv.Itoa(index)
+}
+
+// GetChapterSectionsResource lists the sections of a chapter with heading, level and word count.
+func (s *Server) GetChapterSectionsResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	productID, chapterName, rest := mcputil.ExtractChapterSubresourceFromURI(req.Params.URI)
+	if productID == "" || chapterName == "" || len(rest) != 1 || rest[0] != "sections" {
+		return paramErrorResult(req.Params.URI, "product_id or chapter_name not found in URI"), nil
+	}
+	return s.readChapterDerived(ctx, req, productID, chapterName, encodeJSON, func(chapter *browser.ChapterContentResponse) (any, error) {
+		return buildChapterSections(chapter), nil
+	})
+}
+
+// GetChapterSectionResource returns a single chapter section by index or heading ID.
+// "?format=markdown" returns the section rendered as Markdown instead of JSON.
+func (s *Server) GetChapterSectionResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	productID, chapterName, rest := mcputil.ExtractChapterSubresourceFromURI(req.Params.URI)
+	if productID == "" || chapterName == "" || len(rest) != 2 || rest[0] != "section" || rest[1] == "" {
+		return paramErrorResult(req.Params.URI, "product_id, chapter_name or section not found in URI"), nil
+	}
+	selector := rest[1]
+
+	encode := encodeJSON
+	if mcputil.ExtractQueryParam(req.Params.URI, "format") == string(ResponseFormatMarkdown) {
+		encode = encodeSectionMarkdown
+	}
+	return s.readChapterDerived(ctx, req, productID, chapterName, encode, func(chapter *browser.ChapterContentResponse) (any, error) {
+		return selectChapterSection(chapter, selector)
+	})
+}
+
+// readChapterDerived fetches a chapter (falling back to the content store) and
+// returns a view derived from it.
+func (s *Server) readChapterDerived(ctx context.Context, req *mcp.ReadResourceRequest, productID, chapterName string, encode resourceEncoder, derive func(*browser.ChapterContentResponse) (any, error)) (*mcp.ReadResourceResult, error) {
+	fetchWith := func(get func() (*browser.ChapterContentResponse, error)) func() (any, error) {
+		return func() (any, error) {
+			chapter, err := get()
+			if err != nil {
+				return nil, chapterLookupError(err)
+			}
+			return derive(linkChapterImages(chapter))
+		}
+	}
+	return s.readResource(ctx, req.Params.URI, fetchWith(func() (*browser.ChapterContentResponse, error) {
+		return s.getChapterContent(ctx, req.Session, productID, chapterName)
+	}), fetchWith(func() (*browser.ChapterContentResponse, error) {
+		return s.contentStore.StaleBookChapterContent(productID, chapterName)
+	}), s.chapterEncoder(ctx, req.Params.URI, encode), "get_chapter_section", "product_id", productID, "chapter_name", chapterName)
+}
+
+// buildChapterSections summarizes the sections of a chapter.
+func buildChapterSections(chapter *browser.ChapterContentResponse) *ChapterSectionsResult {
+	result := &ChapterSectionsResult{
+		BookID:       chapter.BookID,
+		ChapterName:  chapter.ChapterName,
+		ChapterTitle: chapter.ChapterTitle,
+		Sections:     make([]ChapterSectionSummary, 0, len(chapter.Content.Sections)),
+		Metadata:     chapter.Metadata,
+	}
+	for i, section := range chapter.Content.Sections {
+		words := htmlparse.CountWordsFromSections([]htmlparse.ContentSection{section})
+		result.TotalWords += words
+		result.Sections = append(result.Sections, ChapterSectionSummary{
+			Index:     i,
+			Heading:   strings.TrimSpace(section.Heading.Text),
+			Level:     section.Heading.Level,
+			ID:        section.Heading.ID,
+			WordCount: words,
+			URI:       chapterSectionURI(chapter.BookID, chapter.ChapterName, i),
+		})
+	}
+	return result
+}
+
+// selectChapterSection returns the section matching selector, which is either
+// a zero-based index or a heading ID.
+func selectChapterSection(chapter *browser.ChapterContentResponse, selector string) (*ChapterSectionResult, error) {
+	sections := chapter.Content.Sections
+	index := -1
+	if n, err := strconv.Atoi(selector); err == nil {
+		if n < 0 || n >= len(sections) {
+			return nil, &resourceParamError{fmt.Sprintf("section index %d out of range: chapter has %d sections (0-%d)", n, len(sections), len(sections)-1)}
+		}
+		index = n
+	} else {
+		for i, section := range sections {
+			if section.Heading.ID == selector {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, &resourceParamError{fmt.Sprintf("section %q not found: use a section index or a heading id from %s/sections",
+				selector, mcputil.BookChapterURI(chapter.BookID, chapter.ChapterName))}
+		}
+	}
+
+	result := &ChapterSectionResult{
+		BookID:        chapter.BookID,
+		ChapterName:   chapter.ChapterName,
+		ChapterTitle:  chapter.ChapterTitle,
+		Index:         index,
+		TotalSections: len(sections),
+		Section:       sections[index],
+		SourceURL:     chapter.SourceURL,
+		Metadata:      chapter.Metadata,
+		chapter:       chapter,
+	}
+	if index > 0 {
+		result.PrevURI = chapterSectionURI(chapter.BookID, chapter.ChapterName, index-1)
+	}
+	if index < len(sections)-1 {
+		result.NextURI = chapterSectionURI(chapter.BookID, chapter.ChapterName, index+1)
+	}
+	return result, nil
+}
+
+// encodeSectionMarkdown renders a *ChapterSectionResult as text/markdown.
+func encodeSectionMarkdown(uri string, data any) (*mcp.ReadResourceResult, error) {
+	section, ok := data.(*ChapterSectionResult)
+	if !ok || section == nil || section.chapter == nil {
+		return nil, fmt.Errorf("unexpected section data type %T", data)
+	}
+	single := *section.chapter
+	single.Content.Sections = []htmlparse.ContentSection{section.Section}
+
+	var b strings.Builder
+	b.WriteString(formatChapterMarkdown(&single))
+	fmt.Fprintf(&b, "\n*Section %d of %d.", section.Index+1, section.TotalSections)
+	if section.PrevURI != "" {
+		fmt.Fprintf(&b, " Previous: %s", section.PrevURI)
+	}
+	if section.NextURI != "" {
+		fmt.Fprintf(&b, " Next: %s", section.NextURI)
+	}
+	b.WriteString("*\n")
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: "text/markdown",
+			Text:     b.String(),
+		}},
+	}, nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+func newSectionedChapter() *browser.ChapterContentResponse {
+	para := func(text string) any { return htmlparse.ParagraphElement{Type: "paragraph", Text: text} }
+	return &browser.ChapterContentResponse{
+		BookID:       "123",
+		ChapterName:  "ch01",
+		ChapterTitle: "Basics",
+		Content: htmlparse.ParsedChapterContent{
+			Sections: []htmlparse.ContentSection{
+				{Heading: htmlparse.ContentHeading{Level: 1, Text: "Basics"}, Content: []any{para("one two three")}},
+				{Heading: htmlparse.ContentHeading{Level: 2, Text: "Setup", ID: "setup"}, Content: []any{para("four five")}},
+				{Heading: htmlparse.ContentHeading{Level: 2, Text: "Usage", ID: "usage"}, Content: []any{para("six")}},
+			},
+		},
+	}
+}
+
+func readResourceText(t *testing.T, session *mcp.ClientSession, uri string) *mcp.ResourceContents {
+	t.Helper()
+	result, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: uri})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	return result.Contents[0]
+}
+
+func TestChapterSectionsResource(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{chapter: newSectionedChapter()})
+	session := connectTestSession(t, srv, nil)
+
+	var got ChapterSectionsResult
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-chapter/123/ch01/sections").Text), &got))
+
+	require.Len(t, got.Sections, 3)
+	assert.Equal(t, 6, got.TotalWords)
+	assert.Equal(t, ChapterSectionSummary{
+		Index: 1, Heading: "Setup", Level: 2, ID: "setup", WordCount: 2,
+		URI: "oreilly://book-chapter/123/ch01/section/1",
+	}, got.Sections[1])
+}
+
+func TestChapterSectionResource(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{chapter: newSectionedChapter()})
+	session := connectTestSession(t, srv, nil)
+
+	t.Run("by index", func(t *testing.T) {
+		var got ChapterSectionResult
+		require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-chapter/123/ch01/section/1").Text), &got))
+		assert.Equal(t, "Setup", got.Section.Heading.Text)
+		assert.Equal(t, 3, got.TotalSections)
+		assert.Equal(t, "oreilly://book-chapter/123/ch01/section/0", got.PrevURI)
+		assert.Equal(t, "oreilly://book-chapter/123/ch01/section/2", got.NextURI)
+	})
+
+	t.Run("by heading id", func(t *testing.T) {
+		var got ChapterSectionResult
+		require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-chapter/123/ch01/section/usage").Text), &got))
+		assert.Equal(t, 2, got.Index)
+		assert.Empty(t, got.NextURI)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		content := readResourceText(t, session, "oreilly://book-chapter/123/ch01/section/1?format=markdown")
+		assert.Equal(t, "text/markdown", content.MIMEType)
+		assert.Contains(t, content.Text, "## Setup\n\nfour five")
+		assert.NotContains(t, content.Text, "six")
+		assert.Contains(t, content.Text, "Section 2 of 3.")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		content := readResourceText(t, session, "oreilly://book-chapter/123/ch01/section/9")
+		assert.Contains(t, content.Text, "section index 9 out of range: chapter has 3 sections")
+	})
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/citation"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/history"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// citationConcurrency is the number of book details fetched at once for a citation export.
+const citationConcurrency = 4
+
+// citedSource is one work to cite, as known before its book details are fetched.
+type citedSource struct {
+	Title       string
+	Authors     []string
+	URL         string
+	ProductID   string // "" when the source does not point to O'Reilly content
+	ContentType string // mcputil.WebContent*; "" when unknown
+}
+
+// GetBookCitationResource handles oreilly://book-citation/{product_id}{?style} requests.
+func (s *Server) GetBookCitationResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	productID := mcputil.ExtractProductIDFromURI(uri)
+	if productID == "" {
+		return paramErrorResult(uri, "product_id not found in URI"), nil
+	}
+	style, err := citation.ParseStyle(mcputil.ExtractQueryParam(uri, "style"))
+	if err != nil {
+		return paramErrorResult(uri, err.Error()), nil
+	}
+	return s.readResource(ctx, uri, func() (any, error) {
+		client := s.getBrowserClient()
+		details, err := client.GetBookDetails(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		return &citedBook{details: details, credits: s.bookCredits(ctx, client, productID, details)}, nil
+	}, func() (any, error) {
+		details, err := s.contentStore.StaleBookDetails(productID)
+		if err != nil {
+			return nil, err
+		}
+		return &citedBook{details: details}, nil // offline: without authors and publisher
+	}, citationEncoder(productID, style), "get_book_citation", "product_id", productID)
+}
+
+// citedBook is a book with the credits looked up for citing it.
+type citedBook struct {
+	details *browser.BookDetailResponse
+	credits *browser.BookCredits // nil when unknown
+}
+
+// bookCredits looks up the authors and publisher of a book. Failures only leave them unknown.
+func (s *Server) bookCredits(ctx context.Context, client browser.Client, productID string, details *browser.BookDetailResponse) *browser.BookCredits {
+	credits, err := client.GetBookCredits(ctx, cmp.Or(details.Identifier, productID), details.Title)
+	if err != nil {
+		slog.Warn("書籍の著者情報の取得に失敗しました", "product_id", productID, "error", err)
+		return nil
+	}
+	return credits
+}
+
+// citationEncoder renders a *citedBook as a citation in style.
+func citationEncoder(productID, style string) resourceEncoder {
+	return func(uri string, data any) (*mcp.ReadResourceResult, error) {
+		book, ok := data.(*citedBook)
+		if !ok || book == nil {
+			return nil, fmt.Errorf("unexpected book citation type %T", data)
+		}
+		text, err := citation.Render(style, []citation.Work{bookWork(productID, book.details, book.credits)})
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      uri,
+				MIMEType: citation.MIMEType(style),
+				Text:     text,
+			}},
+		}, nil
+	}
+}
+
+// ExportCitationsHandler handles the oreilly_export_citations tool.
+// 回答またはリサーチ履歴エントリの出典をまとめて、指定スタイルの引用として返します。
+func (s *Server) ExportCitationsHandler(ctx context.Context, _ *mcp.CallToolRequest, args ExportCitationsArgs) (*mcp.CallToolResult, *ExportCitationsResult, error) {
+	client := s.getBrowserClient()
+	if client == nil {
+		return newToolResultError("O'Reilly セッションが認証されていません。" +
+			"oreilly_reauthenticate ツールを呼び出してログインしてください。"), nil, nil
+	}
+	style, err := citation.ParseStyle(args.Style)
+	if err != nil {
+		return newToolResultError(err.Error()), nil, nil
+	}
+	if (args.QuestionID == "") == (args.HistoryID == "") {
+		return newToolResultError("Specify exactly one of question_id or history_id."), nil, nil
+	}
+
+	var sources []citedSource
+	if args.HistoryID != "" {
+		sources, err = s.historySources(ctx, args.HistoryID)
+	} else {
+		sources, err = s.answerSources(ctx, args.QuestionID)
+	}
+	if err != nil {
+		var paramErr *resourceParamError
+		if errors.As(err, &paramErr) {
+			return newToolResultError(paramErr.msg), nil, nil
+		}
+		return newToolResultError(errH.Sanitize(err, "operation", "export_citations", "question_id", args.QuestionID, "history_id", args.HistoryID)), nil, nil
+	}
+	if len(sources) == 0 {
+		return newToolResultError("There are no sources to cite."), nil, nil
+	}
+
+	works, incomplete := s.citedWorks(ctx, sources)
+	text, err := citation.Render(style, works)
+	if err != nil {
+		return newToolResultError(errH.Sanitize(err, "operation", "render_citations")), nil, nil
+	}
+	slog.Info("引用をエクスポートしました", "style", style, "count", len(works), "incomplete", len(incomplete))
+
+	result := &ExportCitationsResult{
+		Style:      style,
+		MIMEType:   citation.MIMEType(style),
+		Count:      len(works),
+		Citations:  text,
+		Incomplete: incomplete,
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, result, nil
+}
+
+// answerSources returns the sources of an answer, one per product.
+func (s *Server) answerSources(ctx context.Context, questionID string) ([]citedSource, error) {
+	answer, err := s.getBrowserClient().GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	var sources []citedSource
+	seen := map[string]bool{}
+	for _, src := range answer.MisoResponse.Data.Sources {
+		cited := citedSource{Title: src.Title, Authors: src.Authors, URL: src.URL}
+		key := src.URL
+		if r, ok := mcputil.ResolveWebURL(src.URL); ok {
+			cited.ProductID, cited.ContentType = r.ProductID, r.ContentType
+			key = r.ProductID // several chapters of one book are one citation
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		sources = append(sources, cited)
+	}
+	return sources, nil
+}
+
+// historySources returns the works behind a research history entry: the top
+// results of a search, or the sources of a question's answer.
+func (s *Server) historySources(ctx context.Context, historyID string) ([]citedSource, error) {
+	if s.historyManager == nil {
+		return nil, &resourceParamError{"research history is not enabled"}
+	}
+	entry := s.historyManager.GetByID(historyID)
+	if entry == nil {
+		return nil, &resourceParamError{fmt.Sprintf("history entry %q not found. Check orm-mcp://history/recent for IDs.", historyID)}
+	}
+	if entry.Type == history.EntryTypeQuestion {
+		questionID, _ := entry.Parameters["question_id"].(string)
+		if questionID == "" {
+			return nil, &resourceParamError{fmt.Sprintf("history entry %q has no question_id to reload its sources from.", historyID)}
+		}
+		return s.answerSources(ctx, questionID)
+	}
+	var sources []citedSource
+	for _, r := range entry.ResultSummary.TopResults {
+		cited := citedSource{Title: r.Title, ProductID: r.ProductID}
+		if r.Author != "" {
+			cited.Authors = []string{r.Author}
+		}
+		sources = append(sources, cited)
+	}
+	return sources, nil
+}
+
+// citedWorks fetches the details of the books among sources. Sources whose
+// details cannot be fetched are cited from what the source itself says, and
+// their titles are returned as incomplete. Works are in the order of sources.
+func (s *Server) citedWorks(ctx context.Context, sources []citedSource) ([]citation.Work, []string) {
+	client := s.getBrowserClient()
+	works := make([]citation.Work, len(sources))
+	failed := make([]bool, len(sources))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, citationConcurrency)
+	for i, src := range sources {
+		works[i] = sourceWork(src)
+		if src.ProductID == "" || src.ContentType != "" && src.ContentType != mcputil.WebContentBook {
+			continue // only books have details
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			details, err := client.GetBookDetails(ctx, src.ProductID)
+			if err != nil {
+				slog.Warn("引用のための書籍詳細の取得に失敗しました", "product_id", src.ProductID, "error", err)
+				failed[i] = true
+				return
+			}
+			work := bookWork(src.ProductID, details, s.bookCredits(ctx, client, src.ProductID, details))
+			if len(work.Authors) == 0 {
+				work.Authors = src.Authors
+			}
+			works[i] = work
+		})
+	}
+	wg.Wait()
+
+	var incomplete []string
+	for i, src := range sources {
+		if failed[i] {
+			incomplete = append(incomplete, cmp.Or(src.Title, src.ProductID))
+		}
+	}
+	return works, incomplete
+}
+
+// bookWork converts book details and their credits, which may be nil, into a work to cite.
+func bookWork(productID string, details *browser.BookDetailResponse, credits *browser.BookCredits) citation.Work {
+	id := cmp.Or(details.Identifier, productID)
+	work := citation.Work{
+		ID:       id,
+		Type:     citation.TypeBook,
+		Title:    details.Title,
+		Edition:  details.Edition,
+		Date:     details.PublicationDate,
+		ISBN:     details.ISBN,
+		URL:      mcputil.LibraryURL(id),
+		Language: details.Language,
+	}
+	if credits != nil {
+		work.Authors, work.Publisher = credits.Authors, credits.Publisher
+	}
+	if strings.Contains(details.ContentFormat, mcputil.WebContentVideo) {
+		work.Type = citation.TypeVideo
+	}
+	return work
+}
+
+// sourceWork converts a source into a work using only what the source says.
+func sourceWork(src citedSource) citation.Work {
+	work := citation.Work{ID: src.ProductID, Type: citation.TypeWebpage, Title: src.Title, Authors: src.Authors, URL: src.URL}
+	switch src.ContentType {
+	case mcputil.WebContentBook:
+		work.Type = citation.TypeBook
+	case mcputil.WebContentVideo, mcputil.WebContentCourse:
+		work.Type = citation.TypeVideo
+	}
+	if work.URL == "" && src.ProductID != "" {
+		work.URL = mcputil.LibraryURL(src.ProductID)
+	}
+	return work
+}
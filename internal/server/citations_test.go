@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+var learningGoDetails = &browser.BookDetailResponse{
+	Identifier:      "9781492077206",
+	ISBN:            "9781492077206",
+	Title:           "Learning Go, 2nd Edition",
+	PublicationDate: "2024-01-09",
+	Edition:         2,
+}
+
+var learningGoCredits = map[string]*browser.BookCredits{
+	"9781492077206": {Authors: []string{"Jon Bodner"}, Publisher: "O'Reilly Media, Inc."},
+}
+
+func callExportCitations(t *testing.T, session *mcp.ClientSession, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "oreilly_export_citations", Arguments: args})
+	require.NoError(t, err)
+	return res
+}
+
+func TestBookCitationResource(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookDetails: learningGoDetails, credits: learningGoCredits})
+	session := connectTestSession(t, srv, nil)
+
+	apa := readResourceText(t, session, "oreilly://book-citation/9781492077206")
+	assert.Equal(t, "text/markdown", apa.MIMEType)
+	assert.Equal(t, "Bodner, J. (2024). *Learning Go* (2nd ed.). O'Reilly Media, Inc. https://learning.oreilly.com/library/view/-/9781492077206/", apa.Text)
+
+	bib := readResourceText(t, session, "oreilly://book-citation/9781492077206?style=bibtex")
+	assert.Equal(t, "application/x-bibtex", bib.MIMEType)
+	assert.Contains(t, bib.Text, "@book{bodner2024learning,")
+
+	csl := readResourceText(t, session, "oreilly://book-citation/9781492077206?style=csl-json")
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(csl.Text), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "Learning Go", items[0]["title"])
+}
+
+func TestBookCitationResource_WithoutCredits(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookDetails: learningGoDetails})
+	session := connectTestSession(t, srv, nil)
+
+	apa := readResourceText(t, session, "oreilly://book-citation/9781492077206")
+	assert.Equal(t, "*Learning Go* (2nd ed.). (2024). O'Reilly Media. https://learning.oreilly.com/library/view/-/9781492077206/", apa.Text)
+}
+
+func TestBookCitationResource_UnknownStyle(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookDetails: learningGoDetails})
+	session := connectTestSession(t, srv, nil)
+
+	got := readResourceText(t, session, "oreilly://book-citation/9781492077206?style=mla")
+	assert.Contains(t, got.Text, `unknown citation style \"mla\"`)
+}
+
+func TestExportCitationsHandler_AnswerSources(t *testing.T) {
+	mock := &mockBrowserClient{
+		askAnswer: &browser.AnswerResponse{QuestionID: "q1", IsFinished: true, MisoResponse: browser.MisoResponse{Data: browser.AnswerData{
+			Sources: []browser.AnswerSource{
+				{Title: "Learning Go: Chapter 1", URL: "https://learning.oreilly.com/library/view/learning-go-2nd/9781492077206/ch01.html"},
+				{Title: "Learning Go: Chapter 7", URL: "https://learning.oreilly.com/library/view/learning-go-2nd/9781492077206/ch07.html"},
+				{Title: "Go Concurrency Basics", URL: "https://learning.oreilly.com/videos/go-concurrency/9780135000000/", Authors: []string{"Ann Video"}},
+				{Title: "Missing Book", URL: "https://learning.oreilly.com/library/view/-/9781000000000/", Authors: []string{"Bea Missing"}},
+			},
+		}}},
+		detailsByID: map[string]*browser.BookDetailResponse{"9781492077206": learningGoDetails},
+		credits:     learningGoCredits,
+		bookErr:     errors.New("not found"),
+	}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	res := callExportCitations(t, session, map[string]any{"question_id": "q1", "style": "chicago"})
+	require.False(t, res.IsError, "unexpected error: %v", res.Content)
+	assert.ElementsMatch(t, []string{"9781492077206", "9781000000000"}, mock.detailsReqs, "each book is fetched once and videos are not fetched")
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "chicago", structured["style"])
+	assert.Equal(t, 3.0, structured["count"])
+	assert.Equal(t, []any{"Missing Book"}, structured["incomplete"])
+
+	text := res.Content[0].(*mcp.TextContent).Text
+	assert.Equal(t, structured["citations"], text)
+	assert.Contains(t, text, "Bodner, Jon. *Learning Go*. 2nd ed. O'Reilly Media, Inc., 2024.")
+	assert.Contains(t, text, "Missing, Bea. *Missing Book*. O'Reilly Media, n.d.", "the source itself is cited when details fail")
+	assert.Contains(t, text, "Video, Ann. *Go Concurrency Basics*. Video.")
+}
+
+func TestExportCitationsHandler_HistoryEntries(t *testing.T) {
+	mock := &mockBrowserClient{
+		askAnswer:     answerWithSource("9781492077206", "Use goroutines.", "Learning Go"),
+		bookDetails:   learningGoDetails,
+		credits:       learningGoCredits,
+		searchResults: []map[string]any{{"title": "Learning Go", "product_id": "9781492077206"}},
+	}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	callAsk(t, session, map[string]any{"question": "How do I run code concurrently in Go?"})
+	_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "oreilly_search_content", Arguments: map[string]any{"query": "golang"}})
+	require.NoError(t, err)
+
+	recent := srv.historyManager.GetRecent(2)
+	require.Len(t, recent, 2)
+	for _, entry := range recent {
+		res := callExportCitations(t, session, map[string]any{"history_id": entry.ID, "style": "bibtex"})
+		require.False(t, res.IsError, "%s entry: %v", entry.Type, res.Content)
+		assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "author    = {Bodner, Jon},", entry.Type)
+	}
+}
+
+func TestExportCitationsHandler_InvalidArgs(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+	session := connectTestSession(t, srv, nil)
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"neither", map[string]any{}, "exactly one of question_id or history_id"},
+		{"both", map[string]any{"question_id": "q1", "history_id": "h1"}, "exactly one of question_id or history_id"},
+		{"unknown style", map[string]any{"question_id": "q1", "style": "mla"}, "unknown citation style"},
+		{"unknown history", map[string]any{"history_id": "nope"}, `history entry "nope" not found`},
+	}
+	for _, tt := range tests {
+		res := callExportCitations(t, session, tt.args)
+		require.True(t, res.IsError, tt.name)
+		assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, tt.want, tt.name)
+	}
+}
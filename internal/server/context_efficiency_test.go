@@ -25,6 +25,10 @@ func TestToolDescriptionSizes(t *testing.T) {
 	}{
 		{"descSearchContent", descSearchContent},
 		{"descAskQuestion", descAskQuestion},
+		{"descSearchLocal", descSearchLocal},
+		{"descExportBook", descExportBook},
+		{"descResolve", descResolve},
+		{"descExportCitations", descExportCitations},
 	}
 
 	for _, tt := range tests {
@@ -60,6 +64,10 @@ func TestContextEfficiencyReport(t *testing.T) {
 	toolDescs := []namedDesc{
 		{"oreilly_search_content", descSearchContent},
 		{"oreilly_ask_question", descAskQuestion},
+		{"oreilly_search_local", descSearchLocal},
+		{"oreilly_export_book", descExportBook},
+		{"oreilly_resolve", descResolve},
+		{"oreilly_export_citations", descExportCitations},
 	}
 
 	totalToolChars := 0
@@ -96,10 +104,13 @@ func TestContextEfficiencyReport(t *testing.T) {
 		{"book-details-tmpl", descTmplBookDetails},
 		{"book-toc-tmpl", descTmplBookTOC},
 		{"book-chapter-tmpl", descTmplBookChapter},
+		{"book-search-tmpl", descTmplBookSearch},
+		{"book-citation-tmpl", descTmplBookCitation},
 		{"answer-tmpl", descTmplAnswer},
 		{"history/search-tmpl", descTmplHistSearch},
 		{"history/{id}-tmpl", descTmplHistDetail},
 		{"history/{id}/full-tmpl", descTmplHistFull},
+		{"threads/{id}-tmpl", descTmplThread},
 	}
 
 	totalTemplateChars := 0
@@ -236,7 +247,7 @@ func TestLightweightResponseSize(t *testing.T) {
 	srv := &Server{}
 	results := syntheticSearchResults(25)
 
-	_, structured := srv.buildLightweightResponse(results, "req_test123", "/tmp/cache/test.md", 0, 100)
+	_, structured := srv.buildLightweightResponse(results, "req_test123", "/tmp/cache/test.md", 0, len(results), 100)
 
 	data, err := json.Marshal(structured)
 	if err != nil {
@@ -22,26 +22,56 @@ Response: Markdown answer, sources, related resources, question_id (use with ore
 
 IMPORTANT: Cite sources provided in the response.`
 
+const descSearchLocal = `Search passages inside chapters already fetched (local BM25 index, works offline). Finds sections, not titles.
+
+Example: "idempotency key" / "冪等性". Use oreilly_search_content to discover new books.
+
+Response: sections with snippets and section/chapter URIs. IMPORTANT: Cite title, author(s), and O'Reilly Media.`
+
+const descExportBook = `Export a whole book to a local directory for offline study: index.md, one Markdown file per chapter, images under images/.
+
+Resumes interrupted exports and reports progress. Use product_id from oreilly_search_content.
+
+IMPORTANT: Cite title, author(s), and O'Reilly Media.`
+
+const descResolve = `Resolve a pasted O'Reilly link or identifier to server resources: learning.oreilly.com URL, ISBN-10/13 (dashes OK), OURN (urn:orm:book:...) or product_id.
+
+Response: canonical product_id, content type, title, and book-details/TOC/chapter URIs (books only; chapter when the URL points inside a book).`
+
+const descExportCitations = `Export formatted citations for every source of an answer (question_id) or research history entry (history_id).
+
+Styles: apa (default), chicago, bibtex, csl-json. Books include authors, publisher, edition and year.
+
+Single book: oreilly://book-citation/{product_id}?style=bibtex.`
+
 // Resource descriptions.
 
 const (
-	descResBookDetails = "Get book info (title, ISBN, description, publication date). Cite sources when referencing."
-	descResBookTOC     = "Get table of contents with chapter names and structure. Cite book title, author(s), O'Reilly Media."
-	descResBookChapter = "Get full chapter text. CRITICAL: Cite book title, author(s), chapter title, O'Reilly Media."
-	descResAnswer      = "Retrieve previously generated answer by question_id. Cite sources when referencing."
-	descResHistRecent  = "Get recent 20 research entries. Use to review past searches and questions."
+	descResBookDetails   = "Get book info (title, authors, publisher, edition, ISBN, description, publication date). Cite sources when referencing."
+	descResBookTOC       = "Get table of contents with chapter names and structure. Cite book title, author(s), O'Reilly Media."
+	descResBookChapter   = "Get full chapter text. CRITICAL: Cite book title, author(s), chapter title, O'Reilly Media."
+	descResBookChapterMD = "Get full chapter text as Markdown (fewer tokens than JSON). CRITICAL: Cite book title, author(s), chapter title, O'Reilly Media."
+	descResAnswer        = "Retrieve previously generated answer by question_id. Cite sources when referencing."
+	descResHistRecent    = "Get recent 20 research entries. Use to review past searches and questions."
 )
 
 // Resource template descriptions.
 
 const (
-	descTmplBookDetails = "Use product_id from oreilly_search_content to get book details."
-	descTmplBookTOC     = "Use product_id from oreilly_search_content to get table of contents."
-	descTmplBookChapter = "Use product_id and chapter_name to get chapter content."
-	descTmplAnswer      = "Use question_id from oreilly_ask_question to retrieve the answer."
-	descTmplHistSearch  = "Search past research by keyword or type (search/question)."
-	descTmplHistDetail  = "Get details of a specific research entry by ID."
-	descTmplHistFull    = "Get the full cached response for a research entry from the saved Markdown file."
+	descTmplBookDetails     = "Use product_id from oreilly_search_content to get book details. Add ?refresh=true to bypass the cache."
+	descTmplBookTOC         = "Use product_id from oreilly_search_content to get table of contents. Add ?view=tree for the nested hierarchy with chapter URIs, ?refresh=true to bypass the cache."
+	descTmplBookChapter     = "Use product_id and chapter_name (href like ch03, number like 3, or title phrase) to get chapter content. Add ?format=markdown for Markdown, ?images=inline to embed images."
+	descTmplBookChapterMD   = "Use product_id and chapter_name to get chapter content as Markdown."
+	descTmplChapterSections = "List a chapter's sections (heading, level, word count, URI). Use before reading long chapters."
+	descTmplChapterSection  = "Get one chapter section by zero-based index or heading id. Add ?format=markdown for Markdown."
+	descTmplBookSearch      = "Search inside one book: ?q=terms returns ranked sections with heading path, excerpt and section URI. Fetches chapters on demand; use instead of reading whole chapters."
+	descTmplBookCitation    = "Cite one book: ?style=apa (default), chicago, bibtex or csl-json. Includes authors, publisher, edition and year."
+	descTmplBookImage       = "Get a book image (figure, diagram) as a blob. Use the image uri from chapter content."
+	descTmplAnswer          = "Use question_id from oreilly_ask_question to retrieve the answer."
+	descTmplHistSearch      = "Search past research by keyword or type (search/question)."
+	descTmplHistDetail      = "Get details of a specific research entry by ID."
+	descTmplThread          = "Get an O'Reilly Answers conversation by thread_id: every question, answer and source in order."
+	descTmplHistFull        = "Get the full cached response for a research entry from the saved Markdown file."
 )
 
 // Prompt descriptions.
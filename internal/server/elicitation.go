@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/elicitation"
+)
+
+// How to run a long synchronous question, as chosen by the user.
+const (
+	questionWait       = "wait"
+	questionBackground = "background"
+	questionCancel     = "cancel"
+)
+
+// getChapterContent fetches a chapter. When chapterName matches several
+// chapters and the client supports elicitation, the user picks the chapter
+// from the candidates instead of the request failing.
+func (s *Server) getChapterContent(ctx context.Context, session *mcp.ServerSession, productID, chapterName string) (*browser.ChapterContentResponse, error) {
+	chapter, err := s.getBrowserClient().GetBookChapterContent(ctx, productID, chapterName)
+	var notFound *browser.ChapterNotFoundError
+	if err == nil || !errors.As(err, &notFound) || !notFound.Ambiguous {
+		return chapter, err
+	}
+
+	href, ok := s.chooseChapter(ctx, session, notFound)
+	if !ok {
+		return nil, err
+	}
+	slog.Info("ユーザーが選択したチャプターを取得します", "product_id", productID, "chapter_name", chapterName, "href", href)
+	return s.getBrowserClient().GetBookChapterContent(ctx, productID, href)
+}
+
+// chooseChapter asks the user which of the candidate chapters was meant.
+// ok is false when the client cannot be asked or the user made no choice.
+func (s *Server) chooseChapter(ctx context.Context, session *mcp.ServerSession, notFound *browser.ChapterNotFoundError) (string, bool) {
+	options := make([]elicitation.Option, 0, len(notFound.Candidates))
+	for _, c := range notFound.Candidates {
+		if c.Href != "" {
+			options = append(options, elicitation.Option{Value: c.Href, Title: c.Title})
+		}
+	}
+	message := fmt.Sprintf("%q matches several chapters of book %s. Which one do you want to read?", notFound.ChapterName, notFound.ProductID)
+	href, ok, err := s.elicitationManager.Choose(ctx, session, message, options)
+	if err != nil {
+		slog.Warn("チャプター選択の問い合わせに失敗しました", "chapter_name", notFound.ChapterName, "error", err)
+	}
+	return href, ok
+}
+
+// confirmLongQuestion asks the user how to run a synchronous question whose
+// caller explicitly asked to wait longer than the configured threshold.
+// requested is the max_wait_time_seconds given by the caller, 0 when left to
+// the default, which is never confirmed. It returns questionWait unless the
+// user chose to run it in the background or not to ask it at all.
+func (s *Server) confirmLongQuestion(ctx context.Context, session *mcp.ServerSession, question string, requested time.Duration) string {
+	threshold := s.config.Elicitation.ConfirmLongWaits
+	if threshold <= 0 || requested <= threshold {
+		return questionWait
+	}
+
+	message := fmt.Sprintf("Answering %q may take up to %s. How should it run?", question, requested)
+	choice, ok, err := s.elicitationManager.Choose(ctx, session, message, []elicitation.Option{
+		{Value: questionWait, Title: fmt.Sprintf("Wait for the answer (up to %s)", requested)},
+		{Value: questionBackground, Title: "Run in the background and read the answer resource later"},
+		{Value: questionCancel, Title: "Don't ask this question"},
+	})
+	if err != nil {
+		slog.Warn("質問の実行方法の問い合わせに失敗しました", "error", err)
+	}
+	if !ok {
+		return questionWait
+	}
+	return choice
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/elicitation"
+)
+
+// newElicitingServer returns a test server with elicitation enabled.
+func newElicitingServer(t *testing.T, mock *mockBrowserClient, longWait time.Duration) *Server {
+	t.Helper()
+	srv := newTestServer(t, mock)
+	srv.config.Elicitation = config.ElicitationOpts{Enabled: true, ConfirmLongWaits: longWait}
+	srv.elicitationManager = elicitation.NewManager(srv.config)
+	return srv
+}
+
+// answerElicitation returns client options whose elicitation handler replies with
+// action and, when accepting, the given choice. Received requests are sent to got.
+func answerElicitation(action, choice string, got chan<- *mcp.ElicitParams) *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			if got != nil {
+				got <- req.Params
+			}
+			if action != elicitation.ActionAccept {
+				return &mcp.ElicitResult{Action: action}, nil
+			}
+			return &mcp.ElicitResult{Action: action, Content: map[string]any{"choice": choice}}, nil
+		},
+	}
+}
+
+func ambiguousChapterMock() *mockBrowserClient {
+	return &mockBrowserClient{
+		chapter: newSectionedChapter(),
+		chapterErrs: map[string]error{
+			"processing": &browser.ChapterNotFoundError{
+				ProductID:   "123",
+				ChapterName: "processing",
+				Ambiguous:   true,
+				Candidates: []browser.ChapterCandidate{
+					{Title: "10. Batch Processing", Href: "ch10.html", Score: 0.7},
+					{Title: "11. Stream Processing", Href: "ch11.html", Score: 0.7},
+				},
+			},
+		},
+	}
+}
+
+func TestBookChapterResource_ElicitsAmbiguousChapter(t *testing.T) {
+	mock := ambiguousChapterMock()
+	srv := newElicitingServer(t, mock, 0)
+	got := make(chan *mcp.ElicitParams, 1)
+	session := connectTestSession(t, srv, answerElicitation(elicitation.ActionAccept, "ch11.html", got))
+
+	var chapter browser.ChapterContentResponse
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-chapter/123/processing").Text), &chapter))
+	assert.Equal(t, "Basics", chapter.ChapterTitle)
+	assert.Equal(t, []string{"processing", "ch11.html"}, mock.chapterReqs)
+
+	params := <-got
+	assert.Contains(t, params.Message, `"processing" matches several chapters`)
+	schema, _ := json.Marshal(params.RequestedSchema)
+	assert.Contains(t, string(schema), "11. Stream Processing")
+}
+
+func TestBookChapterResource_DeclinedElicitationListsCandidates(t *testing.T) {
+	mock := ambiguousChapterMock()
+	srv := newElicitingServer(t, mock, 0)
+	session := connectTestSession(t, srv, answerElicitation(elicitation.ActionDecline, "", nil))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-chapter/123/processing/sections").Text), &got))
+	assert.Contains(t, got["error"], "oreilly://book-chapter/123/ch10.html")
+	assert.Equal(t, []string{"processing"}, mock.chapterReqs)
+}
+
+func TestAskQuestionHandler_LongWaitElicitation(t *testing.T) {
+	newMock := func() *mockBrowserClient {
+		return &mockBrowserClient{
+			submitResp: &browser.QuestionResponse{QuestionID: "q-1"},
+			askAnswer: &browser.AnswerResponse{
+				QuestionID:   "q-1",
+				IsFinished:   true,
+				MisoResponse: browser.MisoResponse{Data: browser.AnswerData{Answer: "done"}},
+			},
+		}
+	}
+	askFor := func(t *testing.T, session *mcp.ClientSession, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "oreilly_ask_question",
+			Arguments: args,
+		})
+		require.NoError(t, err)
+		return res
+	}
+	ask := func(t *testing.T, session *mcp.ClientSession) *mcp.CallToolResult {
+		t.Helper()
+		return askFor(t, session, map[string]any{"question": "What is Go?", "max_wait_time_seconds": 300})
+	}
+
+	t.Run("background", func(t *testing.T) {
+		srv := newElicitingServer(t, newMock(), time.Minute)
+		res := ask(t, connectTestSession(t, srv, answerElicitation(elicitation.ActionAccept, questionBackground, nil)))
+		require.False(t, res.IsError)
+		assert.Equal(t, "oreilly://answer/q-1", res.StructuredContent.(map[string]any)["answer_uri"])
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		srv := newElicitingServer(t, newMock(), time.Minute)
+		res := ask(t, connectTestSession(t, srv, answerElicitation(elicitation.ActionAccept, questionCancel, nil)))
+		assert.True(t, res.IsError)
+		assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "not submitted")
+	})
+
+	t.Run("declined waits as before", func(t *testing.T) {
+		srv := newElicitingServer(t, newMock(), time.Minute)
+		res := ask(t, connectTestSession(t, srv, answerElicitation(elicitation.ActionDecline, "", nil)))
+		require.False(t, res.IsError)
+		assert.Equal(t, "done", res.StructuredContent.(map[string]any)["answer"])
+	})
+
+	t.Run("below threshold is not confirmed", func(t *testing.T) {
+		srv := newElicitingServer(t, newMock(), 10*time.Minute)
+		got := make(chan *mcp.ElicitParams, 1)
+		res := ask(t, connectTestSession(t, srv, answerElicitation(elicitation.ActionAccept, questionCancel, got)))
+		require.False(t, res.IsError)
+		assert.Empty(t, got)
+	})
+
+	t.Run("default wait is not confirmed", func(t *testing.T) {
+		srv := newElicitingServer(t, newMock(), time.Minute)
+		got := make(chan *mcp.ElicitParams, 1)
+		res := askFor(t, connectTestSession(t, srv, answerElicitation(elicitation.ActionAccept, questionCancel, got)), map[string]any{"question": "What is Go?"})
+		require.False(t, res.IsError)
+		assert.Equal(t, "done", res.StructuredContent.(map[string]any)["answer"])
+		assert.Empty(t, got, "a call without max_wait_time_seconds does not ask the user")
+	})
+}
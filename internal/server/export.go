@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/export"
+)
+
+// ExportBookHandler handles the oreilly_export_book tool.
+// 出力先を省略した場合は XDG StateHome 配下の書籍ごとのディレクトリに書き出します。
+// 出力先はエクスポートディレクトリ配下に限ります。
+func (s *Server) ExportBookHandler(ctx context.Context, req *mcp.CallToolRequest, args ExportBookArgs) (*mcp.CallToolResult, *export.Result, error) {
+	client := s.getBrowserClient()
+	if client == nil {
+		return newToolResultError("O'Reilly セッションが認証されていません。" +
+			"oreilly_reauthenticate ツールを呼び出してログインしてください。"), nil, nil
+	}
+	if args.ProductID == "" {
+		return newToolResultError("product_id is required."), nil, nil
+	}
+
+	outputDir, err := exportOutputDir(s.config.Export.Dir, args.ProductID, args.OutputDir)
+	if err != nil {
+		return newToolResultError(err.Error()), nil, nil
+	}
+
+	result, err := export.Book(ctx, client, args.ProductID, export.Options{
+		OutputDir:   outputDir,
+		Concurrency: s.config.Export.Concurrency,
+		Force:       args.Force,
+		OnProgress:  newExportProgressNotifier(ctx, req),
+	})
+	if err != nil {
+		if result != nil && ctx.Err() != nil {
+			return newToolResultError(fmt.Sprintf("Export interrupted after %d of %d chapters. Run the tool again to resume.",
+				result.Exported+result.Resumed, result.Chapters)), nil, nil
+		}
+		return newToolResultError(errH.Sanitize(err, "operation", "export_book", "product_id", args.ProductID)), nil, nil
+	}
+	return nil, result, nil
+}
+
+// exportOutputDir returns the directory a tool export writes to: outputDir
+// resolved against base, which it must stay inside of, or a directory per book
+// under base when outputDir is empty. Unlike the CLI, the tool cannot write to
+// arbitrary paths, since a client could otherwise overwrite any directory the
+// server can write to.
+func exportOutputDir(base, productID, outputDir string) (string, error) {
+	if outputDir == "" {
+		outputDir = productID
+	}
+	dir := outputDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(base, dir)
+	}
+	dir = filepath.Clean(dir)
+	rel, err := filepath.Rel(filepath.Clean(base), dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output_dir %q must be a subdirectory of the export directory %s", outputDir, base)
+	}
+	return dir, nil
+}
+
+// newExportProgressNotifier returns a callback that sends notifications/progress
+// after each exported chapter. Returns nil when the client did not send a progress token.
+func newExportProgressNotifier(ctx context.Context, req *mcp.CallToolRequest) export.ProgressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	return func(p export.Progress) {
+		params := &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Message:       fmt.Sprintf("Exported %d/%d: %s", p.Done, p.Total, p.Chapter),
+			Progress:      float64(p.Done),
+			Total:         float64(p.Total),
+		}
+		if err := req.Session.NotifyProgress(ctx, params); err != nil {
+			slog.Debug("進捗通知の送信に失敗しました", "error", err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+)
+
+func TestExportBookHandler(t *testing.T) {
+	mock := newImageMock()
+	mock.bookDetails = &browser.BookDetailResponse{Identifier: "123", Title: "Illustrated"}
+	mock.toc = &browser.TableOfContentsResponse{
+		BookID: "123",
+		TableOfContents: []browser.TableOfContentsItem{
+			{ID: "ch01", Title: "Chapter 1. Diagrams", Href: "ch01.html"},
+			{ID: "ch01-s1", Title: "Small", Href: "ch01.html#small", Parent: "ch01"},
+		},
+	}
+	srv := newTestServer(t, mock)
+	srv.config.Export = testExportOpts(t)
+
+	var mu sync.Mutex
+	var messages []string
+	session := connectTestSession(t, srv, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, req.Params.Message)
+		},
+	})
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Meta:      mcp.Meta{"progressToken": "export-1"},
+		Name:      "oreilly_export_book",
+		Arguments: map[string]any{"product_id": "123"},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	outputDir := filepath.Join(srv.config.Export.Dir, "123")
+	assert.Equal(t, outputDir, structured["output_dir"], "defaults to a directory per book")
+	assert.EqualValues(t, 1, structured["exported"])
+
+	chapter, err := os.ReadFile(filepath.Join(outputDir, "01-ch01.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(chapter), "![Small](images/assets/small.png)")
+	assert.FileExists(t, filepath.Join(outputDir, "images", "assets", "large.png"))
+	assert.FileExists(t, filepath.Join(outputDir, "index.md"))
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(messages) == 1
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "Exported 1/1: Chapter 1. Diagrams", messages[0])
+}
+
+func TestExportBookHandler_Errors(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{bookErr: assert.AnError})
+	srv.config.Export = testExportOpts(t)
+	session := connectTestSession(t, srv, nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_export_book",
+		Arguments: map[string]any{"product_id": "123", "output_dir": "learning"},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.IsError, "a book whose TOC cannot be fetched is not exported")
+
+	srv.setBrowserClient(nil)
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_export_book",
+		Arguments: map[string]any{"product_id": "123"},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
+
+func TestExportBookHandler_OutputDirOutsideExportDir(t *testing.T) {
+	mock := &mockBrowserClient{bookDetails: &browser.BookDetailResponse{Identifier: "123", Title: "Book"}}
+	srv := newTestServer(t, mock)
+	srv.config.Export = testExportOpts(t)
+	session := connectTestSession(t, srv, nil)
+
+	outside := t.TempDir()
+	for _, dir := range []string{outside, "../elsewhere", "a/../..", "."} {
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "oreilly_export_book",
+			Arguments: map[string]any{"product_id": "123", "output_dir": dir},
+		})
+		require.NoError(t, err)
+		require.True(t, res.IsError, dir)
+		assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "must be a subdirectory of the export directory", dir)
+	}
+	entries, err := os.ReadDir(outside)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is written outside the export directory")
+	assert.NoDirExists(t, filepath.Join(filepath.Dir(srv.config.Export.Dir), "elsewhere"))
+}
+
+func TestExportOutputDir(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "exports")
+	tests := map[string]string{
+		"":                                  filepath.Join(base, "123"),
+		"learning":                          filepath.Join(base, "learning"),
+		"books/../learning":                 filepath.Join(base, "learning"),
+		filepath.Join(base, "abs", "learn"): filepath.Join(base, "abs", "learn"),
+	}
+	for in, want := range tests {
+		got, err := exportOutputDir(base, "123", in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := exportOutputDir(base, "123", base+"-sibling")
+	assert.Error(t, err, "a sibling sharing the prefix is outside")
+}
+
+// testExportOpts exports to a temp directory one chapter at a time, as the mock is not concurrency-safe.
+func testExportOpts(t *testing.T) config.ExportOpts {
+	return config.ExportOpts{Dir: filepath.Join(t.TempDir(), "exports"), Concurrency: 1}
+}
@@ -17,6 +17,10 @@ func formatSearchResultsMarkdown(result *SearchContentResult) string {
 
 	fmt.Fprintf(&b, "## Search Results (%d of %d)\n\n", result.Count, result.TotalResults)
 
+	if result.Summary != "" {
+		fmt.Fprintf(&b, "### Summary\n\n%s\n\n", result.Summary)
+	}
+
 	for i, r := range result.Results {
 		title, _ := r["title"].(string)
 		id, _ := r["id"].(string)
@@ -81,17 +85,24 @@ func formatAskQuestionMarkdown(result *AskQuestionResult) string {
 	var b strings.Builder
 
 	fmt.Fprintf(&b, "## Q: %s\n\n", result.Question)
+	if result.Summary != "" {
+		fmt.Fprintf(&b, "> **Summary:** %s\n\n", result.Summary)
+	}
 	b.WriteString(result.Answer)
 	b.WriteString("\n")
 
 	if len(result.Sources) > 0 {
 		b.WriteString("\n### Sources\n\n")
-		for _, src := range result.Sources {
+		for i, src := range result.Sources {
 			if src.URL != "" {
-				fmt.Fprintf(&b, "- [%s](%s)\n", src.Title, src.URL)
+				fmt.Fprintf(&b, "- [%s](%s)", src.Title, src.URL)
 			} else {
-				fmt.Fprintf(&b, "- %s\n", src.Title)
+				fmt.Fprintf(&b, "- %s", src.Title)
+			}
+			if i < len(result.SourceChecks) {
+				b.WriteString(formatSourceCheck(result.SourceChecks[i]))
 			}
+			b.WriteString("\n")
 		}
 	}
 
@@ -102,5 +113,59 @@ func formatAskQuestionMarkdown(result *AskQuestionResult) string {
 		}
 	}
 
+	if result.ThreadID != "" {
+		fmt.Fprintf(&b, "\n---\nThread: `%s` (pass as thread_id to ask a follow-up; full conversation: %s)\n", result.ThreadID, result.ThreadURI)
+	}
+
+	return b.String()
+}
+
+// formatSourceCheck renders the verification of a source as a list item suffix.
+func formatSourceCheck(check SourceCheck) string {
+	if check.Verified {
+		return fmt.Sprintf(" — verified in \"%s\" (%s)", check.Heading, check.SectionURI)
+	}
+	return fmt.Sprintf(" — not verified: %s", check.Reason)
+}
+
+// formatSearchLocalMarkdown formats local full-text hits as human-readable Markdown.
+func formatSearchLocalMarkdown(result *SearchLocalResult) string {
+	if len(result.Results) == 0 {
+		return fmt.Sprintf("## Local Search Results\n\nNo sections matched. %d chapters of %d books are indexed.", result.Indexed.Chapters, result.Indexed.Books)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Local Search Results (%d of %d)\n\n", result.Count, result.Total)
+
+	for i, hit := range result.Results {
+		fmt.Fprintf(&b, "%d. **%s**\n", i+1, sectionLinkName(hit))
+		if hit.Snippet != "" {
+			fmt.Fprintf(&b, "   > %s\n", hit.Snippet)
+		}
+		fmt.Fprintf(&b, "   - Section: `%s`\n", hit.SectionURI)
+	}
+
+	return b.String()
+}
+
+// formatChapterMarkdown formats chapter content as Markdown with a source footer.
+func formatChapterMarkdown(chapter *browser.ChapterContentResponse) string {
+	var b strings.Builder
+
+	if stale, _ := chapter.Metadata["stale"].(bool); stale {
+		fetchedAt, _ := chapter.Metadata["fetched_at"].(string)
+		fmt.Fprintf(&b, "> **Offline copy**: served from the local content store (fetched at %s). It may be out of date.\n\n", fetchedAt)
+	}
+
+	b.WriteString(chapter.Markdown())
+
+	b.WriteString("\n---\n\n")
+	fmt.Fprintf(&b, "*Source: %s (book ID `%s`), O'Reilly Media", chapter.ChapterTitle, chapter.BookID)
+	if chapter.SourceURL != "" {
+		fmt.Fprintf(&b, " — %s", chapter.SourceURL)
+	}
+	b.WriteString("*\n")
+
 	return b.String()
 }
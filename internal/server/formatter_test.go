@@ -1,6 +1,7 @@
 package server
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -142,3 +143,14 @@ func TestFormatAskQuestionMarkdown(t *testing.T) {
 		assert.NotContains(t, md, "Sources")
 	})
 }
+
+func TestFormatChapterMarkdown_StaleNotice(t *testing.T) {
+	chapter := &browser.ChapterContentResponse{
+		BookID:       "123",
+		ChapterTitle: "Basics",
+		Metadata:     map[string]any{"stale": true, "fetched_at": "2025-01-01T00:00:00Z"},
+	}
+	md := formatChapterMarkdown(chapter)
+	assert.True(t, strings.HasPrefix(md, "> **Offline copy**"))
+	assert.Contains(t, md, "2025-01-01T00:00:00Z")
+}
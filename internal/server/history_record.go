@@ -24,7 +24,8 @@ func (s *Server) saveHistoryEntry(entry history.Entry) {
 
 // recordSearchHistory records a search to the research history.
 // If entryID is provided, it is used as the history entry ID (to match the cache file).
-func (s *Server) recordSearchHistory(query string, options map[string]any, results []map[string]any, filePath string, duration time.Duration, entryID string) {
+// summary is the sampling digest, if one was requested and produced.
+func (s *Server) recordSearchHistory(query string, options map[string]any, results []map[string]any, filePath string, duration time.Duration, entryID, summary string) {
 	topResults := make([]history.TopResultSummary, 0, 5)
 	for i, result := range results {
 		if i >= 5 {
@@ -56,6 +57,7 @@ func (s *Server) recordSearchHistory(query string, options map[string]any, resul
 		ResultSummary: history.ResultSummary{
 			Count:      len(results),
 			TopResults: topResults,
+			Summary:    summary,
 		},
 		DurationMs: duration.Milliseconds(),
 		FilePath:   filePath,
@@ -63,20 +65,28 @@ func (s *Server) recordSearchHistory(query string, options map[string]any, resul
 }
 
 // recordQuestionHistory records a question to the research history.
-func (s *Server) recordQuestionHistory(question string, answer *browser.AnswerResponse, duration time.Duration) {
+// summary is the sampling digest, if one was requested and produced.
+func (s *Server) recordQuestionHistory(question string, answer *browser.AnswerResponse, duration time.Duration, summary string) {
 	answerPreview := answer.MisoResponse.Data.Answer
 	if len(answerPreview) > 200 {
 		answerPreview = answerPreview[:200] + "..."
 	}
 
+	var params map[string]any
+	if answer.QuestionID != "" {
+		params = map[string]any{"question_id": answer.QuestionID} // lets oreilly_export_citations reload the sources
+	}
+
 	s.saveHistoryEntry(history.Entry{
-		Type:     history.EntryTypeQuestion,
-		Query:    question,
-		ToolName: "oreilly_ask_question",
+		Type:       history.EntryTypeQuestion,
+		Query:      question,
+		ToolName:   "oreilly_ask_question",
+		Parameters: params,
 		ResultSummary: history.ResultSummary{
 			AnswerPreview: answerPreview,
 			SourcesCount:  len(answer.MisoResponse.Data.Sources),
 			FollowupCount: len(answer.MisoResponse.Data.FollowupQuestions),
+			Summary:       summary,
 		},
 		DurationMs: duration.Milliseconds(),
 	})
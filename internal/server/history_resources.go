@@ -59,6 +59,17 @@ func (s *Server) registerHistoryResources() {
 		},
 		s.GetHistoryCachedFileResource,
 	)
+
+	// O'Reilly Answers の会話スレッドリソーステンプレート
+	s.server.AddResourceTemplate(
+		&mcp.ResourceTemplate{
+			URITemplate: "orm-mcp://threads/{id}",
+			Name:        "O'Reilly Answers Thread",
+			Description: descTmplThread,
+			MIMEType:    "application/json",
+		},
+		s.GetThreadResource,
+	)
 }
 
 // GetRecentHistoryResource は直近の調査履歴を取得する
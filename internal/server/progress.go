@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+// newAnswerProgressNotifier returns a callback that sends notifications/progress
+// for each partial answer while O'Reilly Answers is generating.
+// Returns nil when the client did not send a progress token (or no session is available).
+func newAnswerProgressNotifier(ctx context.Context, req *mcp.CallToolRequest, maxWaitTime time.Duration) browser.AnswerProgressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	var sent string // answer text already sent, so each notification carries only what is new
+	return func(partial *browser.AnswerResponse, elapsed time.Duration) {
+		params := answerProgressParams(token, partial, sent, elapsed, maxWaitTime)
+		if partial != nil {
+			sent = partial.MisoResponse.Data.Answer
+		}
+		if err := req.Session.NotifyProgress(ctx, params); err != nil {
+			slog.Debug("進捗通知の送信に失敗しました", "error", err)
+		}
+	}
+}
+
+// answerProgressParams builds the progress notification for a partial answer.
+// Progress is the elapsed time in seconds, so it increases on every poll.
+// Message is a short status line; the answer text is sent once, as the delta
+// since sent in Meta: answer_delta replaces the text after the first
+// answer_offset characters (0 when the answer was rewritten rather than extended).
+func answerProgressParams(token any, partial *browser.AnswerResponse, sent string, elapsed, maxWaitTime time.Duration) *mcp.ProgressNotificationParams {
+	partialAnswer := ""
+	questionID := ""
+	if partial != nil {
+		partialAnswer = partial.MisoResponse.Data.Answer
+		questionID = partial.QuestionID
+	}
+
+	offset := 0
+	delta := partialAnswer
+	if strings.HasPrefix(partialAnswer, sent) {
+		offset = utf8.RuneCountInString(sent)
+		delta = partialAnswer[len(sent):]
+	}
+
+	chars := utf8.RuneCountInString(partialAnswer)
+	message := fmt.Sprintf("Generating answer... (%s elapsed)", elapsed.Round(time.Second))
+	if chars > 0 {
+		message = fmt.Sprintf("Generating answer... (%d characters, %s elapsed)", chars, elapsed.Round(time.Second))
+	}
+
+	return &mcp.ProgressNotificationParams{
+		ProgressToken: token,
+		Message:       message,
+		Progress:      elapsed.Seconds(),
+		Total:         maxWaitTime.Seconds(),
+		Meta: mcp.Meta{
+			"question_id":     questionID,
+			"answer_delta":    delta,
+			"answer_offset":   offset,
+			"elapsed_seconds": int(elapsed.Seconds()),
+		},
+	}
+}
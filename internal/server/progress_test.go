@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+func partialAnswer(text string) *browser.AnswerResponse {
+	return &browser.AnswerResponse{
+		QuestionID:   "q-1",
+		MisoResponse: browser.MisoResponse{Data: browser.AnswerData{Answer: text}},
+	}
+}
+
+func TestNewAnswerProgressNotifier_NoToken(t *testing.T) {
+	assert.Nil(t, newAnswerProgressNotifier(context.Background(), nil, time.Minute))
+	assert.Nil(t, newAnswerProgressNotifier(context.Background(), &mcp.CallToolRequest{}, time.Minute))
+}
+
+func TestAnswerProgressParams(t *testing.T) {
+	t.Run("status line and answer text", func(t *testing.T) {
+		p := answerProgressParams("tok", partialAnswer("Goroutines are"), "", 4*time.Second, time.Minute)
+		assert.Equal(t, "tok", p.ProgressToken)
+		assert.Equal(t, "Generating answer... (14 characters, 4s elapsed)", p.Message)
+		assert.InDelta(t, 4.0, p.Progress, 0.001)
+		assert.InDelta(t, 60.0, p.Total, 0.001)
+		assert.Equal(t, "Goroutines are", p.Meta["answer_delta"])
+		assert.Equal(t, 0, p.Meta["answer_offset"])
+		assert.Equal(t, 4, p.Meta["elapsed_seconds"])
+		assert.NotContains(t, p.Meta, "partial_answer", "the answer text is sent once")
+	})
+
+	t.Run("extended answer sends only the delta", func(t *testing.T) {
+		p := answerProgressParams("tok", partialAnswer("Goroutines are cheap"), "Goroutines are", 5*time.Second, time.Minute)
+		assert.Equal(t, " cheap", p.Meta["answer_delta"])
+		assert.Equal(t, 14, p.Meta["answer_offset"])
+	})
+
+	t.Run("offset counts characters", func(t *testing.T) {
+		p := answerProgressParams("tok", partialAnswer("ゴルーチンは軽量"), "ゴルーチンは", 5*time.Second, time.Minute)
+		assert.Equal(t, "軽量", p.Meta["answer_delta"])
+		assert.Equal(t, 6, p.Meta["answer_offset"])
+		assert.Contains(t, p.Message, "8 characters")
+	})
+
+	t.Run("rewritten answer is sent in full", func(t *testing.T) {
+		p := answerProgressParams("tok", partialAnswer("Channels are"), "Goroutines are", 5*time.Second, time.Minute)
+		assert.Equal(t, "Channels are", p.Meta["answer_delta"])
+		assert.Equal(t, 0, p.Meta["answer_offset"])
+	})
+
+	t.Run("empty answer reports elapsed time only", func(t *testing.T) {
+		p := answerProgressParams("tok", partialAnswer(""), "", 2*time.Second, time.Minute)
+		assert.Equal(t, "Generating answer... (2s elapsed)", p.Message)
+	})
+}
+
+func TestAskQuestionHandler_SendsProgressNotifications(t *testing.T) {
+	mock := &mockBrowserClient{
+		askPartials: []*browser.AnswerResponse{partialAnswer("Go"), partialAnswer("Go channels")},
+		askAnswer: &browser.AnswerResponse{
+			QuestionID:   "q-1",
+			IsFinished:   true,
+			MisoResponse: browser.MisoResponse{Data: browser.AnswerData{Answer: "Go channels connect goroutines."}},
+		},
+	}
+	srv := newTestServer(t, mock)
+
+	var mu sync.Mutex
+	var messages, deltas []string
+	session := connectTestSession(t, srv, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, req.Params.Message)
+			delta, _ := req.Params.Meta["answer_delta"].(string)
+			deltas = append(deltas, delta)
+		},
+	})
+
+	params := &mcp.CallToolParams{
+		Meta:      mcp.Meta{"progressToken": "progress-1"},
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "What are Go channels?"},
+	}
+	res, err := session.CallTool(context.Background(), params)
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(messages) == 2
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"Generating answer... (2 characters, 1s elapsed)",
+		"Generating answer... (11 characters, 2s elapsed)",
+	}, messages)
+	assert.Equal(t, []string{"Go", " channels"}, deltas)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// ResolveHandler handles the oreilly_resolve tool.
+// URL・ISBN・OURN などを正規化し、書籍詳細を取得して実在を確認してからリソース URI を返します。
+func (s *Server) ResolveHandler(ctx context.Context, _ *mcp.CallToolRequest, args ResolveArgs) (*mcp.CallToolResult, *ResolveResult, error) {
+	client := s.getBrowserClient()
+	if client == nil {
+		return newToolResultError("O'Reilly セッションが認証されていません。" +
+			"oreilly_reauthenticate ツールを呼び出してログインしてください。"), nil, nil
+	}
+	if args.Input == "" {
+		return newToolResultError(errH.ValidationMessage()), nil, nil
+	}
+	if len(args.Input) > maxQueryLength {
+		return newToolResultError(fmt.Sprintf("Input is too long. Please use %d characters or fewer.", maxQueryLength)), nil, nil
+	}
+
+	ref, ok := mcputil.ResolveProductRef(args.Input)
+	if !ok {
+		return newToolResultError(fmt.Sprintf("%q is not an O'Reilly URL, ISBN, OURN or product_id. "+
+			"Use oreilly_search_content to find content by title.", args.Input)), nil, nil
+	}
+
+	if !ref.IsBook() {
+		// Videos and courses have no epub details to check and no oreilly://book-* resources
+		slog.Info("リンクを解決しました", "input", args.Input, "product_id", ref.ProductID, "content_type", ref.ContentType)
+		return nil, &ResolveResult{Input: args.Input, ProductID: ref.ProductID, ContentType: ref.ContentType}, nil
+	}
+
+	details, err := client.GetBookDetails(ctx, ref.ProductID)
+	if err != nil {
+		return newToolResultError(errH.Sanitize(err, "operation", "resolve", "product_id", ref.ProductID)), nil, nil
+	}
+
+	productID := cmp.Or(details.Identifier, ref.ProductID)
+	result := &ResolveResult{
+		Input:       args.Input,
+		ProductID:   productID,
+		ContentType: cmp.Or(details.ContentFormat, ref.ContentType),
+		Title:       details.Title,
+		ISBN:        details.ISBN,
+		DetailsURI:  mcputil.BookDetailsURI(productID),
+		TOCURI:      mcputil.BookTOCURI(productID),
+	}
+	links := []mcp.Content{&mcp.ResourceLink{URI: result.DetailsURI, Name: cmp.Or(details.Title, productID), MIMEType: "application/json"}}
+	if ref.ChapterName != "" {
+		result.ChapterName = ref.ChapterName
+		result.ChapterURI = mcputil.BookChapterURI(productID, ref.ChapterName)
+		links = append(links, &mcp.ResourceLink{URI: result.ChapterURI, Name: ref.ChapterName, MIMEType: "application/json"})
+	}
+	slog.Info("リンクを解決しました", "input", args.Input, "product_id", productID, "chapter", ref.ChapterName)
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return newToolResultError(errH.Sanitize(err, "operation", "marshal_resolve")), nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: append([]mcp.Content{&mcp.TextContent{Text: string(jsonBytes)}}, links...),
+	}, result, nil
+}
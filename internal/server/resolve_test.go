@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+func callResolve(t *testing.T, session *mcp.ClientSession, input string) *mcp.CallToolResult {
+	t.Helper()
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_resolve",
+		Arguments: map[string]any{"input": input},
+	})
+	require.NoError(t, err)
+	return res
+}
+
+func TestResolveHandler_ChapterURL(t *testing.T) {
+	mock := &mockBrowserClient{bookDetails: &browser.BookDetailResponse{
+		Identifier:    "9781491903063",
+		ISBN:          "9781449373320",
+		Title:         "Designing Data-Intensive Applications",
+		ContentFormat: "book",
+	}}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	res := callResolve(t, session, "https://learning.oreilly.com/library/view/designing-data-intensive-applications/9781491903063/ch05.html#sec_replication")
+	require.False(t, res.IsError, "unexpected error: %v", res.Content)
+	assert.Equal(t, []string{"9781491903063"}, mock.detailsReqs)
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "9781491903063", structured["product_id"])
+	assert.Equal(t, "book", structured["content_type"])
+	assert.Equal(t, "Designing Data-Intensive Applications", structured["title"])
+	assert.Equal(t, "oreilly://book-details/9781491903063", structured["details_uri"])
+	assert.Equal(t, "oreilly://book-toc/9781491903063", structured["toc_uri"])
+	assert.Equal(t, "oreilly://book-chapter/9781491903063/ch05.html", structured["chapter_uri"])
+
+	require.Len(t, res.Content, 3, "JSON followed by details and chapter links")
+	assert.Equal(t, "oreilly://book-details/9781491903063", res.Content[1].(*mcp.ResourceLink).URI)
+	assert.Equal(t, "oreilly://book-chapter/9781491903063/ch05.html", res.Content[2].(*mcp.ResourceLink).URI)
+}
+
+func TestResolveHandler_ISBN10(t *testing.T) {
+	mock := &mockBrowserClient{bookDetails: &browser.BookDetailResponse{Title: "Some Book"}}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	res := callResolve(t, session, "1-4493-7332-1")
+	require.False(t, res.IsError)
+	assert.Equal(t, []string{"9781449373320"}, mock.detailsReqs, "ISBN-10 is converted before lookup")
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "9781449373320", structured["product_id"], "the normalized ID is used when details carry none")
+	assert.NotContains(t, structured, "chapter_uri")
+}
+
+func TestResolveHandler_Errors(t *testing.T) {
+	t.Run("unrecognized input", func(t *testing.T) {
+		mock := &mockBrowserClient{}
+		session := connectTestSession(t, newTestServer(t, mock), nil)
+
+		res := callResolve(t, session, "Designing Data-Intensive Applications")
+		require.True(t, res.IsError)
+		assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "oreilly_search_content")
+		assert.Empty(t, mock.detailsReqs)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		mock := &mockBrowserClient{bookErr: errors.New("API request failed with status 404")}
+		session := connectTestSession(t, newTestServer(t, mock), nil)
+
+		res := callResolve(t, session, "urn:orm:book:0000000000000")
+		require.True(t, res.IsError)
+		assert.Equal(t, []string{"0000000000000"}, mock.detailsReqs)
+	})
+}
+
+func TestResolveHandler_VideoAndCourse(t *testing.T) {
+	tests := []struct {
+		input, productID, contentType string
+	}{
+		{"https://learning.oreilly.com/videos/kubernetes-fundamentals/9780135918845/9780135918845-KF1_01_00/", "9780135918845", "video"},
+		{"urn:orm:video:9780135918845", "9780135918845", "video"},
+		{"https://www.oreilly.com/course/python-fundamentals/9780135917411/", "9780135917411", "course"},
+	}
+	for _, tt := range tests {
+		mock := &mockBrowserClient{bookErr: errors.New("404 from the epubs API")}
+		srv := newTestServer(t, mock)
+		session := connectTestSession(t, srv, nil)
+
+		res := callResolve(t, session, tt.input)
+		require.False(t, res.IsError, "%s: %v", tt.input, res.Content)
+		assert.Empty(t, mock.detailsReqs, "%s: non-books are not looked up in the epubs API", tt.input)
+
+		structured, ok := res.StructuredContent.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, tt.productID, structured["product_id"], tt.input)
+		assert.Equal(t, tt.contentType, structured["content_type"], tt.input)
+		assert.NotContains(t, structured, "details_uri", tt.input)
+		assert.NotContains(t, structured, "toc_uri", tt.input)
+	}
+}
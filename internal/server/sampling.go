@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+const (
+	// summaryInputResults caps the search results sent to the client's model.
+	summaryInputResults = 10
+	// summaryDescriptionLen caps each result description sent to the client's model, in runes.
+	summaryDescriptionLen = 300
+)
+
+// Notes returned in place of a summary that was requested but not produced.
+const (
+	summaryUnsupportedNote = "Summary unavailable: the client does not support MCP sampling or sampling is disabled on the server."
+	summaryFailedNote      = "Summary unavailable: the sampling request to the client failed."
+	summaryAsyncNote       = "Summary unavailable for async questions; read the answer resource and summarize it instead."
+)
+
+// summarizeSearch asks the client's model for a digest of search results.
+// It returns the summary, or a note explaining why there is none.
+func (s *Server) summarizeSearch(ctx context.Context, session *mcp.ServerSession, query string, results []map[string]any) (summary, note string) {
+	if !s.samplingManager.CanSample(session) {
+		return "", summaryUnsupportedNote
+	}
+
+	// Send only what a digest needs, so the prompt stays small
+	input := make([]map[string]any, 0, min(len(results), summaryInputResults))
+	for _, result := range results[:min(len(results), summaryInputResults)] {
+		item := map[string]any{}
+		for _, key := range []string{"title", "content_type", "publisher", "published_date"} {
+			if v, _ := result[key].(string); v != "" {
+				item[key] = v
+			}
+		}
+		if names := extractAuthorSlice(result["authors"]); len(names) > 0 {
+			item["authors"] = names
+		}
+		if desc, _ := result["description"].(string); desc != "" {
+			item["description"] = truncateRunes(desc, summaryDescriptionLen)
+		}
+		input = append(input, item)
+	}
+
+	summary, err := s.samplingManager.SummarizeSearchResults(ctx, session, query, input)
+	return samplingOutcome(summary, err, "query", query)
+}
+
+// summarizeAnswer asks the client's model for a digest of an answer and its sources.
+// It returns the summary, or a note explaining why there is none.
+func (s *Server) summarizeAnswer(ctx context.Context, session *mcp.ServerSession, question string, answer *browser.AnswerResponse) (summary, note string) {
+	if !s.samplingManager.CanSample(session) {
+		return "", summaryUnsupportedNote
+	}
+
+	sources := make([]any, 0, len(answer.MisoResponse.Data.Sources))
+	for _, src := range answer.MisoResponse.Data.Sources {
+		sources = append(sources, map[string]any{"title": src.Title, "authors": src.Authors})
+	}
+
+	summary, err := s.samplingManager.SummarizeQuestionAnswer(ctx, session, question, answer.MisoResponse.Data.Answer, sources)
+	return samplingOutcome(summary, err, "question", question)
+}
+
+// samplingOutcome maps a sampling result to a summary or a note.
+func samplingOutcome(summary string, err error, kv ...any) (string, string) {
+	if err != nil {
+		slog.Warn("サンプリングによる要約に失敗しました", append(kv, "error", err)...)
+		return "", summaryFailedNote
+	}
+	if summary == "" {
+		return "", summaryFailedNote
+	}
+	slog.Info("サンプリングによる要約完了", append(kv, "summary_length", len(summary))...)
+	return summary, ""
+}
+
+// truncateRunes shortens s to n runes, marking the cut with "...".
+func truncateRunes(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/sampling"
+)
+
+// newSamplingServer returns a test server with sampling enabled.
+func newSamplingServer(t *testing.T, mock *mockBrowserClient) *Server {
+	t.Helper()
+	srv := newTestServer(t, mock)
+	srv.config.Sampling = config.SamplingOpts{Enabled: true, MaxTokens: 321}
+	srv.samplingManager = sampling.NewManager(srv.config)
+	return srv
+}
+
+// replySampling returns client options whose sampling handler replies with text.
+// Received requests are sent to got.
+func replySampling(text string, got chan<- *mcp.CreateMessageParams) *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		CreateMessageHandler: func(_ context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			if got != nil {
+				got <- req.Params
+			}
+			return &mcp.CreateMessageResult{Model: "test-model", Role: sampling.RoleAssistant, Content: &mcp.TextContent{Text: text}}, nil
+		},
+	}
+}
+
+func samplingPrompt(t *testing.T, params *mcp.CreateMessageParams) string {
+	t.Helper()
+	require.Len(t, params.Messages, 1)
+	text, ok := params.Messages[0].Content.(*mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestSearchContentHandler_Summarize(t *testing.T) {
+	mock := &mockBrowserClient{searchResults: []map[string]any{
+		{"title": "Book A", "product_id": "111", "content_type": "book", "authors": []string{"Ann"}, "description": strings.Repeat("long ", 200)},
+		{"title": "Book B", "product_id": "222", "content_type": "book"},
+	}}
+	srv := newSamplingServer(t, mock)
+	got := make(chan *mcp.CreateMessageParams, 1)
+	session := connectTestSession(t, srv, replySampling("Two books on Go.", got))
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_search_content",
+		Arguments: map[string]any{"query": "golang", "summarize": true},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Two books on Go.", structured["summary"])
+	assert.NotContains(t, structured, "summary_note")
+	assert.Equal(t, "Summary: Two books on Go.", res.Content[0].(*mcp.TextContent).Text)
+
+	params := <-got
+	assert.Equal(t, int64(321), params.MaxTokens, "SamplingOpts.MaxTokens is respected")
+	prompt := samplingPrompt(t, params)
+	assert.Contains(t, prompt, "Book A")
+	assert.NotContains(t, prompt, "product_id", "only the fields a digest needs are sent")
+	assert.Less(t, len(prompt), 1000, "long descriptions are truncated")
+
+	entry := srv.historyManager.GetByID(structured["history_id"].(string))
+	require.NotNil(t, entry)
+	assert.Equal(t, "Two books on Go.", entry.ResultSummary.Summary)
+}
+
+func TestSearchContentHandler_SummarizeWithoutSampling(t *testing.T) {
+	mock := &mockBrowserClient{searchResults: []map[string]any{{"title": "Book A", "product_id": "111"}}}
+	srv := newSamplingServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_search_content",
+		Arguments: map[string]any{"query": "golang", "summarize": true},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "the search still succeeds")
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.NotContains(t, structured, "summary")
+	assert.Equal(t, summaryUnsupportedNote, structured["summary_note"])
+}
+
+func TestSearchContentHandler_NoSummaryByDefault(t *testing.T) {
+	mock := &mockBrowserClient{searchResults: []map[string]any{{"title": "Book A", "product_id": "111"}}}
+	srv := newSamplingServer(t, mock)
+	got := make(chan *mcp.CreateMessageParams, 1)
+	session := connectTestSession(t, srv, replySampling("unused", got))
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_search_content",
+		Arguments: map[string]any{"query": "golang"},
+	})
+	require.NoError(t, err)
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.NotContains(t, structured, "summary")
+	assert.NotContains(t, structured, "summary_note")
+	assert.Empty(t, got, "no sampling request is sent")
+}
+
+func TestAskQuestionHandler_Summarize(t *testing.T) {
+	mock := &mockBrowserClient{askAnswer: answerWithSource("q1", "Raft elects a leader by majority vote.", "Database Internals")}
+	srv := newSamplingServer(t, mock)
+	got := make(chan *mcp.CreateMessageParams, 1)
+	session := connectTestSession(t, srv, replySampling("Majority vote, per Database Internals.", got))
+
+	structured := callAsk(t, session, map[string]any{"question": "How does Raft elect a leader?", "summarize": true})
+	assert.Equal(t, "Majority vote, per Database Internals.", structured["summary"])
+
+	prompt := samplingPrompt(t, <-got)
+	assert.Contains(t, prompt, "Raft elects a leader by majority vote.")
+	assert.Contains(t, prompt, "Database Internals", "sources are sent with the answer")
+
+	recent := srv.historyManager.GetRecent(1)
+	require.Len(t, recent, 1)
+	assert.Equal(t, "Majority vote, per Database Internals.", recent[0].ResultSummary.Summary)
+}
+
+func TestAskQuestionHandler_SummarizeAsync(t *testing.T) {
+	mock := &mockBrowserClient{
+		submitResp: &browser.QuestionResponse{QuestionID: "q-async"},
+		askAnswer:  answerWithSource("q-async", "Use fencing tokens.", "Designing Data-Intensive Applications"),
+	}
+	srv := newSamplingServer(t, mock)
+	session := connectTestSession(t, srv, replySampling("unused", nil))
+
+	structured := callAsk(t, session, map[string]any{"question": "How to avoid split brain?", "async": true, "summarize": true})
+	assert.Equal(t, summaryAsyncNote, structured["summary_note"])
+}
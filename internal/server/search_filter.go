@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+// searchFilterFromArgs builds the client-side search filter from tool arguments.
+// A published_before bound covers its whole period, so "2024" includes all of 2024.
+func searchFilterFromArgs(args SearchContentArgs) (browser.SearchFilter, error) {
+	filter := browser.SearchFilter{
+		Languages:    args.Languages,
+		ContentTypes: args.ContentTypes,
+		Publishers:   args.Publishers,
+		Authors:      args.Authors,
+	}
+
+	if args.PublishedAfter != "" {
+		after, ok := browser.ParsePublishedDate(args.PublishedAfter)
+		if !ok {
+			return filter, fmt.Errorf("invalid published_after %q: use YYYY, YYYY-MM or YYYY-MM-DD", args.PublishedAfter)
+		}
+		filter.PublishedAfter = after
+	}
+	if args.PublishedBefore != "" {
+		before, ok := browser.ParsePublishedDate(args.PublishedBefore)
+		if !ok {
+			return filter, fmt.Errorf("invalid published_before %q: use YYYY, YYYY-MM or YYYY-MM-DD", args.PublishedBefore)
+		}
+		filter.PublishedBefore = endOfPeriod(before, args.PublishedBefore)
+	}
+	if !filter.PublishedAfter.IsZero() && !filter.PublishedBefore.IsZero() && filter.PublishedAfter.After(filter.PublishedBefore) {
+		return filter, fmt.Errorf("published_after (%s) must not be later than published_before (%s)", args.PublishedAfter, args.PublishedBefore)
+	}
+	return filter, nil
+}
+
+// endOfPeriod returns the last instant of the year, month or day that s denotes.
+func endOfPeriod(t time.Time, s string) time.Time {
+	switch len(s) {
+	case len("2006"):
+		return t.AddDate(1, 0, 0).Add(-time.Nanosecond)
+	case len("2006-01"):
+		return t.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	case len("2006-01-02"):
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	default:
+		return t
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSearchFilterFromArgs_DateBounds(t *testing.T) {
+	filter, err := searchFilterFromArgs(SearchContentArgs{PublishedAfter: "2023-01", PublishedBefore: "2024"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC); !filter.PublishedAfter.Equal(want) {
+		t.Errorf("PublishedAfter = %v, want %v", filter.PublishedAfter, want)
+	}
+	// published_before covers the whole year
+	if !filter.PublishedBefore.After(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)) ||
+		!filter.PublishedBefore.Before(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublishedBefore = %v, want end of 2024", filter.PublishedBefore)
+	}
+}
+
+func TestSearchFilterFromArgs_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args SearchContentArgs
+	}{
+		{"bad after", SearchContentArgs{PublishedAfter: "last year"}},
+		{"bad before", SearchContentArgs{PublishedBefore: "2024/01/01"}},
+		{"inverted range", SearchContentArgs{PublishedAfter: "2024-06-01", PublishedBefore: "2023"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := searchFilterFromArgs(tt.args); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSearchContentHandler_ClientSideFilters(t *testing.T) {
+	mock := &mockBrowserClient{
+		searchResults: []map[string]any{
+			{"title": "Old", "product_id": "111", "content_type": "book", "published_date": "2019-03-01", "language": "en"},
+			{"title": "New EN", "product_id": "222", "content_type": "book", "published_date": "2024-05-10", "language": "en-US"},
+			{"title": "New JA", "product_id": "333", "content_type": "book", "published_date": "2024-07-01", "language": "ja"},
+			{"title": "Undated", "product_id": "444", "content_type": "book", "language": "en"},
+		},
+		searchTotalResults: 40,
+	}
+	srv := newTestServer(t, mock)
+
+	args := SearchContentArgs{Query: "go", Rows: 4, Languages: []string{"en"}, PublishedAfter: "2023"}
+	result, structured, err := srv.SearchContentHandler(context.Background(), &mcp.CallToolRequest{}, args)
+	if err != nil {
+		t.Fatalf("SearchContentHandler returned error: %v", err)
+	}
+	if result != nil && result.IsError {
+		t.Fatalf("unexpected tool error: %+v", result.Content)
+	}
+	if structured.Count != 1 || structured.Results[0]["id"] != "222" {
+		t.Errorf("expected only product 222, got %+v", structured.Results)
+	}
+	// Pagination advances by the number fetched from the API, not the filtered count.
+	if !structured.HasMore || structured.NextOffset != 4 {
+		t.Errorf("HasMore=%v NextOffset=%d, want true/4", structured.HasMore, structured.NextOffset)
+	}
+}
+
+func TestSearchContentHandler_InvalidDate(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+
+	result, _, err := srv.SearchContentHandler(context.Background(), &mcp.CallToolRequest{},
+		SearchContentArgs{Query: "go", PublishedAfter: "recently"})
+	if err != nil {
+		t.Fatalf("SearchContentHandler returned error: %v", err)
+	}
+	if result == nil || !result.IsError {
+		t.Fatal("expected tool error for invalid published_after")
+	}
+}
+
+func TestSearchContentHandler_Sort(t *testing.T) {
+	mock := &mockBrowserClient{
+		searchResults: []map[string]any{{"title": "Book A", "product_id": "111"}},
+	}
+	srv := newTestServer(t, mock)
+
+	result, _, err := srv.SearchContentHandler(context.Background(), &mcp.CallToolRequest{},
+		SearchContentArgs{Query: "go", Sort: "newest"})
+	if err != nil {
+		t.Fatalf("SearchContentHandler returned error: %v", err)
+	}
+	if result != nil && result.IsError {
+		t.Fatalf("unexpected tool error: %+v", result.Content)
+	}
+	if got := mock.searchOptions["sort"]; got != "newest" {
+		t.Errorf("sort option = %v, want newest", got)
+	}
+}
+
+func TestSearchContentHandler_InvalidSort(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+
+	result, _, err := srv.SearchContentHandler(context.Background(), &mcp.CallToolRequest{},
+		SearchContentArgs{Query: "go", Sort: "rating"})
+	if err != nil {
+		t.Fatalf("SearchContentHandler returned error: %v", err)
+	}
+	if result == nil || !result.IsError {
+		t.Fatal("expected tool error for invalid sort")
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// defaultLocalResults is the number of sections returned when limit is not set.
+const defaultLocalResults = 10
+
+// SearchLocalHandler searches the sections of chapters fetched so far.
+// ネットワークも認証も使わないため、degraded モードやオフライン時にも動作します。
+func (s *Server) SearchLocalHandler(_ context.Context, _ *mcp.CallToolRequest, args SearchLocalArgs) (*mcp.CallToolResult, *SearchLocalResult, error) {
+	if strings.TrimSpace(args.Query) == "" {
+		return newToolResultError(errH.ValidationMessage()), nil, nil
+	}
+	if len(args.Query) > maxQueryLength {
+		return newToolResultError(fmt.Sprintf("Query is too long. Please use %d characters or fewer.", maxQueryLength)), nil, nil
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultLocalResults
+	}
+	args.Limit = min(args.Limit, maxLocalResults)
+
+	hits, total := s.fulltextIndex.Search(args.Query, fulltext.SearchOptions{BookID: args.ProductID, Limit: args.Limit})
+	result := &SearchLocalResult{
+		Query:   args.Query,
+		Count:   len(hits),
+		Total:   total,
+		Results: make([]SearchLocalHit, 0, len(hits)),
+		Indexed: s.fulltextIndex.Stats(),
+	}
+	for _, hit := range hits {
+		result.Results = append(result.Results, SearchLocalHit{
+			Hit:        hit,
+			ChapterURI: mcputil.BookChapterURI(hit.BookID, hit.ChapterName),
+			SectionURI: chapterSectionURI(hit.BookID, hit.ChapterName, hit.SectionIndex),
+		})
+	}
+	slog.Info("ローカル全文検索完了", "query", args.Query, "product_id", args.ProductID, "result_count", len(hits), "total", total)
+
+	text := formatSearchLocalMarkdown(result)
+	if args.Format != ResponseFormatMarkdown {
+		text = searchLocalSummary(result)
+	}
+	content := []mcp.Content{&mcp.TextContent{Text: text}}
+	for _, hit := range result.Results {
+		content = append(content, &mcp.ResourceLink{
+			URI:      hit.SectionURI,
+			Name:     sectionLinkName(hit),
+			MIMEType: "application/json",
+		})
+	}
+	return &mcp.CallToolResult{Content: content}, result, nil
+}
+
+// searchLocalSummary is the short text accompanying the structured result.
+func searchLocalSummary(result *SearchLocalResult) string {
+	if result.Count == 0 {
+		return fmt.Sprintf("No sections matched %q in the local index (%d chapters of %d books). "+
+			"Only chapters read through oreilly://book-chapter are indexed; use oreilly_search_content to find books.",
+			result.Query, result.Indexed.Chapters, result.Indexed.Books)
+	}
+	lines := []string{fmt.Sprintf("%d of %d matching sections:", result.Count, result.Total)}
+	for i, hit := range result.Results {
+		lines = append(lines, fmt.Sprintf("%d. %s (%s)", i+1, sectionLinkName(hit), hit.SectionURI))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sectionLinkName names a hit by its book, chapter and heading.
+func sectionLinkName(hit SearchLocalHit) string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{hit.BookTitle, hit.ChapterTitle, hit.Heading} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return hit.SectionURI
+	}
+	return strings.Join(parts, " › ")
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
+)
+
+func TestSearchLocalHandler(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+	srv.fulltextIndex = fulltext.New(t.TempDir())
+	require.NoError(t, srv.fulltextIndex.IndexChapter("123", "ch01.html", []fulltext.Document{
+		{BookID: "123", BookTitle: "Designing Systems", ChapterName: "ch01.html", ChapterTitle: "Chapter 1. Retries", SectionIndex: 0, Heading: "Retries", Text: "Clients retry after a timeout."},
+		{BookID: "123", BookTitle: "Designing Systems", ChapterName: "ch01.html", ChapterTitle: "Chapter 1. Retries", SectionIndex: 1, Heading: "Idempotency keys", Text: "An idempotency key makes a retried request safe."},
+	}))
+	session := connectTestSession(t, srv, nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_search_local",
+		Arguments: map[string]any{"query": "idempotency"},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, structured["count"])
+	results := structured["results"].([]any)
+	hit := results[0].(map[string]any)
+	assert.Equal(t, "oreilly://book-chapter/123/ch01.html", hit["chapter_uri"])
+	assert.Equal(t, "Idempotency keys", hit["heading"])
+	assert.Contains(t, hit["snippet"], "idempotency key")
+
+	require.Len(t, res.Content, 2)
+	link, ok := res.Content[1].(*mcp.ResourceLink)
+	require.True(t, ok)
+	assert.Equal(t, hit["section_uri"], link.URI)
+	assert.Equal(t, "Designing Systems › Chapter 1. Retries › Idempotency keys", link.Name)
+}
+
+func TestSearchLocalHandler_NoMatches(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+	session := connectTestSession(t, srv, nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_search_local",
+		Arguments: map[string]any{"query": "anything"},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "a disabled index finds nothing instead of failing")
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "No sections matched")
+
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_search_local",
+		Arguments: map[string]any{"query": "  "},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/cookie"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/elicitation"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/history"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/sampling"
@@ -30,34 +32,29 @@ const (
 
 // Server is the MCP server implementation.
 type Server struct {
-	clientMu        sync.RWMutex
-	browserClient   browser.Client
-	server          *mcp.Server
-	config          *config.Config
-	historyManager  *history.Manager
-	samplingManager *sampling.Manager
-	cookieManager   cookie.Manager // 再認証時の BrowserClient 再生成に使用
-	startedAt       time.Time      // サーバー起動時刻 (MCP 再起動検証用)
-	serverVersion   string
+	clientMu           sync.RWMutex
+	browserClient      browser.Client
+	server             *mcp.Server
+	config             *config.Config
+	historyManager     *history.Manager
+	threadManager      *history.ThreadManager // O'Reilly Answers の会話スレッド
+	samplingManager    *sampling.Manager
+	elicitationManager *elicitation.Manager
+	cookieManager      cookie.Manager // 再認証時の BrowserClient 再生成に使用
+	startedAt          time.Time      // サーバー起動時刻 (MCP 再起動検証用)
+	serverVersion      string
+	contentStore       *browser.ContentStore // ネットワーク・認証不可時に oreilly://book-* を配信する
+	fulltextIndex      *fulltext.Index       // 取得済みチャプターの全文検索 (BrowserClient と共有)
+	answerFailures     sync.Map              // question_id -> 非同期回答ポーリングの失敗理由 (oreilly://answer で返す)
+
+	// bgCtx はバックグラウンド処理 (非同期回答ポーリング) 用の context。Close でキャンセルされる。
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
 }
 
-// NewServer creates a new server instance.
-func NewServer(browserClient browser.Client, cfg *config.Config, cookieManager cookie.Manager, serverVersion string) *Server {
-	// Create MCP server
-	mcpServer := mcp.NewServer(
-		&mcp.Implementation{
-			Name:    "orm-discovery-mcp-go",
-			Version: serverVersion,
-		},
-		&mcp.ServerOptions{
-			Instructions: "O'Reilly Learning Platform MCP Server. " +
-				"ROUTING: use oreilly_ask_question for direct questions (what/why/how/best-practice), " +
-				"oreilly_search_content for topic/keyword discovery. " +
-				"Access details via oreilly://book-* resources. " +
-				"Always cite sources with title, author(s), and O'Reilly Media.",
-		},
-	)
-
+// NewServer creates a new server instance without a browser client, in degraded
+// mode until ConnectBrowserClient succeeds.
+func NewServer(cfg *config.Config, cookieManager cookie.Manager, serverVersion string) *Server {
 	// Initialize research history manager
 	historyManager := history.NewManager(
 		cfg.XDGDirs.ResearchHistoryPath(),
@@ -67,20 +64,43 @@ func NewServer(browserClient browser.Client, cfg *config.Config, cookieManager c
 		slog.Warn("調査履歴の読み込みに失敗しました", "error", err)
 	}
 
+	threadManager := history.NewThreadManager(
+		cfg.XDGDirs.AnswerThreadsPath(),
+		cfg.History.MaxThreads,
+	)
+	if err := threadManager.Load(); err != nil {
+		slog.Warn("会話スレッドの読み込みに失敗しました", "error", err)
+	}
+
 	// Initialize sampling manager
 	samplingManager := sampling.NewManager(cfg)
 
+	bgCtx, bgCancel := context.WithCancel(context.Background())
 	srv := &Server{
-		browserClient:   browserClient,
-		server:          mcpServer,
-		config:          cfg,
-		historyManager:  historyManager,
-		samplingManager: samplingManager,
-		cookieManager:   cookieManager,
-		startedAt:       time.Now(),
-		serverVersion:   serverVersion,
+		config:             cfg,
+		historyManager:     historyManager,
+		threadManager:      threadManager,
+		samplingManager:    samplingManager,
+		elicitationManager: elicitation.NewManager(cfg),
+		cookieManager:      cookieManager,
+		startedAt:          time.Now(),
+		serverVersion:      serverVersion,
+		contentStore:       browser.OpenContentStore(cfg.ContentStore),
+		fulltextIndex:      fulltext.Open(cfg.FullText),
+		bgCtx:              bgCtx,
+		bgCancel:           bgCancel,
 	}
 
+	// Create MCP server
+	mcpServer := mcp.NewServer(
+		&mcp.Implementation{
+			Name:    "orm-discovery-mcp-go",
+			Version: serverVersion,
+		},
+		srv.serverOptions(),
+	)
+	srv.server = mcpServer
+
 	// Add middleware for logging
 	mf := mcputil.MiddlewareFactory{LogLevel: cfg.Log.Level}
 	mcpServer.AddReceivingMiddleware(
@@ -88,6 +108,8 @@ func NewServer(browserClient browser.Client, cfg *config.Config, cookieManager c
 		mf.ToolLogging(),
 	)
 
+	go srv.pruneContentStore()
+
 	slog.Info("サーバーを初期化しました")
 
 	srv.registerHandlers()
@@ -96,6 +118,57 @@ func NewServer(browserClient browser.Client, cfg *config.Config, cookieManager c
 	return srv
 }
 
+// pruneContentStore removes content beyond the configured age and size limits
+// from the content store. It runs once at startup.
+func (s *Server) pruneContentStore() {
+	if s.contentStore == nil {
+		return
+	}
+	result, err := s.contentStore.Prune(s.config.ContentStore.MaxAge, s.config.ContentStore.MaxBytes)
+	if err != nil {
+		slog.Warn("コンテンツストアの整理に失敗しました", "error", err)
+		return
+	}
+	if result.Refs > 0 || result.Blobs > 0 {
+		slog.Info("コンテンツストアを整理しました", "refs", result.Refs, "blobs", result.Blobs, "bytes", result.Bytes)
+	}
+}
+
+// ConnectBrowserClient logs in to O'Reilly with a new browser client and uses
+// it from then on. The client saves content to the server's content store and
+// indexes chapters into its full-text index, so they are served offline and
+// searchable with oreilly_search_local right away.
+func (s *Server) ConnectBrowserClient() error {
+	client, err := browser.NewBrowserClient(s.cookieManager, browser.ClientOptions{
+		Debug:         s.config.Debug.Enabled,
+		StateDir:      s.config.XDGDirs.StateHome,
+		HTTP:          s.config.HTTP,
+		BookCache:     s.config.BookCache,
+		ContentStore:  s.contentStore,
+		FulltextIndex: s.fulltextIndex,
+	})
+	if err != nil {
+		return err
+	}
+	s.setBrowserClient(client)
+	return nil
+}
+
+// serverOptions returns the MCP server options, including resource subscription handlers.
+func (s *Server) serverOptions() *mcp.ServerOptions {
+	return &mcp.ServerOptions{
+		Instructions: "O'Reilly Learning Platform MCP Server. " +
+			"ROUTING: use oreilly_ask_question for direct questions (what/why/how/best-practice), " +
+			"oreilly_search_content for topic/keyword discovery, " +
+			"oreilly_search_local for passages inside chapters already read. " +
+			"Access details via oreilly://book-* resources. " +
+			"Always cite sources with title, author(s), and O'Reilly Media; " +
+			"oreilly://book-citation/{product_id} and oreilly_export_citations format them.",
+		SubscribeHandler:   s.SubscribeResourceHandler,
+		UnsubscribeHandler: s.UnsubscribeResourceHandler,
+	}
+}
+
 // getBrowserClient は browserClient を mutex で保護して返します。
 func (s *Server) getBrowserClient() browser.Client {
 	s.clientMu.RLock()
@@ -112,7 +185,11 @@ func (s *Server) setBrowserClient(client browser.Client) {
 
 // Close はサーバーが保持する BrowserClient をクリーンアップします。
 // degraded モードで後から設定された BrowserClient も確実に Close されます。
+// 実行中のバックグラウンド処理 (非同期回答ポーリング) もキャンセルされます。
 func (s *Server) Close() {
+	if s.bgCancel != nil {
+		s.bgCancel()
+	}
 	if client := s.getBrowserClient(); client != nil {
 		client.Close()
 	}
@@ -198,6 +275,62 @@ func (s *Server) registerHandlers() {
 	}
 	mcp.AddTool(s.server, askQuestionTool, s.AskQuestionHandler)
 
+	// Add local full-text search tool
+	searchLocalTool := &mcp.Tool{
+		Name:        "oreilly_search_local",
+		Title:       "Search Inside Fetched Books",
+		Description: descSearchLocal,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:    true,
+			DestructiveHint: ptrBool(false),
+			IdempotentHint:  true,
+			OpenWorldHint:   ptrBool(false),
+		},
+	}
+	mcp.AddTool(s.server, searchLocalTool, s.SearchLocalHandler)
+
+	// Add export book tool
+	exportBookTool := &mcp.Tool{
+		Name:        "oreilly_export_book",
+		Title:       "Export O'Reilly Book",
+		Description: descExportBook,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:    false,
+			DestructiveHint: ptrBool(false),
+			IdempotentHint:  true,
+			OpenWorldHint:   ptrBool(true),
+		},
+	}
+	mcp.AddTool(s.server, exportBookTool, s.ExportBookHandler)
+
+	// Add resolve tool
+	resolveTool := &mcp.Tool{
+		Name:        "oreilly_resolve",
+		Title:       "Resolve O'Reilly Link or ISBN",
+		Description: descResolve,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:    true,
+			DestructiveHint: ptrBool(false),
+			IdempotentHint:  true,
+			OpenWorldHint:   ptrBool(true),
+		},
+	}
+	mcp.AddTool(s.server, resolveTool, s.ResolveHandler)
+
+	// Add citation export tool
+	exportCitationsTool := &mcp.Tool{
+		Name:        "oreilly_export_citations",
+		Title:       "Export Citations",
+		Description: descExportCitations,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:    true,
+			DestructiveHint: ptrBool(false),
+			IdempotentHint:  true,
+			OpenWorldHint:   ptrBool(true),
+		},
+	}
+	mcp.AddTool(s.server, exportCitationsTool, s.ExportCitationsHandler)
+
 	// Add reauthenticate tool
 	reauthTool := &mcp.Tool{
 		Name:  "oreilly_reauthenticate",
@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,29 +22,124 @@ type mockBrowserClient struct {
 	searchResults      []map[string]any
 	searchTotalResults int
 	searchErr          error
+	searchOptions      map[string]any          // options passed to the last SearchContent call
+	questionOpts       browser.QuestionOptions // options passed to the last AskQuestion or SubmitQuestion call
+	questions          []string                // questions passed to AskQuestion and SubmitQuestion
+
+	askAnswer   *browser.AnswerResponse
+	askErr      error
+	askPartials []*browser.AnswerResponse // passed to onProgress before returning askAnswer
+	submitResp  *browser.QuestionResponse
+	submitErr   error
+	waitRelease chan struct{} // when set, WaitForAnswer blocks until it is closed
+
+	bookDetails *browser.BookDetailResponse
+	detailsByID map[string]*browser.BookDetailResponse // per product ID; takes precedence over bookDetails
+	detailsMu   sync.Mutex                             // guards detailsReqs for concurrent details fetches
+	detailsReqs []string                               // product IDs passed to GetBookDetails
+	credits     map[string]*browser.BookCredits        // GetBookCredits responses by product ID; empty credits otherwise
+	creditsErr  error                                  // returned by GetBookCredits
+	toc         *browser.TableOfContentsResponse
+	chapter     *browser.ChapterContentResponse
+	bookErr     error                         // returned by the book details, TOC and chapter methods
+	chapterErrs map[string]error              // per chapter name; takes precedence over bookErr
+	chapterMu   sync.Mutex                    // guards chapterReqs for concurrent chapter fetches
+	chapterReqs []string                      // chapter names passed to GetBookChapterContent
+	chapterHTML map[string]string             // GetChapterHTMLContent responses by chapter name
+	images      map[string]*browser.BookImage // by book-relative path
+	invalidated []string                      // product IDs passed to InvalidateBookCache
+	authErr     error                         // returned by CheckAndResetAuth
 }
 
-func (m *mockBrowserClient) SearchContent(_ string, _ map[string]any) ([]map[string]any, int, error) {
+func (m *mockBrowserClient) SearchContent(_ context.Context, _ string, options map[string]any) ([]map[string]any, int, error) {
+	m.searchOptions = options
 	return m.searchResults, m.searchTotalResults, m.searchErr
 }
-func (m *mockBrowserClient) AskQuestion(_ string, _ time.Duration) (*browser.AnswerResponse, error) {
-	return nil, nil
+func (m *mockBrowserClient) AskQuestion(_ context.Context, question string, opts browser.QuestionOptions, _ time.Duration, onProgress browser.AnswerProgressFunc) (*browser.AnswerResponse, error) {
+	m.questionOpts = opts
+	m.questions = append(m.questions, question)
+	for i, p := range m.askPartials {
+		if onProgress != nil {
+			onProgress(p, time.Duration(i+1)*time.Second)
+		}
+	}
+	return m.askAnswer, m.askErr
 }
-func (m *mockBrowserClient) GetBookDetails(_ string) (*browser.BookDetailResponse, error) {
-	return nil, nil
+func (m *mockBrowserClient) GetBookDetails(_ context.Context, productID string) (*browser.BookDetailResponse, error) {
+	m.detailsMu.Lock()
+	m.detailsReqs = append(m.detailsReqs, productID)
+	m.detailsMu.Unlock()
+	if details, ok := m.detailsByID[productID]; ok {
+		return details, nil
+	}
+	return m.bookDetails, m.bookErr
+}
+func (m *mockBrowserClient) GetBookCredits(_ context.Context, productID, _ string) (*browser.BookCredits, error) {
+	if m.creditsErr != nil {
+		return nil, m.creditsErr
+	}
+	if credits, ok := m.credits[productID]; ok {
+		return credits, nil
+	}
+	return &browser.BookCredits{}, nil
 }
-func (m *mockBrowserClient) GetBookTOC(_ string) (*browser.TableOfContentsResponse, error) {
-	return nil, nil
+func (m *mockBrowserClient) GetBookTOC(_ context.Context, _ string) (*browser.TableOfContentsResponse, error) {
+	return m.toc, m.bookErr
 }
-func (m *mockBrowserClient) GetBookChapterContent(_, _ string) (*browser.ChapterContentResponse, error) {
-	return nil, nil
+func (m *mockBrowserClient) GetBookChapterContent(_ context.Context, _, chapterName string) (*browser.ChapterContentResponse, error) {
+	m.chapterMu.Lock()
+	m.chapterReqs = append(m.chapterReqs, chapterName)
+	m.chapterMu.Unlock()
+	if err, ok := m.chapterErrs[chapterName]; ok {
+		return nil, err
+	}
+	return m.chapter, m.bookErr
 }
-func (m *mockBrowserClient) GetQuestionByID(_ string) (*browser.AnswerResponse, error) {
-	return nil, nil
+func (m *mockBrowserClient) GetChapterHTMLContent(_ context.Context, productID, chapterName string) (string, string, error) {
+	if err, ok := m.chapterErrs[chapterName]; ok {
+		return "", "", err
+	}
+	if m.bookErr != nil {
+		return "", "", m.bookErr
+	}
+	html, ok := m.chapterHTML[chapterName]
+	if !ok {
+		return "", "", errors.New("chapter not found")
+	}
+	return html, "https://learning.oreilly.com/api/v2/epubs/urn:orm:book:" + productID + "/files/" + chapterName, nil
+}
+func (m *mockBrowserClient) GetBookImage(_ context.Context, _, imagePath string) (*browser.BookImage, error) {
+	if image, ok := m.images[imagePath]; ok {
+		return image, nil
+	}
+	if m.bookErr != nil {
+		return nil, m.bookErr
+	}
+	return nil, errors.New("image not found")
+}
+func (m *mockBrowserClient) SubmitQuestion(_ context.Context, question string, opts browser.QuestionOptions) (*browser.QuestionResponse, error) {
+	m.questionOpts = opts
+	m.questions = append(m.questions, question)
+	return m.submitResp, m.submitErr
+}
+func (m *mockBrowserClient) WaitForAnswer(_ context.Context, _ string, _ time.Duration, _ browser.AnswerProgressFunc) (*browser.AnswerResponse, error) {
+	if m.waitRelease != nil {
+		<-m.waitRelease
+	}
+	return m.askAnswer, m.askErr
+}
+func (m *mockBrowserClient) GetQuestionByID(_ context.Context, _ string) (*browser.AnswerResponse, error) {
+	if m.askAnswer == nil {
+		return nil, errors.New("question not found")
+	}
+	return m.askAnswer, nil
+}
+func (m *mockBrowserClient) Reauthenticate() error                     { return nil }
+func (m *mockBrowserClient) CheckAndResetAuth(_ context.Context) error { return m.authErr }
+func (m *mockBrowserClient) Close()                                    {}
+func (m *mockBrowserClient) InvalidateBookCache(productID string) {
+	m.invalidated = append(m.invalidated, productID)
 }
-func (m *mockBrowserClient) Reauthenticate() error    { return nil }
-func (m *mockBrowserClient) CheckAndResetAuth() error { return nil }
-func (m *mockBrowserClient) Close()                   {}
 
 // newTestServer creates a Server with mock browser client and temp directories.
 func newTestServer(t *testing.T, mock *mockBrowserClient) *Server {
@@ -54,7 +151,7 @@ func newTestServer(t *testing.T, mock *mockBrowserClient) *Server {
 			CacheHome: filepath.Join(tmpDir, "cache"),
 			StateHome: filepath.Join(tmpDir, "state"),
 		},
-		History: config.HistoryOpts{MaxEntries: 100},
+		History: config.HistoryOpts{MaxEntries: 100, MaxThreads: 100},
 	}
 
 	historyManager := history.NewManager(
@@ -62,14 +159,47 @@ func newTestServer(t *testing.T, mock *mockBrowserClient) *Server {
 		cfg.History.MaxEntries,
 	)
 	_ = historyManager.Load()
+	threadManager := history.NewThreadManager(cfg.XDGDirs.AnswerThreadsPath(), cfg.History.MaxThreads)
+	_ = threadManager.Load()
+
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	t.Cleanup(bgCancel)
 
 	return &Server{
 		browserClient:  mock,
 		config:         cfg,
 		historyManager: historyManager,
+		threadManager:  threadManager,
 		startedAt:      time.Now(),
 		serverVersion:  "test",
+		bgCtx:          bgCtx,
+		bgCancel:       bgCancel,
+	}
+}
+
+// connectTestSession registers handlers on a fresh MCP server backed by srv and
+// connects an in-memory client with the given options.
+func connectTestSession(t *testing.T, srv *Server, clientOpts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	srv.server = mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "test"}, srv.serverOptions())
+	srv.registerHandlers()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := srv.server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, clientOpts)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
 	}
+	t.Cleanup(func() { _ = clientSession.Close() })
+	return clientSession
 }
 
 func TestSearchContentHandler_SingleSave(t *testing.T) {
@@ -289,7 +419,7 @@ func TestBuildLightweightResponse_BrowserAuthorConversion(t *testing.T) {
 		},
 	}
 
-	_, structured := srv.buildLightweightResponse(results, "hist_123", "/tmp/test.md", 0, 1)
+	_, structured := srv.buildLightweightResponse(results, "hist_123", "/tmp/test.md", 0, len(results), 1)
 
 	if structured == nil || len(structured.Results) == 0 {
 		t.Fatal("expected structured results")
@@ -316,7 +446,7 @@ func TestBuildLightweightResponse_FilePath(t *testing.T) {
 		{"id": "123", "title": "Test Book", "content_type": "book"},
 	}
 
-	toolResult, structured := srv.buildLightweightResponse(results, "hist_123", "/tmp/cache/test.md", 0, 1)
+	toolResult, structured := srv.buildLightweightResponse(results, "hist_123", "/tmp/cache/test.md", 0, len(results), 1)
 
 	if structured == nil {
 		t.Fatal("expected structured result")
@@ -347,7 +477,7 @@ func TestBuildLightweightResponse_LimitsTo5Results(t *testing.T) {
 		}
 	}
 
-	toolResult, structured := srv.buildLightweightResponse(results, "hist_123", "/tmp/test.md", 0, 50)
+	toolResult, structured := srv.buildLightweightResponse(results, "hist_123", "/tmp/test.md", 0, len(results), 50)
 
 	if structured == nil {
 		t.Fatal("expected structured result")
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/history"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+const (
+	// threadURIPrefix is the URI prefix of the orm-mcp://threads/{id} resource.
+	threadURIPrefix = "orm-mcp://threads/"
+
+	// threadContextTurns is the number of earlier turns sent with a follow-up.
+	threadContextTurns = 3
+	// threadContextAnswerLen caps each earlier answer sent with a follow-up, in runes.
+	threadContextAnswerLen = 400
+)
+
+// threadURI returns the thread resource URI for a thread ID.
+func threadURI(threadID string) string {
+	return threadURIPrefix + url.PathEscape(threadID)
+}
+
+// askedQuestion is a question as the user asked it and as it is submitted to
+// O'Reilly Answers, which carries the earlier turns of its thread as context.
+type askedQuestion struct {
+	threadID  string // empty for a new thread
+	question  string
+	submitted string
+}
+
+// prepareQuestion resolves the thread of args. It returns an error message
+// when thread_id does not name a known thread.
+func (s *Server) prepareQuestion(args AskQuestionArgs) (askedQuestion, string) {
+	asked := askedQuestion{threadID: args.ThreadID, question: args.Question, submitted: args.Question}
+	if args.ThreadID == "" {
+		return asked, ""
+	}
+	var thread *history.Thread
+	if s.threadManager != nil {
+		thread = s.threadManager.GetThread(args.ThreadID)
+	}
+	if thread == nil {
+		return asked, fmt.Sprintf("thread %q not found. Omit thread_id to start a new conversation.", args.ThreadID)
+	}
+	asked.submitted = followupQuestion(thread, args.Question)
+	return asked, ""
+}
+
+// followupQuestion prefixes question with the last turns of thread so that
+// O'Reilly Answers, which keeps no conversation state, can resolve references
+// to earlier questions.
+func followupQuestion(thread *history.Thread, question string) string {
+	var b strings.Builder
+	b.WriteString("Earlier in this conversation:\n")
+	for _, turn := range thread.Turns[max(0, len(thread.Turns)-threadContextTurns):] {
+		answer := truncateRunes(strings.Join(strings.Fields(turn.Answer), " "), threadContextAnswerLen)
+		if answer == "" {
+			answer = "(not answered)" // an async question still pending or failed
+		}
+		fmt.Fprintf(&b, "Q: %s\nA: %s\n", turn.Question, answer)
+	}
+	fmt.Fprintf(&b, "\nFollow-up question: %s", question)
+	return b.String()
+}
+
+// recordThreadTurn records an answered question to its thread and returns the
+// thread ID, or "" when threads are not available.
+func (s *Server) recordThreadTurn(asked askedQuestion, answer *browser.AnswerResponse) string {
+	if s.threadManager == nil {
+		return ""
+	}
+	data := answer.MisoResponse.Data
+	turn := history.Turn{
+		QuestionID:        answer.QuestionID,
+		Answer:            data.Answer,
+		Sources:           make([]history.ThreadSource, 0, len(data.Sources)),
+		FollowupQuestions: data.FollowupQuestions,
+	}
+	for _, src := range data.Sources {
+		turn.Sources = append(turn.Sources, history.ThreadSource{Title: src.Title, URL: src.URL, Authors: src.Authors, Excerpt: src.Excerpt})
+	}
+	return s.saveThreadTurn(asked, turn)
+}
+
+// saveThreadTurn fills in the question of turn, adds it to the thread of
+// asked, replacing a pending turn for the same question, and saves the threads.
+func (s *Server) saveThreadTurn(asked askedQuestion, turn history.Turn) string {
+	if s.threadManager == nil {
+		return ""
+	}
+	turn.Question = asked.question
+	if asked.submitted != asked.question {
+		turn.SubmittedQuestion = asked.submitted
+	}
+
+	threadID := s.threadManager.AppendTurn(asked.threadID, turn)
+	if err := s.threadManager.Save(); err != nil {
+		slog.Warn("会話スレッドの保存に失敗しました", "thread_id", threadID, "error", err)
+	}
+	return threadID
+}
+
+// recordPendingTurn records a question submitted asynchronously as a pending
+// turn, so that its thread exists before the answer does. It returns the
+// thread ID, or "" when threads are not available.
+func (s *Server) recordPendingTurn(asked askedQuestion, questionID string) string {
+	return s.saveThreadTurn(asked, history.Turn{QuestionID: questionID, Sources: []history.ThreadSource{}, Pending: true})
+}
+
+// recordFailedTurn marks the pending turn of an async question whose answer could not be fetched.
+func (s *Server) recordFailedTurn(asked askedQuestion, questionID string, err error) {
+	s.saveThreadTurn(asked, history.Turn{QuestionID: questionID, Sources: []history.ThreadSource{}, Error: err.Error()})
+}
+
+// GetThreadResource returns a conversation thread with every question, answer and source.
+func (s *Server) GetThreadResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	if s.threadManager == nil {
+		return paramErrorResult(uri, "answer threads are not available"), nil
+	}
+	id := mcputil.ExtractProductIDFromURI(uri)
+	if id == "" {
+		return paramErrorResult(uri, "thread id not found in URI"), nil
+	}
+	thread := s.threadManager.GetThread(id)
+	slog.Info("会話スレッド取得完了", "thread_id", id, "found", thread != nil)
+	if thread == nil {
+		return paramErrorResult(uri, "thread not found: "+id), nil
+	}
+
+	jsonBytes, err := json.Marshal(thread)
+	if err != nil {
+		return errH.ResourceContents(uri, err, "operation", "marshal_thread"), nil
+	}
+	return jsonResourceResult(uri, jsonBytes), nil
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/history"
+)
+
+func answerWithSource(id, text, source string) *browser.AnswerResponse {
+	return &browser.AnswerResponse{
+		QuestionID: id,
+		IsFinished: true,
+		MisoResponse: browser.MisoResponse{Data: browser.AnswerData{
+			Answer:  text,
+			Sources: []browser.AnswerSource{{Title: source, URL: "https://learning.oreilly.com/library/view/-/" + id + "/"}},
+		}},
+	}
+}
+
+func callAsk(t *testing.T, session *mcp.ClientSession, args map[string]any) map[string]any {
+	t.Helper()
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "oreilly_ask_question", Arguments: args})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "unexpected error: %v", res.Content)
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	return structured
+}
+
+func TestAskQuestionHandler_Threads(t *testing.T) {
+	mock := &mockBrowserClient{askAnswer: answerWithSource("q1", "Raft is a consensus algorithm.", "Designing Data-Intensive Applications")}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	first := callAsk(t, session, map[string]any{"question": "What is Raft?"})
+	threadID, _ := first["thread_id"].(string)
+	require.NotEmpty(t, threadID, "every answer starts a thread")
+	assert.Equal(t, "orm-mcp://threads/"+threadID, first["thread_uri"])
+
+	mock.askAnswer = answerWithSource("q2", "A candidate needs votes from a majority.", "Database Internals")
+	second := callAsk(t, session, map[string]any{"question": "How are leaders elected?", "thread_id": threadID})
+	assert.Equal(t, threadID, second["thread_id"])
+
+	require.Len(t, mock.questions, 2)
+	assert.Equal(t, "What is Raft?", mock.questions[0])
+	assert.Equal(t, "Earlier in this conversation:\nQ: What is Raft?\nA: Raft is a consensus algorithm.\n\nFollow-up question: How are leaders elected?", mock.questions[1])
+
+	var thread history.Thread
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "orm-mcp://threads/"+threadID).Text), &thread))
+	require.Len(t, thread.Turns, 2)
+	assert.Equal(t, "How are leaders elected?", thread.Turns[1].Question)
+	assert.Equal(t, mock.questions[1], thread.Turns[1].SubmittedQuestion)
+	assert.Empty(t, thread.Turns[0].SubmittedQuestion)
+	assert.Equal(t, "Database Internals", thread.Turns[1].Sources[0].Title)
+}
+
+func TestAskQuestionHandler_AsyncThread(t *testing.T) {
+	mock := &mockBrowserClient{
+		submitResp: &browser.QuestionResponse{QuestionID: "q-async"},
+		askAnswer:  answerWithSource("q-async", "Use fencing tokens.", "Designing Data-Intensive Applications"),
+	}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	structured := callAsk(t, session, map[string]any{"question": "How to avoid split brain?", "async": true})
+	threadID, _ := structured["thread_id"].(string)
+	require.NotEmpty(t, threadID, "async answers get a thread ID up front")
+
+	assert.Eventually(t, func() bool {
+		thread := srv.threadManager.GetThread(threadID)
+		return thread != nil && len(thread.Turns) == 1 && thread.Turns[0].Answer == "Use fencing tokens."
+	}, time.Second, 10*time.Millisecond, "the finished answer is recorded to the thread")
+}
+
+func TestAskQuestionHandler_AsyncThreadFollowupBeforeAnswer(t *testing.T) {
+	release := make(chan struct{})
+	mock := &mockBrowserClient{
+		submitResp:  &browser.QuestionResponse{QuestionID: "q-async"},
+		askAnswer:   answerWithSource("q-async", "Use fencing tokens.", "Designing Data-Intensive Applications"),
+		waitRelease: release,
+	}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	structured := callAsk(t, session, map[string]any{"question": "How to avoid split brain?", "async": true})
+	threadID := structured["thread_id"].(string)
+
+	thread := srv.threadManager.GetThread(threadID)
+	require.NotNil(t, thread, "the thread exists as soon as the question is submitted")
+	require.Len(t, thread.Turns, 1)
+	assert.True(t, thread.Turns[0].Pending)
+
+	mock.submitResp = &browser.QuestionResponse{QuestionID: "q-followup"}
+	followup := callAsk(t, session, map[string]any{"question": "And with leases?", "thread_id": threadID, "async": true})
+	assert.Equal(t, threadID, followup["thread_id"])
+	assert.Contains(t, mock.questions[1], "How to avoid split brain?")
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		thread := srv.threadManager.GetThread(threadID)
+		return thread != nil && len(thread.Turns) == 2 && !thread.Turns[0].Pending && thread.Turns[0].Answer == "Use fencing tokens."
+	}, time.Second, 10*time.Millisecond, "the pending turn is replaced by the answer")
+}
+
+func TestAskQuestionHandler_AsyncThreadFailedPoll(t *testing.T) {
+	mock := &mockBrowserClient{
+		submitResp: &browser.QuestionResponse{QuestionID: "q-async"},
+		askErr:     errors.New("timed out"),
+	}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	structured := callAsk(t, session, map[string]any{"question": "How to avoid split brain?", "async": true})
+	threadID := structured["thread_id"].(string)
+
+	assert.Eventually(t, func() bool {
+		thread := srv.threadManager.GetThread(threadID)
+		return thread != nil && len(thread.Turns) == 1 && !thread.Turns[0].Pending && thread.Turns[0].Error == "timed out"
+	}, time.Second, 10*time.Millisecond, "the thread keeps the question with the failure")
+}
+
+func TestAskQuestionHandler_UnknownThread(t *testing.T) {
+	mock := &mockBrowserClient{}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "And then?", "thread_id": "thr_missing"},
+	})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, `thread "thr_missing" not found`)
+	assert.Empty(t, mock.questions, "nothing is submitted")
+}
+
+func TestThreadResource_NotFound(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{})
+	session := connectTestSession(t, srv, nil)
+
+	assert.Contains(t, readResourceText(t, session, "orm-mcp://threads/thr_missing").Text, "thread not found: thr_missing")
+}
+
+func TestFollowupQuestion_BoundsContext(t *testing.T) {
+	thread := &history.Thread{}
+	for i := 1; i <= 5; i++ {
+		thread.Turns = append(thread.Turns, history.Turn{Question: fmt.Sprintf("Q%d?", i), Answer: strings.Repeat("word ", 200)})
+	}
+
+	got := followupQuestion(thread, "Q6?")
+	assert.NotContains(t, got, "Q2?", "only the last turns are sent")
+	assert.Contains(t, got, "Q3?")
+	assert.True(t, strings.HasSuffix(got, "Follow-up question: Q6?"))
+	assert.Equal(t, 3, strings.Count(got, "..."), "long answers are truncated")
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+// tocViewTree is the book-toc "view" query value that selects the nested hierarchy.
+const tocViewTree = "tree"
+
+// TOCTreeNode is a node of the hierarchical table of contents.
+type TOCTreeNode struct {
+	ID            string         `json:"id"`
+	Title         string         `json:"title"`
+	Href          string         `json:"href"`
+	Depth         int            `json:"depth"`                    // 1 for top-level entries
+	ChapterNumber string         `json:"chapter_number,omitempty"` // e.g. "3", "3.2" or "A", taken from the title label
+	URI           string         `json:"uri,omitempty"`            // oreilly://book-chapter/... for this entry
+	Children      []*TOCTreeNode `json:"children,omitempty"`
+}
+
+// TOCTreeResult is the response of the book-toc resource with ?view=tree.
+type TOCTreeResult struct {
+	BookID        string         `json:"book_id"`
+	BookTitle     string         `json:"book_title,omitempty"`
+	TotalChapters int            `json:"total_chapters"`
+	Tree          []*TOCTreeNode `json:"tree"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+}
+
+// buildTOCTree reconstructs the TOC hierarchy from the flattened list.
+// Items are in document order, so each item's parent is the nearest open
+// ancestor whose ID matches its Parent; unknown parents become top-level nodes.
+func buildTOCTree(toc *browser.TableOfContentsResponse) []*TOCTreeNode {
+	roots := []*TOCTreeNode{}
+	var stack []*TOCTreeNode
+
+	for _, item := range toc.TableOfContents {
+		for len(stack) > 0 && (item.Parent == "" || stack[len(stack)-1].ID != item.Parent) {
+			stack = stack[:len(stack)-1]
+		}
+
+		node := &TOCTreeNode{
+			ID:            item.ID,
+			Title:         item.Title,
+			Href:          item.Href,
+			Depth:         len(stack) + 1,
+			ChapterNumber: browser.ChapterNumberFromTitle(item.Title),
+		}
+		if item.Href != "" {
+			node.URI = mcputil.BookChapterURI(toc.BookID, item.Href)
+		}
+
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+	}
+	return roots
+}
+
+// encodeTOCTree renders a *browser.TableOfContentsResponse as a hierarchical JSON tree.
+func encodeTOCTree(uri string, data any) (*mcp.ReadResourceResult, error) {
+	toc, ok := data.(*browser.TableOfContentsResponse)
+	if !ok || toc == nil {
+		return nil, fmt.Errorf("unexpected TOC data type %T", data)
+	}
+	return encodeJSON(uri, &TOCTreeResult{
+		BookID:        toc.BookID,
+		BookTitle:     toc.BookTitle,
+		TotalChapters: toc.TotalChapters,
+		Tree:          buildTOCTree(toc),
+		Metadata:      toc.Metadata,
+	})
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+)
+
+func newNestedTOC() *browser.TableOfContentsResponse {
+	return &browser.TableOfContentsResponse{
+		BookID:    "123",
+		BookTitle: "Designing Data Systems",
+		TableOfContents: []browser.TableOfContentsItem{
+			{ID: "123-/preface.html", Title: "Preface", Href: "preface.html", Level: 0},
+			{ID: "123-/part01.html", Title: "Part I. Foundations", Href: "part01.html", Level: 0},
+			{ID: "123-/ch01.html", Title: "1. Choosing a Datastore", Href: "ch01.html", Level: 1, Parent: "123-/part01.html"},
+			{ID: "123-/ch01.html#sec1", Title: "1.1 Access Patterns", Href: "ch01.html#sec1", Level: 2, Parent: "123-/ch01.html"},
+			{ID: "123-/ch02.html", Title: "Chapter 2. Replication", Href: "ch02.html", Level: 1, Parent: "123-/part01.html"},
+			{ID: "123-/app01.html", Title: "Appendix A. Glossary", Href: "app01.html", Level: 0},
+		},
+		TotalChapters: 6,
+	}
+}
+
+func TestBuildTOCTree(t *testing.T) {
+	tree := buildTOCTree(newNestedTOC())
+
+	require.Len(t, tree, 3)
+	assert.Equal(t, "Preface", tree[0].Title)
+	assert.Equal(t, "", tree[0].ChapterNumber)
+	assert.Equal(t, "A", tree[2].ChapterNumber)
+
+	part := tree[1]
+	assert.Equal(t, 1, part.Depth)
+	require.Len(t, part.Children, 2)
+
+	ch1 := part.Children[0]
+	assert.Equal(t, 2, ch1.Depth)
+	assert.Equal(t, "1", ch1.ChapterNumber)
+	assert.Equal(t, "oreilly://book-chapter/123/ch01.html", ch1.URI)
+	require.Len(t, ch1.Children, 1)
+	assert.Equal(t, 3, ch1.Children[0].Depth)
+	assert.Equal(t, "1.1", ch1.Children[0].ChapterNumber)
+	assert.Equal(t, "oreilly://book-chapter/123/ch01.html%23sec1", ch1.Children[0].URI)
+
+	assert.Equal(t, "2", part.Children[1].ChapterNumber)
+	assert.Empty(t, part.Children[1].Children)
+}
+
+func TestBuildTOCTree_UnknownParentBecomesRoot(t *testing.T) {
+	tree := buildTOCTree(&browser.TableOfContentsResponse{
+		BookID: "123",
+		TableOfContents: []browser.TableOfContentsItem{
+			{ID: "a", Title: "A", Href: "a.html"},
+			{ID: "b", Title: "B", Href: "b.html", Parent: "missing"},
+		},
+	})
+	require.Len(t, tree, 2)
+	assert.Equal(t, 1, tree[1].Depth)
+}
+
+func TestBookTOCResource_TreeView(t *testing.T) {
+	srv := newTestServer(t, &mockBrowserClient{toc: newNestedTOC()})
+	session := connectTestSession(t, srv, nil)
+
+	var flat browser.TableOfContentsResponse
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-toc/123").Text), &flat))
+	assert.Len(t, flat.TableOfContents, 6)
+
+	var got TOCTreeResult
+	require.NoError(t, json.Unmarshal([]byte(readResourceText(t, session, "oreilly://book-toc/123?view=tree").Text), &got))
+	assert.Equal(t, "123", got.BookID)
+	assert.Equal(t, 6, got.TotalChapters)
+	require.Len(t, got.Tree, 3)
+	require.Len(t, got.Tree[1].Children, 2)
+	assert.Equal(t, "oreilly://book-chapter/123/ch02.html", got.Tree[1].Children[1].URI)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/cache"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
 )
 
 // SearchContentHandler handles search requests.
@@ -43,6 +45,13 @@ func (s *Server) SearchContentHandler(ctx context.Context, req *mcp.CallToolRequ
 	if len(args.Languages) == 0 {
 		args.Languages = []string{"en", "ja"}
 	}
+	if !browser.IsValidSortOrder(args.Sort) {
+		return newToolResultError(fmt.Sprintf("invalid sort %q: use one of %s", args.Sort, strings.Join(browser.SortOrders, ", "))), nil, nil
+	}
+	filter, err := searchFilterFromArgs(args)
+	if err != nil {
+		return newToolResultError(err.Error()), nil, nil
+	}
 
 	// Prepare options for BrowserClient
 	options := map[string]any{
@@ -55,10 +64,28 @@ func (s *Server) SearchContentHandler(ctx context.Context, req *mcp.CallToolRequ
 		"report":        args.Report,
 		"isTopics":      args.IsTopics,
 	}
+	if len(args.ContentTypes) > 0 {
+		options["content_types"] = args.ContentTypes
+	}
+	if len(args.Publishers) > 0 {
+		options["publishers"] = args.Publishers
+	}
+	if len(args.Authors) > 0 {
+		options["authors"] = args.Authors
+	}
+	if args.PublishedAfter != "" {
+		options["published_after"] = args.PublishedAfter
+	}
+	if args.PublishedBefore != "" {
+		options["published_before"] = args.PublishedBefore
+	}
+	if args.Sort != "" {
+		options["sort"] = args.Sort
+	}
 
 	// Execute search using BrowserClient
 	slog.Debug("BrowserClient検索開始", "query", args.Query, "offset", args.Offset, "rows", args.Rows)
-	results, totalResults, err := s.getBrowserClient().SearchContent(args.Query, options)
+	results, totalResults, err := s.getBrowserClient().SearchContent(ctx, args.Query, options)
 	if err != nil && errH.IsAuth(err) {
 		// Attempt re-authentication
 		slog.Info("認証エラー検出: 再認証を試みます")
@@ -67,11 +94,18 @@ func (s *Server) SearchContentHandler(ctx context.Context, req *mcp.CallToolRequ
 		}
 
 		// Retry
-		results, totalResults, err = s.getBrowserClient().SearchContent(args.Query, options)
+		results, totalResults, err = s.getBrowserClient().SearchContent(ctx, args.Query, options)
 	}
 	if err != nil {
 		return newToolResultError(errH.Sanitize(err, "operation", "search", "query", args.Query)), nil, nil
 	}
+	// APIが対応していない条件（著者・出版日など）をクライアント側で適用する。
+	// ページングは API から取得した件数で進める
+	fetched := len(results)
+	results = browser.FilterSearchResults(results, filter)
+	if len(results) != fetched {
+		slog.Debug("検索結果をフィルタしました", "before", fetched, "after", len(results))
+	}
 	slog.Info("検索完了", "query", args.Query, "result_count", len(results), "total_results", totalResults)
 	sessionLog.InfoContext(ctx, "検索完了", "query", args.Query, "result_count", len(results), "total_results", totalResults)
 
@@ -81,17 +115,30 @@ func (s *Server) SearchContentHandler(ctx context.Context, req *mcp.CallToolRequ
 	// Save full results to cache file (single save with history ID)
 	cacheDir := s.config.XDGDirs.ResponseCachePath()
 	filePath, cacheErr := cache.SaveResponseAsMarkdown(cache.SaveParams{
-		Dir: cacheDir, Query: args.Query, Results: results, HistoryID: historyID, TotalResults: totalResults,
+		Dir: cacheDir, Query: args.Query, Results: results, HistoryID: historyID, TotalResults: totalResults, Sort: args.Sort,
 	})
 	if cacheErr != nil {
 		slog.Warn("レスポンスキャッシュの保存に失敗しました", "error", cacheErr)
 	}
 
+	// Summarize with the client's model when requested (falls back to a note)
+	var summary, summaryNote string
+	if args.Summarize {
+		summary, summaryNote = s.summarizeSearch(ctx, req.Session, args.Query, results)
+	}
+
 	// Record to research history (pass pre-generated historyID)
-	s.recordSearchHistory(args.Query, options, results, filePath, time.Since(start), historyID)
+	s.recordSearchHistory(args.Query, options, results, filePath, time.Since(start), historyID, summary)
 
 	// Build lightweight response
-	toolResult, structured := s.buildLightweightResponse(results, historyID, filePath, args.Offset, totalResults)
+	toolResult, structured := s.buildLightweightResponse(results, historyID, filePath, args.Offset, fetched, totalResults)
+	if structured != nil {
+		structured.Summary = summary
+		structured.SummaryNote = summaryNote
+	}
+	if summary != "" && toolResult != nil {
+		toolResult.Content = append([]mcp.Content{&mcp.TextContent{Text: "Summary: " + summary}}, toolResult.Content...)
+	}
 
 	// Return Markdown format if requested
 	if args.Format == ResponseFormatMarkdown && structured != nil {
@@ -106,8 +153,10 @@ func (s *Server) SearchContentHandler(ctx context.Context, req *mcp.CallToolRequ
 // buildLightweightResponse builds a lightweight response with file path for lazy loading.
 // Book results include ResourceLink entries for direct resource navigation.
 // Returns up to 5 results in the text summary.
-func (s *Server) buildLightweightResponse(results []map[string]any, historyID, filePath string, offset, totalResults int) (*mcp.CallToolResult, *SearchContentResult) {
-	total := cache.EffectiveTotalResults(totalResults, len(results))
+// fetched is the number of results returned by the API before client-side filtering;
+// pagination advances by it so that filtered-out results are not requested again.
+func (s *Server) buildLightweightResponse(results []map[string]any, historyID, filePath string, offset, fetched, totalResults int) (*mcp.CallToolResult, *SearchContentResult) {
+	total := cache.EffectiveTotalResults(totalResults, fetched)
 
 	lightweightResults := make([]map[string]any, 0, len(results))
 	var resourceLinks []mcp.Content
@@ -138,14 +187,14 @@ func (s *Server) buildLightweightResponse(results []map[string]any, historyID, f
 				name = id
 			}
 			resourceLinks = append(resourceLinks, &mcp.ResourceLink{
-				URI:      "oreilly://book-details/" + id,
+				URI:      mcputil.BookDetailsURI(id),
 				Name:     name,
 				MIMEType: "application/json",
 			})
 		}
 	}
 
-	hasMore, nextOffset := calcPagination(offset, len(results), total)
+	hasMore, nextOffset := calcPagination(offset, fetched, total)
 
 	// Limit structured results for context efficiency
 	const inlineSummaryLimit = 5
@@ -218,6 +267,16 @@ func (s *Server) AskQuestionHandler(ctx context.Context, req *mcp.CallToolReques
 		}
 		maxWaitTime = time.Duration(args.MaxWaitTimeSeconds) * time.Second
 	}
+	if err := args.questionOptions().Validate(); err != nil {
+		return newToolResultError(err.Error()), nil, nil
+	}
+	if args.VerifySources && args.Async {
+		return newToolResultError("verify_sources needs the finished answer and cannot be combined with async."), nil, nil
+	}
+	asked, errMsg := s.prepareQuestion(args)
+	if errMsg != "" {
+		return newToolResultError(errMsg), nil, nil
+	}
 
 	// Check browser client
 	if s.getBrowserClient() == nil {
@@ -227,8 +286,24 @@ func (s *Server) AskQuestionHandler(ctx context.Context, req *mcp.CallToolReques
 	slog.Info("質問処理開始", "question", args.Question, "max_wait_time", maxWaitTime)
 	sessionLog.InfoContext(ctx, "質問処理開始", "question", args.Question, "max_wait_time", maxWaitTime)
 
-	// Execute question (with polling)
-	answer, err := s.getBrowserClient().AskQuestion(args.Question, maxWaitTime)
+	if !args.Async {
+		requested := time.Duration(args.MaxWaitTimeSeconds) * time.Second // 0 when the default wait applies
+		switch s.confirmLongQuestion(ctx, req.Session, args.Question, requested) {
+		case questionBackground:
+			args.Async = true
+		case questionCancel:
+			slog.Info("ユーザーが質問の送信を取り消しました", "question", args.Question)
+			return newToolResultError("The question was not submitted: the user chose not to ask it."), nil, nil
+		}
+	}
+
+	if args.Async {
+		return s.askQuestionAsync(ctx, args, asked, maxWaitTime, start)
+	}
+
+	// Execute question (with polling); stream partial answers if the client sent a progress token
+	onProgress := newAnswerProgressNotifier(ctx, req, maxWaitTime)
+	answer, err := s.getBrowserClient().AskQuestion(ctx, asked.submitted, args.questionOptions(), maxWaitTime, onProgress)
 	if err != nil {
 		return newToolResultError(errH.Sanitize(err, "operation", "ask_question", "question", args.Question)), nil, nil
 	}
@@ -236,8 +311,21 @@ func (s *Server) AskQuestionHandler(ctx context.Context, req *mcp.CallToolReques
 	slog.Info("質問に対する回答を取得しました", "question", args.Question, "question_id", answer.QuestionID)
 	sessionLog.InfoContext(ctx, "回答取得完了", "question", args.Question, "question_id", answer.QuestionID)
 
+	// Summarize with the client's model when requested (falls back to a note)
+	var summary, summaryNote string
+	if args.Summarize {
+		summary, summaryNote = s.summarizeAnswer(ctx, req.Session, args.Question, answer)
+	}
+
+	// Check the cited passages against the chapters when requested
+	var checks []SourceCheck
+	if args.VerifySources {
+		checks = s.verifySources(ctx, answer.MisoResponse.Data.Sources)
+	}
+
 	// Record to research history
-	s.recordQuestionHistory(args.Question, answer, time.Since(start))
+	s.recordQuestionHistory(args.Question, answer, time.Since(start), summary)
+	threadID := s.recordThreadTurn(asked, answer)
 
 	// Build StructuredContent response
 	structured := &AskQuestionResult{
@@ -249,23 +337,36 @@ func (s *Server) AskQuestionHandler(ctx context.Context, req *mcp.CallToolReques
 		RelatedResources:    answer.MisoResponse.Data.RelatedResources,
 		AffiliationProducts: answer.MisoResponse.Data.AffiliationProducts,
 		FollowupQuestions:   answer.MisoResponse.Data.FollowupQuestions,
+		SourceLinks:         answerSourceLinks(answer.MisoResponse.Data),
 		CitationNote:        "IMPORTANT: When referencing this information, always cite the sources listed above with proper attribution to O'Reilly Media.",
+		Summary:             summary,
+		SummaryNote:         summaryNote,
+		SourceChecks:        checks,
+	}
+	if threadID != "" {
+		structured.ThreadID = threadID
+		structured.ThreadURI = threadURI(threadID)
 	}
 
-	// Return Markdown format if requested
+	// Link every source to its oreilly:// resource, after the answer itself
+	var text string
 	if args.Format == ResponseFormatMarkdown {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: formatAskQuestionMarkdown(structured)}},
-		}, structured, nil
+		text = formatAskQuestionMarkdown(structured)
+	} else {
+		jsonBytes, err := json.Marshal(structured)
+		if err != nil {
+			return newToolResultError(errH.Sanitize(err, "operation", "marshal_answer")), nil, nil
+		}
+		text = string(jsonBytes)
 	}
-
-	return nil, structured, nil
+	content := append([]mcp.Content{&mcp.TextContent{Text: text}}, sourceResourceLinks(structured.SourceLinks)...)
+	return &mcp.CallToolResult{Content: content}, structured, nil
 }
 
 // ReauthenticateHandler handles the oreilly_reauthenticate MCP tool.
 // Cookie が有効ならそのまま返し、期限切れなら BrowserClient.Reauthenticate() で
 // ビジブルブラウザを起動して再認証します。
-// browserClient が nil の場合 (degraded モード) は NewBrowserClient() で
+// browserClient が nil の場合 (degraded モード) は ConnectBrowserClient() で
 // 新しい BrowserClient を生成します (内部でビジブルログインを実行)。
 func (s *Server) ReauthenticateHandler(
 	ctx context.Context,
@@ -274,16 +375,10 @@ func (s *Server) ReauthenticateHandler(
 ) (*mcp.CallToolResult, *ReauthResult, error) {
 	// degraded モード: browserClient が nil = サーバーが認証なしで起動した状態
 	if s.getBrowserClient() == nil {
-		slog.Info("oreilly_reauthenticate: degraded モード - NewBrowserClient で認証を開始します")
-		client, err := browser.NewBrowserClient(
-			s.cookieManager,
-			s.config.Debug.Enabled,
-			s.config.XDGDirs.StateHome,
-		)
-		if err != nil {
+		slog.Info("oreilly_reauthenticate: degraded モード - ConnectBrowserClient で認証を開始します")
+		if err := s.ConnectBrowserClient(); err != nil {
 			return newToolResultError(errH.Sanitize(err, "operation", "create_browser_client")), nil, nil
 		}
-		s.setBrowserClient(client)
 		return nil, &ReauthResult{
 			Status:  "setup_completed",
 			Message: "再認証が完了しました。O'Reilly セッションが更新されました。",
@@ -291,7 +386,7 @@ func (s *Server) ReauthenticateHandler(
 	}
 
 	// 通常モード: 1. 現在の Cookie で認証チェック
-	if err := s.getBrowserClient().CheckAndResetAuth(); err == nil {
+	if err := s.getBrowserClient().CheckAndResetAuth(ctx); err == nil {
 		return nil, &ReauthResult{
 			Status:  "authenticated",
 			Message: "O'Reilly セッションは有効です。",
@@ -303,6 +398,8 @@ func (s *Server) ReauthenticateHandler(
 	if err := s.getBrowserClient().Reauthenticate(); err != nil {
 		return newToolResultError(errH.Sanitize(err, "operation", "reauthenticate")), nil, nil
 	}
+	// 新しいセッションでは閲覧できる書籍が変わりうるため、キャッシュ済みの書籍情報を破棄します
+	s.invalidateBookCache("")
 
 	return nil, &ReauthResult{
 		Status:  "setup_completed",
@@ -3,6 +3,7 @@ package server
 import (
 	"github.com/google/uuid"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
 )
 
 // ResponseFormat defines the output format for tool results.
@@ -20,6 +21,7 @@ const (
 	maxQueryLength    = 500
 	maxQuestionLength = 500
 	maxRows           = 100
+	maxLocalResults   = 50
 )
 
 // SearchContentArgs represents the parameters for the oreilly_search_content tool.
@@ -33,11 +35,23 @@ type SearchContentArgs struct {
 	Report       bool     `json:"report,omitempty" jsonschema:"Include reporting data (default: true)"`
 	IsTopics     bool     `json:"isTopics,omitempty" jsonschema:"Search topics only (default: false)"`
 
+	// Filter parameters
+	ContentTypes    []string `json:"content_types,omitempty" jsonschema:"Restrict to content types, e.g. book, video"`
+	Publishers      []string `json:"publishers,omitempty" jsonschema:"Restrict to publishers (case-insensitive substring match)"`
+	Authors         []string `json:"authors,omitempty" jsonschema:"Restrict to authors (case-insensitive substring match)"`
+	PublishedAfter  string   `json:"published_after,omitempty" jsonschema:"Only content published on or after this date (YYYY, YYYY-MM or YYYY-MM-DD)"`
+	PublishedBefore string   `json:"published_before,omitempty" jsonschema:"Only content published on or before this date (YYYY, YYYY-MM or YYYY-MM-DD)"`
+
+	// Sort order
+	Sort string `json:"sort,omitempty" jsonschema:"Sort order: relevance (default), newest, oldest, popularity or title. If the API ignores it, newest/oldest/title reorder only the returned page and popularity keeps the API order"`
+
 	// Pagination parameters
 	Offset int `json:"offset,omitempty" jsonschema:"Pagination offset (0-based, default: 0)"`
 
 	// Response format
 	Format ResponseFormat `json:"format,omitempty" jsonschema:"Output format: 'json' (default) or 'markdown' for human-readable output"`
+
+	Summarize bool `json:"summarize,omitempty" jsonschema:"Add a short digest of the results written by the client's model via MCP sampling (default: false)"`
 }
 
 // AskQuestionArgs represents the parameters for the oreilly_ask_question tool.
@@ -45,6 +59,95 @@ type AskQuestionArgs struct {
 	Question           string         `json:"question" jsonschema:"Focused technical question in English (under 100 characters preferred),minLength=1,maxLength=500"`
 	MaxWaitTimeSeconds int            `json:"max_wait_time_seconds,omitempty" jsonschema:"Maximum time to wait for answer generation in seconds (default: 300, max: 600)"`
 	Format             ResponseFormat `json:"format,omitempty" jsonschema:"Output format: 'json' (default) or 'markdown' for human-readable output"`
+	Async              bool           `json:"async,omitempty" jsonschema:"Return question_id and answer URI immediately; the answer is generated in the background (default: false)"`
+	ThreadID           string         `json:"thread_id,omitempty" jsonschema:"Ask a follow-up in this conversation (thread_id from a previous answer); earlier questions and answers are sent as context"`
+	Summarize          bool           `json:"summarize,omitempty" jsonschema:"Add a short digest of the answer written by the client's model via MCP sampling (default: false, not with async)"`
+	VerifySources      bool           `json:"verify_sources,omitempty" jsonschema:"Check each source excerpt against the cited chapter text and report verified, matching section and chapter URI (default: false, not with async)"`
+
+	// Scope parameters
+	ProductIDs   []string `json:"product_ids,omitempty" jsonschema:"Answer only from these books (product_id from oreilly_search_content). Bare IDs are always books; pass the ourn for videos and articles"`
+	ContentTypes []string `json:"content_types,omitempty" jsonschema:"Answer only from these content types: book, video, article"`
+	Publishers   []string `json:"publishers,omitempty" jsonschema:"Answer only from these publishers (exact name, e.g. O'Reilly Media, Inc.)"`
+
+	// Tuning parameters
+	SnippetLength   int `json:"snippet_length,omitempty" jsonschema:"Length of source excerpts used for the answer (default: 500),minimum=0"`
+	HighlightLength int `json:"highlight_length,omitempty" jsonschema:"Length of highlighted passages (default: 200),minimum=0"`
+}
+
+// questionOptions returns the scope and tuning of the question.
+func (a AskQuestionArgs) questionOptions() browser.QuestionOptions {
+	return browser.QuestionOptions{
+		ProductIDs:      a.ProductIDs,
+		ContentTypes:    a.ContentTypes,
+		Publishers:      a.Publishers,
+		SnippetLength:   a.SnippetLength,
+		HighlightLength: a.HighlightLength,
+	}
+}
+
+// SearchLocalArgs represents the parameters for the oreilly_search_local tool.
+type SearchLocalArgs struct {
+	Query     string         `json:"query" jsonschema:"Words or phrase to find inside chapters already fetched (English or Japanese),minLength=1,maxLength=500"`
+	ProductID string         `json:"product_id,omitempty" jsonschema:"Only search sections of this book"`
+	Limit     int            `json:"limit,omitempty" jsonschema:"Maximum number of sections to return (default: 10, max: 50),minimum=1,maximum=50"`
+	Format    ResponseFormat `json:"format,omitempty" jsonschema:"Output format: 'json' (default) or 'markdown' for human-readable output"`
+}
+
+// SearchLocalHit is a matching chapter section with links to read it.
+type SearchLocalHit struct {
+	fulltext.Hit
+	ChapterURI string `json:"chapter_uri"`
+	SectionURI string `json:"section_uri"`
+}
+
+// SearchLocalResult represents the structured output for the oreilly_search_local tool.
+type SearchLocalResult struct {
+	Query   string           `json:"query"`
+	Count   int              `json:"count"`
+	Total   int              `json:"total"` // matching sections before the limit
+	Results []SearchLocalHit `json:"results"`
+	Indexed fulltext.Stats   `json:"indexed"` // what the local index covers
+}
+
+// ResolveArgs represents the parameters for the oreilly_resolve tool.
+type ResolveArgs struct {
+	Input string `json:"input" jsonschema:"O'Reilly URL, ISBN-10 or ISBN-13 (dashes allowed), OURN like urn:orm:book:9781491903063, oreilly:// URI or product_id,minLength=1,maxLength=500"`
+}
+
+// ResolveResult represents the structured output for the oreilly_resolve tool.
+type ResolveResult struct {
+	Input       string `json:"input"`
+	ProductID   string `json:"product_id"` // canonical ID for oreilly://book-* resources
+	ContentType string `json:"content_type,omitempty"`
+	Title       string `json:"title,omitempty"` // books only; videos and courses are not looked up
+	ISBN        string `json:"isbn,omitempty"`
+	DetailsURI  string `json:"details_uri,omitempty"` // books only
+	TOCURI      string `json:"toc_uri,omitempty"`     // books only
+	ChapterName string `json:"chapter_name,omitempty"`
+	ChapterURI  string `json:"chapter_uri,omitempty"` // set when the input points inside a book
+}
+
+// ExportCitationsArgs represents the parameters for the oreilly_export_citations tool.
+type ExportCitationsArgs struct {
+	QuestionID string `json:"question_id,omitempty" jsonschema:"question_id from oreilly_ask_question; cites the answer's sources"`
+	HistoryID  string `json:"history_id,omitempty" jsonschema:"Research history entry ID; cites a search's top results or a question's sources"`
+	Style      string `json:"style,omitempty" jsonschema:"Citation style: apa (default), chicago, bibtex or csl-json"`
+}
+
+// ExportCitationsResult represents the structured output for the oreilly_export_citations tool.
+type ExportCitationsResult struct {
+	Style      string   `json:"style"`
+	MIMEType   string   `json:"mime_type"`
+	Count      int      `json:"count"`
+	Citations  string   `json:"citations"`            // all works rendered in style
+	Incomplete []string `json:"incomplete,omitempty"` // titles cited from the source alone because their details could not be fetched
+}
+
+// ExportBookArgs represents the parameters for the oreilly_export_book tool.
+type ExportBookArgs struct {
+	ProductID string `json:"product_id" jsonschema:"Book product_id from oreilly_search_content,minLength=1"`
+	OutputDir string `json:"output_dir,omitempty" jsonschema:"Subdirectory of the server's export directory to write the book to; relative paths are resolved against it (default: the product_id)"`
+	Force     bool   `json:"force,omitempty" jsonschema:"Re-export chapters finished by a previous run instead of resuming (default: false)"`
 }
 
 // SearchContentResult represents the structured output for oreilly_search_content tool.
@@ -60,6 +163,9 @@ type SearchContentResult struct {
 
 	HistoryID string `json:"history_id,omitempty"` // Research history ID
 	FilePath  string `json:"file_path,omitempty"`  // Path to cached Markdown file with full results
+
+	Summary     string `json:"summary,omitempty"`      // digest from MCP sampling (summarize=true)
+	SummaryNote string `json:"summary_note,omitempty"` // why summarize=true produced no summary
 }
 
 // calcPagination computes pagination state from offset, result count, and total results.
@@ -94,5 +200,12 @@ type AskQuestionResult struct {
 	RelatedResources    []browser.RelatedResource    `json:"related_resources"`
 	AffiliationProducts []browser.AffiliationProduct `json:"affiliation_products"`
 	FollowupQuestions   []string                     `json:"followup_questions"`
+	SourceLinks         []AnswerSourceLink           `json:"source_links"` // sources as oreilly:// resources
 	CitationNote        string                       `json:"citation_note"`
+	AnswerURI           string                       `json:"answer_uri,omitempty"` // oreilly://answer/{question_id} (async mode)
+	ThreadID            string                       `json:"thread_id,omitempty"`  // pass back as thread_id to ask a follow-up
+	ThreadURI           string                       `json:"thread_uri,omitempty"` // orm-mcp://threads/{thread_id}
+	Summary             string                       `json:"summary,omitempty"`    // digest from MCP sampling (summarize=true)
+	SummaryNote         string                       `json:"summary_note,omitempty"`
+	SourceChecks        []SourceCheck                `json:"source_checks,omitempty"` // one per source (verify_sources=true)
 }
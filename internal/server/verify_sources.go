@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/fulltext"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/mcputil"
+)
+
+const (
+	// verifyThreshold is the share of an excerpt's word triples that must occur
+	// in the chapter for the source to count as verified.
+	verifyThreshold = 0.6
+	// verifyConcurrency is the number of cited chapters fetched at once.
+	verifyConcurrency = 4
+	// verifyShingleSize is the number of consecutive words compared at a time;
+	// shorter excerpts are compared as one run of their own length.
+	verifyShingleSize = 3
+)
+
+// Reasons a source is not verified.
+const (
+	verifyReasonNoChapter = "source URL does not point to a book chapter"
+	verifyReasonNoExcerpt = "source has no excerpt to check"
+	verifyReasonFetch     = "chapter could not be fetched"
+	verifyReasonNoMatch   = "excerpt not found in the chapter text"
+)
+
+// markupPattern matches HTML tags left in Answers excerpts, e.g. highlight marks.
+var markupPattern = regexp.MustCompile(`<[^>]*>`)
+
+// SourceCheck is the verification of one Answers source against the text of the chapter it cites.
+type SourceCheck struct {
+	Title      string  `json:"title"`
+	URL        string  `json:"url"`
+	Verified   bool    `json:"verified"`
+	Score      float64 `json:"score"`             // share of the excerpt's word triples found in the chapter
+	Heading    string  `json:"heading,omitempty"` // section that best matches the excerpt
+	SectionURI string  `json:"section_uri,omitempty"`
+	ChapterURI string  `json:"chapter_uri,omitempty"`
+	Reason     string  `json:"reason,omitempty"` // why the source is not verified
+}
+
+// verifySources checks each source excerpt against its chapter, fetching each
+// cited chapter once. The checks are in the order of sources.
+func (s *Server) verifySources(ctx context.Context, sources []browser.AnswerSource) []SourceCheck {
+	checks := make([]SourceCheck, len(sources))
+	refs := make([]mcputil.WebResource, len(sources))
+	cited := map[string]mcputil.WebResource{} // chapter URI → chapter
+	for i, src := range sources {
+		checks[i] = SourceCheck{Title: src.Title, URL: src.URL}
+		ref, ok := mcputil.ResolveWebURL(src.URL)
+		if !ok || ref.ChapterName == "" || !ref.IsBook() {
+			checks[i].Reason = verifyReasonNoChapter
+			continue
+		}
+		refs[i] = ref
+		checks[i].ChapterURI = ref.URI()
+		cited[checks[i].ChapterURI] = ref
+	}
+
+	client := s.getBrowserClient()
+	chapters := map[string]*browser.ChapterContentResponse{} // chapter URI → content; missing when the fetch failed
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	sem := make(chan struct{}, verifyConcurrency)
+	for uri, ref := range cited {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			chapter, err := client.GetBookChapterContent(ctx, ref.ProductID, ref.ChapterName)
+			if err != nil {
+				slog.Warn("出典の検証でチャプターの取得に失敗しました", "product_id", ref.ProductID, "chapter", ref.ChapterName, "error", err)
+				return
+			}
+			mu.Lock()
+			chapters[uri] = chapter
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	verified := 0
+	for i, src := range sources {
+		if checks[i].ChapterURI == "" {
+			continue
+		}
+		chapter, ok := chapters[checks[i].ChapterURI]
+		if !ok {
+			checks[i].Reason = verifyReasonFetch
+			continue
+		}
+		tokens := fulltext.Tokenize(markupPattern.ReplaceAllString(src.Excerpt, " "))
+		if len(tokens) == 0 {
+			checks[i].Reason = verifyReasonNoExcerpt
+			continue
+		}
+		matchExcerpt(&checks[i], tokens, refs[i], chapter.Content.Sections)
+		if checks[i].Verified {
+			verified++
+		}
+	}
+	slog.Info("出典の検証完了", "sources", len(sources), "verified", verified)
+	return checks
+}
+
+// matchExcerpt scores the excerpt tokens against the whole chapter and records
+// the section sharing the most word runs with them.
+func matchExcerpt(check *SourceCheck, tokens []string, ref mcputil.WebResource, sections []htmlparse.ContentSection) {
+	n := min(verifyShingleSize, len(tokens))
+	excerpt := shingles(tokens, n)
+	found := map[string]bool{}
+	best, bestCount := -1, 0
+	for i, section := range sections {
+		text := section.Heading.Text + " " + htmlparse.SectionText(section)
+		count := 0
+		for shingle := range shingles(fulltext.Tokenize(text), n) {
+			if excerpt[shingle] {
+				found[shingle] = true
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+
+	check.Score = math.Round(float64(len(found))/float64(len(excerpt))*100) / 100
+	check.Verified = check.Score >= verifyThreshold
+	if best >= 0 {
+		check.Heading = sections[best].Heading.Text
+		check.SectionURI = chapterSectionURI(ref.ProductID, ref.ChapterName, best)
+	}
+	if !check.Verified {
+		check.Reason = verifyReasonNoMatch
+	}
+}
+
+// shingles returns the distinct runs of n consecutive tokens.
+func shingles(tokens []string, n int) map[string]bool {
+	set := map[string]bool{}
+	for i := 0; i+n <= len(tokens); i++ {
+		set[strings.Join(tokens[i:i+n], " ")] = true
+	}
+	return set
+}
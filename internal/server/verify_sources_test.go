@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
+	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/htmlparse"
+)
+
+const ddiaURL = "https://learning.oreilly.com/library/view/ddia/9781491903063/"
+
+func replicationChapter() *browser.ChapterContentResponse {
+	para := func(text string) any { return htmlparse.ParagraphElement{Type: "paragraph", Text: text} }
+	return &browser.ChapterContentResponse{
+		BookID:      "9781491903063",
+		ChapterName: "ch05.html",
+		Content: htmlparse.ParsedChapterContent{Sections: []htmlparse.ContentSection{
+			{Heading: htmlparse.ContentHeading{Level: 1, Text: "Replication"}, Content: []any{para("Replication means keeping a copy of the same data on multiple machines.")}},
+			{Heading: htmlparse.ContentHeading{Level: 2, Text: "Leaders and Followers"}, Content: []any{
+				para("Each node that stores a copy of the database is called a replica."),
+				para("Every write to the database needs to be processed by every replica; otherwise, the replicas would no longer contain the same data."),
+			}},
+		}},
+	}
+}
+
+func TestVerifySources(t *testing.T) {
+	mock := &mockBrowserClient{
+		chapter:     replicationChapter(),
+		chapterErrs: map[string]error{"ch99.html": errors.New("API request failed with status 404")},
+	}
+	srv := newTestServer(t, mock)
+
+	checks := srv.verifySources(context.Background(), []browser.AnswerSource{
+		{Title: "Verified", URL: ddiaURL + "ch05.html#leaders", Excerpt: "Every <em>write</em> to the database needs to be processed by every replica; otherwise..."},
+		{Title: "Same chapter, invented text", URL: ddiaURL + "ch05.html", Excerpt: "Raft elects a leader through randomized election timeouts."},
+		{Title: "Short excerpt", URL: ddiaURL + "ch05.html", Excerpt: "replica"},
+		{Title: "No excerpt", URL: ddiaURL + "ch05.html"},
+		{Title: "Missing chapter", URL: ddiaURL + "ch99.html", Excerpt: "anything"},
+		{Title: "Book landing page", URL: ddiaURL, Excerpt: "anything"},
+	})
+	require.Len(t, checks, 6)
+
+	assert.True(t, checks[0].Verified)
+	assert.Equal(t, 1.0, checks[0].Score)
+	assert.Equal(t, "Leaders and Followers", checks[0].Heading)
+	assert.Equal(t, "oreilly://book-chapter/9781491903063/ch05.html/section/1", checks[0].SectionURI)
+	assert.Equal(t, "oreilly://book-chapter/9781491903063/ch05.html", checks[0].ChapterURI)
+	assert.Empty(t, checks[0].Reason)
+
+	assert.False(t, checks[1].Verified)
+	assert.Equal(t, verifyReasonNoMatch, checks[1].Reason)
+	assert.Less(t, checks[1].Score, verifyThreshold)
+
+	assert.True(t, checks[2].Verified, "short excerpts are matched word by word")
+	assert.Equal(t, verifyReasonNoExcerpt, checks[3].Reason)
+	assert.Equal(t, verifyReasonFetch, checks[4].Reason)
+	assert.Equal(t, verifyReasonNoChapter, checks[5].Reason)
+	assert.Empty(t, checks[5].ChapterURI)
+
+	assert.ElementsMatch(t, []string{"ch05.html", "ch99.html"}, mock.chapterReqs, "each cited chapter is fetched once")
+}
+
+func TestAskQuestionHandler_VerifySources(t *testing.T) {
+	answer := answerWithSource("q1", "Writes go through every replica.", "Designing Data-Intensive Applications")
+	answer.MisoResponse.Data.Sources[0].URL = ddiaURL + "ch05.html"
+	answer.MisoResponse.Data.Sources[0].Excerpt = "Each node that stores a copy of the database is called a replica."
+	mock := &mockBrowserClient{askAnswer: answer, chapter: replicationChapter()}
+	srv := newTestServer(t, mock)
+	session := connectTestSession(t, srv, nil)
+
+	structured := callAsk(t, session, map[string]any{"question": "What is a replica?", "verify_sources": true})
+	checks, ok := structured["source_checks"].([]any)
+	require.True(t, ok)
+	require.Len(t, checks, 1)
+	check := checks[0].(map[string]any)
+	assert.Equal(t, true, check["verified"])
+	assert.Equal(t, "Leaders and Followers", check["heading"])
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "What is a replica?", "verify_sources": true, "format": "markdown"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text,
+		`— verified in "Leaders and Followers" (oreilly://book-chapter/9781491903063/ch05.html/section/1)`)
+}
+
+func TestAskQuestionHandler_VerifySourcesWithoutFlag(t *testing.T) {
+	mock := &mockBrowserClient{askAnswer: answerWithSource("q1", "answer", "Book")}
+	session := connectTestSession(t, newTestServer(t, mock), nil)
+
+	structured := callAsk(t, session, map[string]any{"question": "What is a replica?"})
+	assert.NotContains(t, structured, "source_checks")
+	assert.Empty(t, mock.chapterReqs, "chapters are only fetched on request")
+}
+
+func TestAskQuestionHandler_VerifySourcesAsync(t *testing.T) {
+	mock := &mockBrowserClient{submitResp: &browser.QuestionResponse{QuestionID: "q-async"}}
+	session := connectTestSession(t, newTestServer(t, mock), nil)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oreilly_ask_question",
+		Arguments: map[string]any{"question": "What is a replica?", "verify_sources": true, "async": true},
+	})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	assert.Empty(t, mock.questions, "nothing is submitted")
+}
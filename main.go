@@ -8,7 +8,6 @@ import (
 	"os/signal"
 	"syscall"
 
-	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/browser/cookie"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/config"
 	"github.com/usadamasa/orm-discovery-mcp-go/internal/server"
@@ -40,6 +39,15 @@ func main() {
 		return
 	}
 
+	// Handle --export flag (書籍を Markdown ディレクトリにエクスポート)
+	if len(os.Args) > 1 && os.Args[1] == "--export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	runMCPServer()
 }
 
@@ -82,18 +90,14 @@ func runMCPServer() {
 	}
 
 	// Create browser client and login (using StateHome for Chrome temp data)
-	// browser.Client インターフェースとして宣言し、エラー時は nil (interface nil) のまま渡す。
-	// typed nil (*BrowserClient(nil)) を渡すと == nil チェックが正しく動作しないため。
-	var browserClient browser.Client
-	bc, err := browser.NewBrowserClient(cookieManager, cfg.Debug.Enabled, cfg.XDGDirs.StateHome)
-	if err != nil {
+	// 失敗した場合は browserClient が nil の degraded モードで起動する。
+	s := server.NewServer(cfg, cookieManager, version)
+	if err := s.ConnectBrowserClient(); err != nil {
 		slog.Warn("ブラウザクライアントの初期化に失敗しました。degraded モードで起動します。"+
 			"oreilly_reauthenticate ツールで再認証してください。", "error", err)
 	} else {
-		browserClient = bc
 		slog.Info("ブラウザクライアントの初期化が完了しました")
 	}
-	s := server.NewServer(browserClient, cfg, cookieManager, version)
 	defer s.Close() // Clean up browser on process exit (includes clients created in degraded mode)
 
 	if cfg.Server.Transport == "http" {